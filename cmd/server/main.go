@@ -0,0 +1,125 @@
+package main
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/events"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/jobs"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/search"
+	"cms-headless/internal/services"
+	"cms-headless/internal/utils"
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db := utils.InitDB()
+	if err := models.Migrate(db); err != nil {
+		log.Fatalf("Falha ao migrar o banco: %v", err)
+	}
+	// Estruturas da busca textual (FTS5, tsvector) ficam fora do schema do models
+	if _, err := search.Setup(db); err != nil {
+		log.Fatalf("Falha ao preparar a busca textual: %v", err)
+	}
+
+	indexed, err := repositories.EnsureSearchIndex(db)
+	if err != nil {
+		log.Fatalf("Falha ao indexar conteúdo para busca: %v", err)
+	}
+	log.Printf("Busca textual: backend %s (%d itens indexados agora)", search.Backend(db), indexed)
+
+	postRepo := repositories.NewPostRepository(db)
+	projectRepo := repositories.NewProjectRepository(db)
+
+	mux := http.NewServeMux()
+	handlers.NewPublicHandler(postRepo, projectRepo).Register(mux)
+	handlers.NewSearchHandler(repositories.NewContentSearchRepository(db)).Register(mux)
+	handlers.NewLandingHandler(repositories.NewTagRepository(db), repositories.NewCategoryRepository(db), postRepo, projectRepo).Register(mux)
+
+	tokens := auth.ParseTokens(os.Getenv("ADMIN_TOKENS"))
+	if len(tokens) == 0 {
+		log.Printf("ADMIN_TOKENS vazio: a API administrativa recusará todas as requisições")
+	}
+	adminMux := http.NewServeMux()
+	postService, projectService := services.NewPostService(db), services.NewProjectService(db)
+	handlers.NewAdminHandler(postService, projectService).Register(adminMux)
+	handlers.NewRevisionHandler(services.NewRevisionService(db), postService, projectService).Register(adminMux)
+	handlers.NewTaxonomyHandler(services.NewTaxonomyService(db)).Register(adminMux)
+	handlers.NewWebhookHandler(services.NewWebhookService(db)).Register(adminMux)
+
+	// PREVIEW_SECRET vazio desativa o preview (emissão e leitura)
+	if secret := os.Getenv("PREVIEW_SECRET"); secret != "" {
+		maxTTL := time.Duration(envInt("PREVIEW_TOKEN_TTL_MINUTES", 60)) * time.Minute
+		preview := handlers.NewPreviewHandler([]byte(secret), maxTTL, postService, projectService)
+		preview.RegisterAdmin(adminMux)
+		preview.Register(mux)
+	} else {
+		log.Printf("PREVIEW_SECRET vazio: preview de rascunhos desativado")
+	}
+	mux.Handle("/admin/", auth.Middleware(tokens, adminMux))
+
+	// TRASH_RETENTION_DAYS=0 desativa o expurgo automático da lixeira
+	if days := envInt("TRASH_RETENTION_DAYS", 30); days > 0 {
+		retention := time.Duration(days) * 24 * time.Hour
+		go jobs.NewTrashRetention(postRepo, projectRepo, retention).Run(ctx, time.Hour)
+	}
+
+	// Eventos de publicação/retirada agendadas; o scheduler já os grava no outbox na
+	// própria transação, o bus fica só para o log
+	webhookRepo := repositories.NewWebhookRepository(db)
+	bus := events.NewBus()
+	bus.Subscribe(func(ctx context.Context, e events.Event) error {
+		log.Printf("Evento %s: %s %d (%s)", e.Type, e.ContentType, e.ContentID, e.Slug)
+		return nil
+	})
+	interval := time.Duration(envInt("PUBLISH_SCHEDULER_INTERVAL_SECONDS", 30)) * time.Second
+	go jobs.NewPublishScheduler(db, bus).Run(ctx, interval)
+
+	webhookClient := &http.Client{Timeout: 10 * time.Second}
+	go jobs.NewWebhookDispatcher(webhookRepo, webhookClient).Run(ctx, 5*time.Second)
+
+	addr := ":" + envOr("PORT", "8080")
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Servidor ouvindo em %s", addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("Falha no servidor HTTP: %v", err)
+	}
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Papéis do fluxo editorial: editor escreve e envia para revisão, reviewer aprova e
+// publica, admin pode tudo (inclusive arquivar)
+const (
+	RoleEditor   = "editor"
+	RoleReviewer = "reviewer"
+	RoleAdmin    = "admin"
+)
+
+// Usuário autenticado na API administrativa
+type Principal struct {
+	Name string
+	Role string
+}
+
+type contextKey struct{}
+
+// Interpreta ADMIN_TOKENS no formato "nome:token[:papel],nome2:token2".
+// Sem papel (formato antigo), o token é admin.
+func ParseTokens(raw string) map[string]Principal {
+	tokens := make(map[string]Principal)
+	for _, entry := range strings.Split(raw, ",") {
+		name, token, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || name == "" || token == "" {
+			continue
+		}
+		role := RoleAdmin
+		if i := strings.LastIndexByte(token, ':'); i >= 0 && isRole(token[i+1:]) {
+			token, role = token[:i], token[i+1:]
+		}
+		tokens[token] = Principal{Name: name, Role: role}
+	}
+	return tokens
+}
+
+func isRole(s string) bool {
+	return s == RoleEditor || s == RoleReviewer || s == RoleAdmin
+}
+
+// Exige "Authorization: Bearer <token>" válido e injeta o Principal no contexto
+func Middleware(tokens map[string]Principal, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		principal, found := lookup(tokens, strings.TrimSpace(token))
+		if !ok || !found {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"não autenticado"}` + "\n"))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
+
+// Comparação em tempo constante para não vazar o token por timing
+func lookup(tokens map[string]Principal, token string) (Principal, bool) {
+	if token == "" {
+		return Principal{}, false
+	}
+	sum := sha256.Sum256([]byte(token))
+	for candidate, p := range tokens {
+		c := sha256.Sum256([]byte(candidate))
+		if subtle.ConstantTimeCompare(sum[:], c[:]) == 1 {
+			return p, true
+		}
+	}
+	return Principal{}, false
+}
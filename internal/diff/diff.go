@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"strings"
+)
+
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Acima disso (linhas de a × linhas de b) a tabela do LCS ficaria grande demais;
+// devolvemos o texto inteiro como removido/inserido
+const maxCells = 4_000_000
+
+// Diff linha a linha de a para b pela maior subsequência comum (LCS)
+func Lines(a, b string) []Line {
+	x, y := splitLines(a), splitLines(b)
+	n, m := len(x), len(y)
+
+	if n*m > maxCells {
+		res := make([]Line, 0, n+m)
+		for _, l := range x {
+			res = append(res, Line{Op: Delete, Text: l})
+		}
+		for _, l := range y {
+			res = append(res, Line{Op: Insert, Text: l})
+		}
+		return res
+	}
+
+	// lcs[i][j] = tamanho da LCS entre x[i:] e y[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	res := make([]Line, 0, max(n, m))
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			res = append(res, Line{Op: Equal, Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, Line{Op: Delete, Text: x[i]})
+			i++
+		default:
+			res = append(res, Line{Op: Insert, Text: y[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		res = append(res, Line{Op: Delete, Text: x[i]})
+	}
+	for ; j < m; j++ {
+		res = append(res, Line{Op: Insert, Text: y[j]})
+	}
+	return res
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
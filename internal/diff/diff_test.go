@@ -0,0 +1,36 @@
+package diff_test
+
+import (
+	"cms-headless/internal/diff"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	t.Run("Deve marcar linhas alteradas mantendo as iguais", func(t *testing.T) {
+		res := diff.Lines("a\nb\nc", "a\nx\nc")
+
+		assert.Equal(t, []diff.Line{
+			{Op: diff.Equal, Text: "a"},
+			{Op: diff.Delete, Text: "b"},
+			{Op: diff.Insert, Text: "x"},
+			{Op: diff.Equal, Text: "c"},
+		}, res)
+	})
+
+	t.Run("Deve tratar texto vazio como inserção completa", func(t *testing.T) {
+		res := diff.Lines("", "um\ndois")
+
+		assert.Equal(t, []diff.Line{
+			{Op: diff.Insert, Text: "um"},
+			{Op: diff.Insert, Text: "dois"},
+		}, res)
+	})
+
+	t.Run("Deve devolver apenas linhas iguais para textos idênticos", func(t *testing.T) {
+		for _, l := range diff.Lines("a\r\nb", "a\nb") {
+			assert.Equal(t, diff.Equal, l.Op)
+		}
+	})
+}
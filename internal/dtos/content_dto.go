@@ -1,22 +1,83 @@
 package dtos
 
+import "time"
+
 // Input para criação/update via Postman
 type ContentInput struct {
-	Type             string `json:"type" binding:"required,oneof=project post"`
-	Title            string `json:"title" binding:"required,min=3"`
-	ShortDescription string `json:"short_description"`
-	Body             string `json:"body" binding:"required"`
-	DemoURL          string `json:"demo_url" validate:"url"`
-	RepoURL          string `json:"repo_url" validate:"url"`
+	Type             string   `json:"type" binding:"required,oneof=project post"`
+	Title            string   `json:"title" binding:"required,min=3"`
+	Slug             string   `json:"slug"` // Opcional: sobrescreve o slug gerado do título
+	ShortDescription string   `json:"short_description"`
+	Body             string   `json:"body" binding:"required"`
+	DemoURL          string   `json:"demo_url" validate:"url"`
+	RepoURL          string   `json:"repo_url" validate:"url"`
+	TagIDs           []uint   `json:"tag_ids"`      // nil mantém as tags atuais no update
+	TagNames         []string `json:"tag_names"`    // Somados a tag_ids; aliases viram a tag canônica e nomes novos criam tags
+	CategoryIDs      []uint   `json:"category_ids"` // nil mantém as categorias atuais no update
 }
 
 // Output limpo para o Next.js
 type ContentResponse struct {
-	ID               uint   `json:"id"`
-	Title            string `json:"title"`
-	Slug             string `json:"slug"`
-	ShortDescription string `json:"short_description"`
-	Body             string `json:"body"`
-	Type             string `json:"type"`
-	DemoURL          string `json:"demo_url,omitempty"`
+	ID               uint               `json:"id"`
+	Title            string             `json:"title"`
+	Slug             string             `json:"slug"`
+	ShortDescription string             `json:"short_description"`
+	Body             string             `json:"body"`
+	Type             string             `json:"type"`
+	DemoURL          string             `json:"demo_url,omitempty"`
+	RepoURL          string             `json:"repo_url,omitempty"`
+	PostedAt         *time.Time         `json:"posted_at,omitempty"`
+	UnpublishAt      *time.Time         `json:"unpublish_at,omitempty"`
+	Status           string             `json:"status,omitempty"`
+	HasDraft         bool               `json:"has_draft,omitempty"` // Admin: há edições não publicadas
+	Tags             []TaxonomyResponse `json:"tags"`
+	Categories       []TaxonomyResponse `json:"categories"`
+}
+
+// Tag ou categoria associada ao conteúdo
+type TaxonomyResponse struct {
+	ID    uint                   `json:"id"`
+	Title string                 `json:"title"`
+	Slug  string                 `json:"slug"`
+	Usage *TaxonomyUsageResponse `json:"usage,omitempty"` // Só nas rotas de tags/categorias
+}
+
+// Agendamento/remoção da publicação (null despublica)
+type PostedAtInput struct {
+	PostedAt *time.Time `json:"posted_at"`
+}
+
+// Retirada automática do ar (null remove o agendamento)
+type UnpublishAtInput struct {
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// Mudança manual de estado editorial (scheduled/published vêm do posted_at)
+type StatusInput struct {
+	Status string `json:"status" binding:"required,oneof=draft in_review approved archived"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+// Entrada do histórico de estados
+type StateChangeResponse struct {
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TagIDsInput struct {
+	TagIDs   []uint   `json:"tag_ids"`
+	TagNames []string `json:"tag_names"` // Como em ContentInput
+}
+
+type CategoryIDsInput struct {
+	CategoryIDs []uint `json:"category_ids"`
+}
+
+// Resposta para slugs antigos: o Next.js usa MovedTo para responder 301
+type RedirectResponse struct {
+	Slug    string `json:"slug"`
+	MovedTo string `json:"moved_to"`
 }
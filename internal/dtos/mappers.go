@@ -0,0 +1,323 @@
+package dtos
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/search"
+	"cms-headless/internal/validators"
+	"strings"
+)
+
+// Tamanho (em runas) do trecho do corpo exibido nos resultados de busca
+const snippetLength = 160
+
+const (
+	TypePost    = "post"
+	TypeProject = "project"
+)
+
+func FromPost(post models.Post) ContentResponse {
+	return ContentResponse{
+		ID:               post.ID,
+		Title:            post.Title,
+		Slug:             post.Slug,
+		ShortDescription: post.ShortDescription,
+		Body:             post.Body,
+		Type:             TypePost,
+		PostedAt:         post.PostedAt,
+		UnpublishAt:      post.UnpublishAt,
+		Status:           post.Status,
+		HasDraft:         post.HasDraft,
+		Tags:             FromTags(post.Tags),
+		Categories:       FromCategories(post.Categories),
+	}
+}
+
+func FromProject(project models.Project) ContentResponse {
+	return ContentResponse{
+		ID:               project.ID,
+		Title:            project.Title,
+		Slug:             project.Slug,
+		ShortDescription: project.ShortDescription,
+		Body:             project.Body,
+		Type:             TypeProject,
+		DemoURL:          project.DemoURL,
+		RepoURL:          project.RepoURL,
+		PostedAt:         project.PostedAt,
+		UnpublishAt:      project.UnpublishAt,
+		Status:           project.Status,
+		HasDraft:         project.HasDraft,
+		Tags:             FromTags(project.Tags),
+		Categories:       FromCategories(project.Categories),
+	}
+}
+
+func FromPosts(posts []models.Post) []ContentResponse {
+	res := make([]ContentResponse, 0, len(posts))
+	for _, p := range posts {
+		res = append(res, FromPost(p))
+	}
+	return res
+}
+
+func FromProjects(projects []models.Project) []ContentResponse {
+	res := make([]ContentResponse, 0, len(projects))
+	for _, p := range projects {
+		res = append(res, FromProject(p))
+	}
+	return res
+}
+
+func FromTag(tag models.Tag) TaxonomyResponse {
+	return TaxonomyResponse{ID: tag.ID, Title: tag.Title, Slug: tag.Slug}
+}
+
+func FromCategory(category models.Category) TaxonomyResponse {
+	return TaxonomyResponse{ID: category.ID, Title: category.Title, Slug: category.Slug}
+}
+
+func FromTagDetail(tag models.Tag) TaxonomyDetailResponse {
+	return TaxonomyDetailResponse{
+		TaxonomyResponse: FromTag(tag),
+		Description:      tag.Description,
+		MetaTitle:        tag.MetaTitle,
+		MetaDescription:  tag.MetaDescription,
+	}
+}
+
+func FromCategoryDetail(category models.Category) TaxonomyDetailResponse {
+	return TaxonomyDetailResponse{
+		TaxonomyResponse: FromCategory(category),
+		ParentID:         category.ParentID,
+		Description:      category.Description,
+		MetaTitle:        category.MetaTitle,
+		MetaDescription:  category.MetaDescription,
+	}
+}
+
+// Tag/categoria com a contagem de uso, para as rotas de administração
+func FromTaxonomyUsage(res TaxonomyResponse, usage repositories.TaxonomyUsage) TaxonomyResponse {
+	res.Usage = &TaxonomyUsageResponse{Posts: usage.Posts, Projects: usage.Projects}
+	return res
+}
+
+func FromTagsWithUsage(tags []models.Tag, usage map[uint]repositories.TaxonomyUsage) []TaxonomyResponse {
+	res := make([]TaxonomyResponse, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, FromTaxonomyUsage(FromTag(t), usage[t.ID]))
+	}
+	return res
+}
+
+func FromCategoriesWithUsage(categories []models.Category, usage map[uint]repositories.TaxonomyUsage) []TaxonomyResponse {
+	res := make([]TaxonomyResponse, 0, len(categories))
+	for _, c := range categories {
+		res = append(res, FromTaxonomyUsage(FromCategory(c), usage[c.ID]))
+	}
+	return res
+}
+
+// Sempre devolvemos slices (nunca null) para simplificar o consumo no Next.js
+func FromTags(tags []models.Tag) []TaxonomyResponse {
+	res := make([]TaxonomyResponse, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, FromTag(t))
+	}
+	return res
+}
+
+func FromCategories(categories []models.Category) []TaxonomyResponse {
+	res := make([]TaxonomyResponse, 0, len(categories))
+	for _, c := range categories {
+		res = append(res, FromCategory(c))
+	}
+	return res
+}
+
+func FromTagAlias(alias models.TagAlias) TagAliasResponse {
+	return TagAliasResponse{ID: alias.ID, Alias: alias.Alias, CreatedAt: alias.CreatedAt}
+}
+
+func FromTagAliases(aliases []models.TagAlias) []TagAliasResponse {
+	res := make([]TagAliasResponse, 0, len(aliases))
+	for _, a := range aliases {
+		res = append(res, FromTagAlias(a))
+	}
+	return res
+}
+
+func FromTagMatches(matches []repositories.TagMatch) []TagAutocompleteResponse {
+	res := make([]TagAutocompleteResponse, 0, len(matches))
+	for _, m := range matches {
+		res = append(res, TagAutocompleteResponse{
+			TaxonomyResponse: FromTaxonomyUsage(FromTag(m.Tag), m.Usage),
+			Alias:            m.Alias,
+		})
+	}
+	return res
+}
+
+func FromTagSuggestions(s repositories.TagSuggestions) TagSuggestionsResponse {
+	res := TagSuggestionsResponse{
+		Keywords: make([]KeywordResponse, 0, len(s.Keywords)),
+		Tags:     make([]SuggestedTagResponse, 0, len(s.Tags)),
+	}
+	for _, k := range s.Keywords {
+		res.Keywords = append(res.Keywords, KeywordResponse{Term: k.Term, Score: k.Score})
+	}
+	for _, t := range s.Tags {
+		res.Tags = append(res.Tags, SuggestedTagResponse{TaxonomyResponse: FromTag(t.Tag), Keyword: t.Keyword, Score: t.Score})
+	}
+	return res
+}
+
+// Monta a árvore a partir da lista plana em ordem de exibição. Categoria cujo
+// pai não está na lista vira raiz.
+func FromCategoryTree(categories []models.Category) []CategoryNodeResponse {
+	present := make(map[uint]bool, len(categories))
+	children := make(map[uint][]models.Category, len(categories))
+	for _, c := range categories {
+		present[c.ID] = true
+	}
+	var roots []models.Category
+	for _, c := range categories {
+		if c.ParentID != nil && present[*c.ParentID] {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	var build func(level []models.Category) []CategoryNodeResponse
+	build = func(level []models.Category) []CategoryNodeResponse {
+		nodes := make([]CategoryNodeResponse, 0, len(level))
+		for _, c := range level {
+			nodes = append(nodes, CategoryNodeResponse{TaxonomyResponse: FromCategory(c), Children: build(children[c.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+func FromWebhook(sub models.WebhookSubscription) WebhookResponse {
+	evts := []string{}
+	if sub.Events != "" {
+		evts = strings.Split(sub.Events, ",")
+	}
+	return WebhookResponse{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    evts,
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt,
+	}
+}
+
+func FromWebhooks(subs []models.WebhookSubscription) []WebhookResponse {
+	res := make([]WebhookResponse, 0, len(subs))
+	for _, s := range subs {
+		res = append(res, FromWebhook(s))
+	}
+	return res
+}
+
+func FromRevision(rev models.Revision) RevisionResponse {
+	return RevisionResponse{
+		Number:           rev.Number,
+		ContentType:      rev.ContentType,
+		ContentID:        rev.ContentID,
+		Title:            rev.Title,
+		Slug:             rev.Slug,
+		ShortDescription: rev.ShortDescription,
+		Body:             rev.Body,
+		DemoURL:          rev.DemoURL,
+		RepoURL:          rev.RepoURL,
+		TagIDs:           nonNilIDs(rev.TagIDs),
+		CategoryIDs:      nonNilIDs(rev.CategoryIDs),
+		Author:           rev.Author,
+		CreatedAt:        rev.CreatedAt,
+	}
+}
+
+func FromRevisions(revisions []models.Revision) []RevisionSummaryResponse {
+	res := make([]RevisionSummaryResponse, 0, len(revisions))
+	for _, r := range revisions {
+		res = append(res, RevisionSummaryResponse{
+			Number:    r.Number,
+			Title:     r.Title,
+			Slug:      r.Slug,
+			Author:    r.Author,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+	return res
+}
+
+func nonNilIDs(ids []uint) []uint {
+	if ids == nil {
+		return []uint{}
+	}
+	return ids
+}
+
+func FromStateChanges(changes []models.StateChange) []StateChangeResponse {
+	res := make([]StateChangeResponse, 0, len(changes))
+	for _, c := range changes {
+		res = append(res, StateChangeResponse{From: c.From, To: c.To, Actor: c.Actor, Note: c.Note, CreatedAt: c.CreatedAt})
+	}
+	return res
+}
+
+func FromPostHits(hits []repositories.SearchHit[models.Post], q string) []SearchHitResponse {
+	res := make([]SearchHitResponse, 0, len(hits))
+	for _, h := range hits {
+		res = append(res, searchHit(FromPost(h.Item), h.Score, q))
+	}
+	return res
+}
+
+func FromProjectHits(hits []repositories.SearchHit[models.Project], q string) []SearchHitResponse {
+	res := make([]SearchHitResponse, 0, len(hits))
+	for _, h := range hits {
+		res = append(res, searchHit(FromProject(h.Item), h.Score, q))
+	}
+	return res
+}
+
+// Busca unificada: o Type de cada item diz se é post ou projeto
+func FromContentHits(hits []repositories.ContentHit, q string) []SearchHitResponse {
+	res := make([]SearchHitResponse, 0, len(hits))
+	for _, h := range hits {
+		if h.Post != nil {
+			res = append(res, searchHit(FromPost(*h.Post), h.Score, q))
+		} else {
+			res = append(res, searchHit(FromProject(*h.Project), h.Score, q))
+		}
+	}
+	return res
+}
+
+func searchHit(content ContentResponse, score float64, q string) SearchHitResponse {
+	body := validators.StripHTML(content.Body)
+	if content.ShortDescription != "" && !search.Matches(body, q) {
+		body = content.ShortDescription // Casou pela descrição: ela explica melhor o resultado
+	}
+	return SearchHitResponse{
+		ContentResponse: content,
+		Score:           score,
+		TitleHighlight:  search.Highlight(content.Title, q),
+		Snippet:         search.Snippet(body, q, snippetLength),
+	}
+}
+
+func FromFacets(f repositories.Facets) FacetsResponse {
+	return FacetsResponse{Tags: fromFacetCounts(f.Tags), Categories: fromFacetCounts(f.Categories)}
+}
+
+func fromFacetCounts(counts []repositories.FacetCount) []FacetResponse {
+	res := make([]FacetResponse, 0, len(counts))
+	for _, c := range counts {
+		res = append(res, FacetResponse{ID: c.ID, Title: c.Title, Count: c.Count})
+	}
+	return res
+}
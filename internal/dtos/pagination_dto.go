@@ -0,0 +1,48 @@
+package dtos
+
+import "cms-headless/internal/utils"
+
+// Metadados de paginação calculados a partir do total retornado pelos repositórios
+type PaginationMeta struct {
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// Envelope padrão das listagens
+type PaginatedResponse[T any] struct {
+	Data       []T            `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+func NewPaginationMeta(page, pageSize int, total int64) PaginationMeta {
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+	return PaginationMeta{Page: page, PageSize: pageSize, Total: total, TotalPages: totalPages}
+}
+
+// Metadados da paginação por cursor. next_cursor some na última página e
+// total só vem quando pedido (with_total=true).
+type CursorMeta struct {
+	PageSize   int    `json:"page_size"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// Envelope das listagens por cursor
+type CursorResponse[T any] struct {
+	Data       []T        `json:"data"`
+	Pagination CursorMeta `json:"pagination"`
+}
+
+func NewCursorMeta(pageSize int, next *utils.Cursor, total *int64) CursorMeta {
+	meta := CursorMeta{PageSize: pageSize, HasMore: next != nil, Total: total}
+	if next != nil {
+		meta.NextCursor = utils.EncodeCursor(*next)
+	}
+	return meta
+}
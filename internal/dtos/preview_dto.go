@@ -0,0 +1,15 @@
+package dtos
+
+import "time"
+
+// Type vazio gera um token para todos os rascunhos; com Type, ID é obrigatório
+type PreviewTokenInput struct {
+	Type      string `json:"type" binding:"oneof=post project"`
+	ID        uint   `json:"id"`
+	ExpiresIn int    `json:"expires_in"` // Segundos; limitado ao máximo configurado
+}
+
+type PreviewTokenResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
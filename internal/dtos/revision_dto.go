@@ -0,0 +1,45 @@
+package dtos
+
+import (
+	"cms-headless/internal/diff"
+	"time"
+)
+
+// Item da listagem de revisões (sem o body, que pode ser grande)
+type RevisionSummaryResponse struct {
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Slug      string    `json:"slug"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RevisionResponse struct {
+	Number           int       `json:"number"`
+	ContentType      string    `json:"content_type"`
+	ContentID        uint      `json:"content_id"`
+	Title            string    `json:"title"`
+	Slug             string    `json:"slug"`
+	ShortDescription string    `json:"short_description"`
+	Body             string    `json:"body"`
+	DemoURL          string    `json:"demo_url,omitempty"`
+	RepoURL          string    `json:"repo_url,omitempty"`
+	TagIDs           []uint    `json:"tag_ids"`
+	CategoryIDs      []uint    `json:"category_ids"`
+	Author           string    `json:"author"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Campo simples que mudou entre duas revisões
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type RevisionDiffResponse struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+	Body    []diff.Line   `json:"body"` // Diff linha a linha do body
+}
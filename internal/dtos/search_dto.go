@@ -0,0 +1,28 @@
+package dtos
+
+// Item da busca: o conteúdo mais a relevância e os trechos destacados
+// (HTML escapado com os termos entre <mark>)
+type SearchHitResponse struct {
+	ContentResponse
+	Score          float64 `json:"score"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+// Quantos resultados da busca atual têm a tag/categoria (para os chips de filtro)
+type FacetResponse struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Count int64  `json:"count"`
+}
+
+type FacetsResponse struct {
+	Tags       []FacetResponse `json:"tags"`
+	Categories []FacetResponse `json:"categories"`
+}
+
+type SearchResponse struct {
+	Data       []SearchHitResponse `json:"data"`
+	Pagination PaginationMeta      `json:"pagination"`
+	Facets     FacetsResponse      `json:"facets"`
+}
@@ -0,0 +1,79 @@
+package dtos
+
+import "time"
+
+// Fusão de tags/categorias: as origens somem e seus itens passam para o destino
+type MergeInput struct {
+	SourceIDs []uint `json:"source_ids" binding:"required"`
+	TargetID  uint   `json:"target_id" binding:"required"`
+}
+
+// Quantos posts e projetos publicados usam a tag/categoria
+type TaxonomyUsageResponse struct {
+	Posts    int64 `json:"posts"`
+	Projects int64 `json:"projects"`
+}
+
+// Tag/categoria completa: item único no admin e página pública
+type TaxonomyDetailResponse struct {
+	TaxonomyResponse
+	ParentID        *uint  `json:"parent_id,omitempty"` // Só categorias
+	Description     string `json:"description"`
+	MetaTitle       string `json:"meta_title,omitempty"`
+	MetaDescription string `json:"meta_description,omitempty"`
+}
+
+// Página pública de uma tag/categoria: o item e o conteúdo publicado ligado a ele
+type TaxonomyLandingResponse struct {
+	Taxonomy    TaxonomyDetailResponse             `json:"taxonomy"`
+	Breadcrumbs []TaxonomyResponse                 `json:"breadcrumbs,omitempty"` // Categorias: da raiz até ela
+	Posts       PaginatedResponse[ContentResponse] `json:"posts"`
+	Projects    PaginatedResponse[ContentResponse] `json:"projects"`
+}
+
+// Nó da árvore de categorias, com as filhas já ordenadas
+type CategoryNodeResponse struct {
+	TaxonomyResponse
+	Children []CategoryNodeResponse `json:"children"`
+}
+
+// Move a categoria: parent_id null a torna raiz; sem position, vai para o fim
+type CategoryMoveInput struct {
+	ParentID *uint `json:"parent_id"`
+	Position *int  `json:"position"`
+}
+
+// Nome alternativo que resolve para a tag ("golang" -> "Go")
+type TagAliasInput struct {
+	Alias string `json:"alias" binding:"required,max=100"`
+}
+
+type TagAliasResponse struct {
+	ID        uint      `json:"id"`
+	Alias     string    `json:"alias"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Item do autocomplete de tags, com o uso; alias é o nome alternativo que casou
+type TagAutocompleteResponse struct {
+	TaxonomyResponse
+	Alias string `json:"alias,omitempty"`
+}
+
+// Palavra-chave do texto com o peso TF-IDF
+type KeywordResponse struct {
+	Term  string  `json:"term"`
+	Score float64 `json:"score"`
+}
+
+// Tag existente que aparece no texto; keyword é o título ou alias encontrado
+type SuggestedTagResponse struct {
+	TaxonomyResponse
+	Keyword string  `json:"keyword"`
+	Score   float64 `json:"score"`
+}
+
+type TagSuggestionsResponse struct {
+	Keywords []KeywordResponse      `json:"keywords"`
+	Tags     []SuggestedTagResponse `json:"tags"`
+}
@@ -0,0 +1,28 @@
+package dtos
+
+import "time"
+
+type WebhookInput struct {
+	URL    string   `json:"url" binding:"required" validate:"url"`
+	Events []string `json:"events"` // Vazio assina todos os eventos
+	Secret string   `json:"secret"` // Gerado automaticamente quando omitido
+}
+
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	Secret    string    `json:"secret,omitempty"` // Só exposto na criação
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Edição de tag/categoria (PUT substitui todos os campos). Sem slug, o atual é
+// mantido enquanto o título não mudar; com título novo, é gerado outro.
+type TaxonomyInput struct {
+	Title           string `json:"title" binding:"required"`
+	Slug            string `json:"slug"`
+	Description     string `json:"description" binding:"max=2000"`
+	MetaTitle       string `json:"meta_title" binding:"max=70"`
+	MetaDescription string `json:"meta_description" binding:"max=160"`
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+type Type string
+
+const (
+	ContentCreated     Type = "content.created"
+	ContentUpdated     Type = "content.updated"
+	ContentPublished   Type = "content.published"
+	ContentUnpublished Type = "content.unpublished"
+	ContentDeleted     Type = "content.deleted"
+	TagRenamed         Type = "tag.renamed"
+	CategoryRenamed    Type = "category.renamed"
+	TagDeleted         Type = "tag.deleted"
+	CategoryDeleted    Type = "category.deleted"
+	CategoryMoved      Type = "category.moved"
+)
+
+// Todos os tipos aceitos em assinaturas de webhook
+var Types = []Type{
+	ContentCreated, ContentUpdated, ContentPublished, ContentUnpublished,
+	ContentDeleted, TagRenamed, CategoryRenamed, TagDeleted, CategoryDeleted, CategoryMoved,
+}
+
+// Evento do ciclo de vida de um post/projeto (ou renomeação/remoção de tag/categoria)
+type Event struct {
+	Type         Type      `json:"type"`
+	ContentType  string    `json:"content_type"` // "post", "project", "tag" ou "category"
+	ContentID    uint      `json:"content_id"`
+	Slug         string    `json:"slug,omitempty"`
+	PreviousSlug string    `json:"previous_slug,omitempty"` // Para revalidar a rota antiga após renomeação
+	Title        string    `json:"title,omitempty"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+func IsValidType(t string) bool {
+	return slices.Contains(Types, Type(t))
+}
+
+type Handler func(ctx context.Context, e Event) error
+
+// Barramento em memória: cada assinante (webhooks, purge de cache, feeds) recebe todos os eventos
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Entrega o evento a todos os assinantes; a falha de um não impede os demais
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			log.Printf("Assinante falhou ao processar %s (%s %d): %v", e.Type, e.ContentType, e.ContentID, err)
+		}
+	}
+}
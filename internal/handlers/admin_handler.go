@@ -0,0 +1,624 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"cms-headless/internal/utils"
+	"cms-headless/internal/validators"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// API administrativa de escrita. Deve ser registrada atrás do auth.Middleware.
+type AdminHandler struct {
+	posts    services.PostService
+	projects services.ProjectService
+}
+
+func NewAdminHandler(posts services.PostService, projects services.ProjectService) *AdminHandler {
+	return &AdminHandler{posts: posts, projects: projects}
+}
+
+func (h *AdminHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/contents", h.create)
+
+	mux.HandleFunc("GET /admin/posts", h.listPosts)
+	mux.HandleFunc("GET /admin/posts/{id}", h.getPost)
+	mux.HandleFunc("PUT /admin/posts/{id}", h.updatePost)
+	mux.HandleFunc("DELETE /admin/posts/{id}", h.deletePost)
+	mux.HandleFunc("PUT /admin/posts/{id}/posted-at", h.setPostPostedAt)
+	mux.HandleFunc("PUT /admin/posts/{id}/unpublish-at", h.setPostUnpublishAt)
+	mux.HandleFunc("PUT /admin/posts/{id}/tags", h.replacePostTags)
+	mux.HandleFunc("PUT /admin/posts/{id}/categories", h.replacePostCategories)
+	mux.HandleFunc("GET /admin/posts/trash", h.listPostTrash)
+	mux.HandleFunc("POST /admin/posts/{id}/restore", h.restorePost)
+	mux.HandleFunc("DELETE /admin/posts/{id}/purge", h.purgePost)
+	mux.HandleFunc("POST /admin/posts/{id}/publish-changes", h.publishPostChanges)
+	mux.HandleFunc("DELETE /admin/posts/{id}/draft", h.discardPostChanges)
+	mux.HandleFunc("POST /admin/posts/{id}/status", h.transitionPost)
+	mux.HandleFunc("GET /admin/posts/{id}/state-changes", h.listPostStateChanges)
+
+	mux.HandleFunc("GET /admin/projects", h.listProjects)
+	mux.HandleFunc("GET /admin/projects/{id}", h.getProject)
+	mux.HandleFunc("PUT /admin/projects/{id}", h.updateProject)
+	mux.HandleFunc("DELETE /admin/projects/{id}", h.deleteProject)
+	mux.HandleFunc("PUT /admin/projects/{id}/posted-at", h.setProjectPostedAt)
+	mux.HandleFunc("PUT /admin/projects/{id}/unpublish-at", h.setProjectUnpublishAt)
+	mux.HandleFunc("PUT /admin/projects/{id}/tags", h.replaceProjectTags)
+	mux.HandleFunc("PUT /admin/projects/{id}/categories", h.replaceProjectCategories)
+	mux.HandleFunc("GET /admin/projects/trash", h.listProjectTrash)
+	mux.HandleFunc("POST /admin/projects/{id}/restore", h.restoreProject)
+	mux.HandleFunc("DELETE /admin/projects/{id}/purge", h.purgeProject)
+	mux.HandleFunc("POST /admin/projects/{id}/publish-changes", h.publishProjectChanges)
+	mux.HandleFunc("DELETE /admin/projects/{id}/draft", h.discardProjectChanges)
+	mux.HandleFunc("POST /admin/projects/{id}/status", h.transitionProject)
+	mux.HandleFunc("GET /admin/projects/{id}/state-changes", h.listProjectStateChanges)
+}
+
+func (h *AdminHandler) create(w http.ResponseWriter, r *http.Request) {
+	var in dtos.ContentInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+
+	switch in.Type {
+	case dtos.TypePost:
+		post, err := h.posts.Create(r.Context(), in)
+		if err != nil {
+			writeServiceError(w, err, "post")
+			return
+		}
+		writeJSON(w, http.StatusCreated, dtos.FromPost(*post))
+	case dtos.TypeProject:
+		project, err := h.projects.Create(r.Context(), in)
+		if err != nil {
+			writeServiceError(w, err, "projeto")
+			return
+		}
+		writeJSON(w, http.StatusCreated, dtos.FromProject(*project))
+	}
+}
+
+// --- Posts ---
+
+func (h *AdminHandler) listPosts(w http.ResponseWriter, r *http.Request) {
+	if wantsCursor(r) {
+		h.listPostsByCursor(w, r)
+		return
+	}
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	posts, total, err := h.posts.List(r.Context(), page, pageSize, r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Falha ao listar posts: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar posts")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.ContentResponse]{
+		Data:       dtos.FromPosts(posts),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+	})
+}
+
+func (h *AdminHandler) listPostsByCursor(w http.ResponseWriter, r *http.Request) {
+	q, errs := cursorQuery(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	page, err := h.posts.ListPage(r.Context(), q, r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Falha ao listar posts por cursor: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar posts")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.CursorResponse[dtos.ContentResponse]{
+		Data:       dtos.FromPosts(page.Items),
+		Pagination: dtos.NewCursorMeta(q.Limit, page.Next, page.Total),
+	})
+}
+
+func (h *AdminHandler) getPost(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	post, err := h.posts.Get(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) updatePost(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.ContentInput
+	if !decodeAndValidate(w, r, &in) || !requireType(w, in, dtos.TypePost) {
+		return
+	}
+	post, err := h.posts.Update(r.Context(), id, in)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) deletePost(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.posts.Delete(r.Context(), id); err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) setPostPostedAt(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.PostedAtInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	post, err := h.posts.SetPostedAt(r.Context(), id, in.PostedAt)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) setPostUnpublishAt(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.UnpublishAtInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	post, err := h.posts.SetUnpublishAt(r.Context(), id, in.UnpublishAt)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+// Promove o rascunho do post publicado a versão no ar
+func (h *AdminHandler) publishPostChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	post, err := h.posts.PublishChanges(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) discardPostChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	post, err := h.posts.DiscardChanges(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) transitionPost(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.StatusInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	post, err := h.posts.Transition(r.Context(), id, in.Status, in.Note)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) listPostStateChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	changes, err := h.posts.StateChanges(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromStateChanges(changes))
+}
+
+func (h *AdminHandler) replacePostTags(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.TagIDsInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	post, err := h.posts.ReplaceTags(r.Context(), id, nonNil(in.TagIDs), in.TagNames)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) replacePostCategories(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.CategoryIDsInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	post, err := h.posts.ReplaceCategories(r.Context(), id, nonNil(in.CategoryIDs))
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *AdminHandler) listPostTrash(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	items, total, err := h.posts.Trash(r.Context(), page, pageSize)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.ContentResponse]{
+		Data:       dtos.FromPosts(items),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+	})
+}
+
+func (h *AdminHandler) restorePost(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	item, err := h.posts.Restore(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*item))
+}
+
+func (h *AdminHandler) purgePost(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.posts.Purge(r.Context(), id); err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- Projects ---
+
+func (h *AdminHandler) listProjects(w http.ResponseWriter, r *http.Request) {
+	if wantsCursor(r) {
+		h.listProjectsByCursor(w, r)
+		return
+	}
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	projects, total, err := h.projects.List(r.Context(), page, pageSize, r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Falha ao listar projetos: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar projetos")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.ContentResponse]{
+		Data:       dtos.FromProjects(projects),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+	})
+}
+
+func (h *AdminHandler) listProjectsByCursor(w http.ResponseWriter, r *http.Request) {
+	q, errs := cursorQuery(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	page, err := h.projects.ListPage(r.Context(), q, r.URL.Query().Get("status"))
+	if err != nil {
+		log.Printf("Falha ao listar projetos por cursor: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar projetos")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.CursorResponse[dtos.ContentResponse]{
+		Data:       dtos.FromProjects(page.Items),
+		Pagination: dtos.NewCursorMeta(q.Limit, page.Next, page.Total),
+	})
+}
+
+func (h *AdminHandler) getProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	project, err := h.projects.Get(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) updateProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.ContentInput
+	if !decodeAndValidate(w, r, &in) || !requireType(w, in, dtos.TypeProject) {
+		return
+	}
+	project, err := h.projects.Update(r.Context(), id, in)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) deleteProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.projects.Delete(r.Context(), id); err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AdminHandler) setProjectPostedAt(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.PostedAtInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	project, err := h.projects.SetPostedAt(r.Context(), id, in.PostedAt)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) setProjectUnpublishAt(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.UnpublishAtInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	project, err := h.projects.SetUnpublishAt(r.Context(), id, in.UnpublishAt)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+// Promove o rascunho do projeto publicado a versão no ar
+func (h *AdminHandler) publishProjectChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	project, err := h.projects.PublishChanges(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) discardProjectChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	project, err := h.projects.DiscardChanges(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) transitionProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.StatusInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	project, err := h.projects.Transition(r.Context(), id, in.Status, in.Note)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) listProjectStateChanges(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	changes, err := h.projects.StateChanges(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromStateChanges(changes))
+}
+
+func (h *AdminHandler) replaceProjectTags(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.TagIDsInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	project, err := h.projects.ReplaceTags(r.Context(), id, nonNil(in.TagIDs), in.TagNames)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) replaceProjectCategories(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.CategoryIDsInput
+	if !decodeJSON(w, r, &in) {
+		return
+	}
+	project, err := h.projects.ReplaceCategories(r.Context(), id, nonNil(in.CategoryIDs))
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func (h *AdminHandler) listProjectTrash(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	items, total, err := h.projects.Trash(r.Context(), page, pageSize)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.ContentResponse]{
+		Data:       dtos.FromProjects(items),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+	})
+}
+
+func (h *AdminHandler) restoreProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	item, err := h.projects.Restore(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*item))
+}
+
+func (h *AdminHandler) purgeProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.projects.Purge(r.Context(), id); err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- Helpers ---
+
+func requireType(w http.ResponseWriter, in dtos.ContentInput, expected string) bool {
+	if in.Type != expected {
+		writeValidationErrors(w, []validators.FieldError{{Field: "type", Message: "deve ser " + expected + " para esta rota"}})
+		return false
+	}
+	return true
+}
+
+// Nas rotas de substituição, ausência de IDs significa "remover todas"
+func nonNil(ids []uint) []uint {
+	if ids == nil {
+		return []uint{}
+	}
+	return ids
+}
+
+// Traduz erros do service/repositórios em status HTTP
+func writeServiceError(w http.ResponseWriter, err error, entity string) {
+	var refErr *services.InvalidReferenceError
+	var valErr *services.ValidationError
+	var transErr *services.TransitionError
+	switch {
+	case errors.As(err, &refErr):
+		writeValidationErrors(w, []validators.FieldError{{Field: refErr.Field, Message: refErr.Error()}})
+	case errors.As(err, &valErr):
+		writeValidationErrors(w, []validators.FieldError{{Field: valErr.Field, Message: valErr.Message}})
+	case errors.Is(err, repositories.ErrInvalidReference):
+		writeError(w, http.StatusUnprocessableEntity, repositories.ErrInvalidReference.Error())
+	case errors.Is(err, repositories.ErrNotFound):
+		writeError(w, http.StatusNotFound, notFoundMessage(entity))
+	case errors.Is(err, services.ErrForbidden):
+		writeError(w, http.StatusForbidden, err.Error())
+	case errors.As(err, &transErr), errors.Is(err, services.ErrNoPendingChanges):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, repositories.ErrRestoreConflict):
+		// Mensagem própria (sem detalhes do driver) indicando o campo reutilizado
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, repositories.ErrDuplicateSlug):
+		writeConflict(w, "slug", repositories.ErrDuplicateSlug)
+	case errors.Is(err, repositories.ErrDuplicateTitle):
+		writeConflict(w, "title", repositories.ErrDuplicateTitle)
+	default:
+		log.Printf("Falha ao processar %s: %v", entity, err)
+		writeError(w, http.StatusInternalServerError, "erro ao processar "+entity)
+	}
+}
+
+func writeConflict(w http.ResponseWriter, field string, err error) {
+	writeJSON(w, http.StatusConflict, validationErrorResponse{
+		Error:  "conflito",
+		Fields: []validators.FieldError{{Field: field, Message: err.Error()}},
+	})
+}
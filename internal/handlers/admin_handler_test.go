@@ -0,0 +1,295 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/models"
+	"cms-headless/internal/services"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+const adminToken = "segredo"
+
+func setupAdminMux() (http.Handler, *gorm.DB) {
+	db := SetupTestDB()
+	adminMux := http.NewServeMux()
+	handlers.NewAdminHandler(services.NewPostService(db), services.NewProjectService(db)).Register(adminMux)
+	return auth.Middleware(auth.ParseTokens("editor:"+adminToken), adminMux), db
+}
+
+func adminRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// Leva o conteúdo de draft a approved para que possa ser agendado
+func approve(t *testing.T, mux http.Handler, path string) {
+	doRequest(t, mux, adminRequest(http.MethodPost, path+"/status", `{"status":"in_review"}`), nil)
+	doRequest(t, mux, adminRequest(http.MethodPost, path+"/status", `{"status":"approved"}`), nil)
+}
+
+type validationBody struct {
+	Fields []struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"fields"`
+}
+
+func TestAdminHandler(t *testing.T) {
+	mux, db := setupAdminMux()
+
+	t.Run("Deve recusar requisições sem token válido", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/contents", strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer errado")
+		rec := doRequest(t, mux, req, nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Deve criar post com slug gerado e body sanitizado", func(t *testing.T) {
+		var res dtos.ContentResponse
+		body := `{"type":"post","title":"Meu Post","body":"<p>oi</p><script>alert(1)</script>"}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", body), &res)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "meu-post", res.Slug)
+		assert.Equal(t, "<p>oi</p>", res.Body)
+		assert.Nil(t, res.PostedAt, "novo conteúdo nasce como rascunho")
+	})
+
+	t.Run("Deve retornar erros por campo quando a validação falhar", func(t *testing.T) {
+		var res validationBody
+		body := `{"type":"video","title":"ab","body":"","demo_url":"nao-e-url"}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", body), &res)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		fields := map[string]string{}
+		for _, f := range res.Fields {
+			fields[f.Field] = f.Message
+		}
+		assert.Contains(t, fields, "type")
+		assert.Contains(t, fields, "title")
+		assert.Contains(t, fields, "body")
+		assert.Contains(t, fields, "demo_url")
+	})
+
+	t.Run("Deve retornar 409 ao repetir o título", func(t *testing.T) {
+		var res validationBody
+		body := `{"type":"post","title":"Meu Post","body":"outro"}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", body), &res)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		if assert.NotEmpty(t, res.Fields) {
+			assert.Contains(t, []string{"title", "slug"}, res.Fields[0].Field)
+		}
+	})
+
+	t.Run("Deve criar e atualizar projeto", func(t *testing.T) {
+		var created dtos.ContentResponse
+		body := `{"type":"project","title":"Projeto X","body":"corpo","demo_url":"https://x.dev"}`
+		doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", body), &created)
+
+		var updated dtos.ContentResponse
+		body = `{"type":"project","title":"Projeto Y","body":"novo corpo","repo_url":"https://github.com/x/y"}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/projects/1", body), &updated)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, created.ID, updated.ID)
+		assert.Equal(t, "projeto-y", updated.Slug)
+		assert.Equal(t, "https://github.com/x/y", updated.RepoURL)
+	})
+
+	t.Run("Deve recusar update com tipo divergente da rota", func(t *testing.T) {
+		body := `{"type":"post","title":"Projeto Z","body":"corpo"}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/projects/1", body), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Deve agendar publicação, substituir tags e categorias", func(t *testing.T) {
+		db.Create(&models.Tag{Title: "Go"})
+		db.Create(&models.Category{Title: "Backend"})
+
+		var res dtos.ContentResponse
+		approve(t, mux, "/admin/posts/1")
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/posted-at", `{"posted_at":"2024-01-02T03:04:05Z"}`), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 2024, res.PostedAt.Year())
+		assert.Equal(t, models.StatusPublished, res.Status)
+
+		doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/tags", `{"tag_ids":[1]}`), &res)
+		assert.Len(t, res.Tags, 1)
+
+		doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/categories", `{"category_ids":[1]}`), &res)
+		assert.Len(t, res.Categories, 1)
+		assert.Len(t, res.Tags, 1)
+	})
+
+	t.Run("Deve agendar retirada apenas depois da publicação", func(t *testing.T) {
+		var res dtos.ContentResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/unpublish-at", `{"unpublish_at":"2024-02-01T00:00:00Z"}`), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, time.February, res.UnpublishAt.Month())
+
+		var invalid validationBody
+		rec = doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/unpublish-at", `{"unpublish_at":"2023-12-31T00:00:00Z"}`), &invalid)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		if assert.Len(t, invalid.Fields, 1) {
+			assert.Equal(t, "unpublish_at", invalid.Fields[0].Field)
+		}
+	})
+
+	t.Run("Deve retornar erro por campo para tags inexistentes", func(t *testing.T) {
+		var res validationBody
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/tags", `{"tag_ids":[1,99]}`), &res)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		if assert.Len(t, res.Fields, 1) {
+			assert.Equal(t, "tag_ids", res.Fields[0].Field)
+		}
+	})
+
+	t.Run("Deve remover post e retornar 404 em seguida", func(t *testing.T) {
+		rec := doRequest(t, mux, adminRequest(http.MethodDelete, "/admin/posts/1", ""), nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, "/admin/posts/1", ""), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Deve listar, restaurar e expurgar itens da lixeira", func(t *testing.T) {
+		var trash dtos.PaginatedResponse[dtos.ContentResponse]
+		doRequest(t, mux, adminRequest(http.MethodGet, "/admin/posts/trash", ""), &trash)
+		assert.Equal(t, int64(1), trash.Pagination.Total)
+
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/posts/1/restore", ""), nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, "/admin/posts/1/purge", ""), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code, "só itens na lixeira podem ser expurgados")
+
+		doRequest(t, mux, adminRequest(http.MethodDelete, "/admin/posts/1", ""), nil)
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, "/admin/posts/1/purge", ""), nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodPost, "/admin/posts/1/restore", ""), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestAdminHandler_Drafts(t *testing.T) {
+	mux, _ := setupAdminMux()
+
+	doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", `{"type":"post","title":"Publicado","body":"v1"}`), nil)
+	approve(t, mux, "/admin/posts/1")
+	doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/posted-at", `{"posted_at":"2024-01-02T03:04:05Z"}`), nil)
+
+	t.Run("Deve guardar edição de post publicado como rascunho", func(t *testing.T) {
+		var res dtos.ContentResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1", `{"type":"post","title":"Publicado","body":"v2"}`), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, res.HasDraft)
+		assert.Equal(t, "v2", res.Body)
+	})
+
+	t.Run("Deve publicar alterações e responder 409 sem rascunho", func(t *testing.T) {
+		var res dtos.ContentResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/posts/1/publish-changes", ""), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, res.HasDraft)
+		assert.Equal(t, "v2", res.Body)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodPost, "/admin/posts/1/publish-changes", ""), nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, "/admin/posts/1/draft", ""), nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
+
+func TestAdminHandler_TagNames(t *testing.T) {
+	mux, db := setupAdminMux()
+	golang := models.Tag{Title: "Go"}
+	db.Create(&golang)
+	db.Create(&models.TagAlias{TagID: golang.ID, Alias: "Golang"})
+
+	t.Run("Deve resolver tag_names para as tags canônicas criando as que faltam", func(t *testing.T) {
+		var res dtos.ContentResponse
+		body := `{"type":"post","title":"Canais","body":"oi","tag_names":["golang"," GO ","Concorrência"]}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", body), &res)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		if assert.Len(t, res.Tags, 2) {
+			titles := []string{res.Tags[0].Title, res.Tags[1].Title}
+			assert.ElementsMatch(t, []string{"Go", "Concorrência"}, titles)
+		}
+	})
+
+	t.Run("Deve juntar tag_ids e tag_names sem repetir", func(t *testing.T) {
+		var res dtos.ContentResponse
+		body := fmt.Sprintf(`{"tag_ids":[%d],"tag_names":["go"]}`, golang.ID)
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1/tags", body), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, res.Tags, 1) {
+			assert.Equal(t, golang.ID, res.Tags[0].ID)
+		}
+	})
+}
+
+func TestAdminHandler_Workflow(t *testing.T) {
+	db := SetupTestDB()
+	adminMux := http.NewServeMux()
+	handlers.NewAdminHandler(services.NewPostService(db), services.NewProjectService(db)).Register(adminMux)
+	mux := auth.Middleware(auth.ParseTokens("ana:tok-editor:editor,caio:"+adminToken), adminMux)
+
+	editorRequest := func(method, target, body string) *http.Request {
+		req := adminRequest(method, target, body)
+		req.Header.Set("Authorization", "Bearer tok-editor")
+		return req
+	}
+
+	doRequest(t, mux, editorRequest(http.MethodPost, "/admin/contents", `{"type":"project","title":"Projeto X","body":"corpo"}`), nil)
+
+	t.Run("Editor envia para revisão mas recebe 403 ao aprovar", func(t *testing.T) {
+		var res dtos.ContentResponse
+		rec := doRequest(t, mux, editorRequest(http.MethodPost, "/admin/projects/1/status", `{"status":"in_review","note":"revisar"}`), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, models.StatusInReview, res.Status)
+
+		rec = doRequest(t, mux, editorRequest(http.MethodPost, "/admin/projects/1/status", `{"status":"approved"}`), nil)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("Deve responder 409 para agendamento sem aprovação e 422 para estado desconhecido", func(t *testing.T) {
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/projects/1/posted-at", `{"posted_at":"2024-01-02T03:04:05Z"}`), nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodPost, "/admin/projects/1/status", `{"status":"published"}`), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Deve listar por estado e expor o histórico", func(t *testing.T) {
+		var list dtos.PaginatedResponse[dtos.ContentResponse]
+		doRequest(t, mux, adminRequest(http.MethodGet, "/admin/projects?status=in_review", ""), &list)
+		assert.Equal(t, int64(1), list.Pagination.Total)
+
+		var changes []dtos.StateChangeResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodGet, "/admin/projects/1/state-changes", ""), &changes)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, changes, 1) {
+			assert.Equal(t, "ana", changes[0].Actor)
+			assert.Equal(t, "revisar", changes[0].Note)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/validators"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout das datas sem hora aceitas em posted_from/posted_to
+const dateLayout = "2006-01-02"
+
+// Monta o filtro de busca a partir da query string:
+//
+//	q                                texto livre
+//	tag_id, category_id              precisa ter todos (AND)
+//	tag_id_any, category_id_any      ao menos um (OR)
+//	tag_id_not, category_id_not      nenhum (NOT)
+//	include_subcategories=true       category_id* casam também com as subcategorias
+//	posted_from, posted_to           intervalo de PostedAt (RFC 3339 ou AAAA-MM-DD)
+//
+// Listas aceitam vírgulas (tag_id=1,2) ou o parâmetro repetido (tag_id=1&tag_id=2).
+// Uma data sem hora em posted_to inclui o dia inteiro.
+func contentFilter(r *http.Request) (repositories.ContentFilter, []validators.FieldError) {
+	var errs []validators.FieldError
+	ids := func(key string) []uint {
+		v, err := queryUints(r, key)
+		if err != nil {
+			errs = append(errs, validators.FieldError{Field: key, Message: "deve ser uma lista de ids numéricos"})
+		}
+		return v
+	}
+	date := func(key string, endOfDay bool) *time.Time {
+		v, err := queryTime(r, key, endOfDay)
+		if err != nil {
+			errs = append(errs, validators.FieldError{Field: key, Message: "deve ser uma data AAAA-MM-DD ou RFC 3339"})
+		}
+		return v
+	}
+
+	filter := repositories.ContentFilter{
+		Query:             strings.TrimSpace(r.URL.Query().Get("q")),
+		Tags:              ids("tag_id"),
+		AnyTags:           ids("tag_id_any"),
+		ExcludeTags:       ids("tag_id_not"),
+		Categories:        ids("category_id"),
+		AnyCategories:     ids("category_id_any"),
+		ExcludeCategories: ids("category_id_not"),
+		PostedFrom:        date("posted_from", false),
+		PostedTo:          date("posted_to", true),
+
+		IncludeSubcategories: r.URL.Query().Get("include_subcategories") == "true",
+	}
+	if filter.PostedFrom != nil && filter.PostedTo != nil && !filter.PostedFrom.Before(*filter.PostedTo) {
+		errs = append(errs, validators.FieldError{Field: "posted_to", Message: "deve ser posterior a posted_from"})
+	}
+	return filter, errs
+}
+
+func queryUints(r *http.Request, key string) ([]uint, error) {
+	var ids []uint
+	for _, v := range r.URL.Query()[key] {
+		for _, part := range strings.Split(v, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(part, 10, 64)
+			if err != nil || id == 0 {
+				return nil, strconv.ErrSyntax
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+// endOfDay: uma data sem hora vira o início do dia seguinte, já que o limite
+// superior do filtro é exclusivo
+func queryTime(r *http.Request, key string, endOfDay bool) (*time.Time, error) {
+	v := strings.TrimSpace(r.URL.Query().Get(key))
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
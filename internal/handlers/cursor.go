@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"cms-headless/internal/utils"
+	"cms-headless/internal/validators"
+	"net/http"
+)
+
+// Listagens com ?cursor (mesmo vazio, para a primeira página) usam paginação por
+// cursor em vez de page/page_size com OFFSET
+func wantsCursor(r *http.Request) bool {
+	return r.URL.Query().Has("cursor")
+}
+
+// Lê cursor, page_size e with_total da query string
+func cursorQuery(r *http.Request) (utils.CursorQuery, []validators.FieldError) {
+	_, limit := utils.NormalizePagination(1, queryInt(r, "page_size", 0))
+	after, err := utils.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return utils.CursorQuery{}, []validators.FieldError{{Field: "cursor", Message: "cursor inválido ou expirado"}}
+	}
+	return utils.CursorQuery{After: after, Limit: limit, WithTotal: r.URL.Query().Get("with_total") == "true"}, nil
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/utils"
+	"log"
+	"net/http"
+)
+
+// Páginas públicas de tags e categorias (/tags/[slug] e /categories/[slug] no
+// Next.js): o item com descrição e SEO, mais os posts e projetos publicados.
+// page e page_size valem para as duas listas. A página de uma categoria inclui o
+// conteúdo das subcategorias. Também serve a árvore de navegação e os breadcrumbs.
+type LandingHandler struct {
+	tags       repositories.TagRepository
+	categories repositories.CategoryRepository
+	posts      repositories.PostRepository
+	projects   repositories.ProjectRepository
+}
+
+func NewLandingHandler(
+	tags repositories.TagRepository,
+	categories repositories.CategoryRepository,
+	posts repositories.PostRepository,
+	projects repositories.ProjectRepository,
+) *LandingHandler {
+	return &LandingHandler{tags: tags, categories: categories, posts: posts, projects: projects}
+}
+
+func (h *LandingHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/tags/{slug}", h.getTag)
+	mux.HandleFunc("GET /api/categories", h.categoryTree)
+	mux.HandleFunc("GET /api/categories/{slug}", h.getCategory)
+	mux.HandleFunc("GET /api/categories/{slug}/breadcrumbs", h.breadcrumbs)
+}
+
+func (h *LandingHandler) getTag(w http.ResponseWriter, r *http.Request) {
+	tag, movedTo, err := h.tags.WithContext(r.Context()).FindBySlugOrRedirect(r.PathValue("slug"))
+	if err != nil {
+		writeLookupError(w, err, "tag")
+		return
+	}
+	if movedTo != "" {
+		writeMoved(w, "tags", movedTo)
+		return
+	}
+	h.writeLanding(w, r, dtos.FromTagDetail(*tag), nil, repositories.ContentFilter{Tags: []uint{tag.ID}})
+}
+
+func (h *LandingHandler) getCategory(w http.ResponseWriter, r *http.Request) {
+	repo := h.categories.WithContext(r.Context())
+	category, movedTo, err := repo.FindBySlugOrRedirect(r.PathValue("slug"))
+	if err != nil {
+		writeLookupError(w, err, "categoria")
+		return
+	}
+	if movedTo != "" {
+		writeMoved(w, "categories", movedTo)
+		return
+	}
+	ancestors, err := repo.Ancestors(category.ID)
+	if err != nil {
+		writeLookupError(w, err, "categoria")
+		return
+	}
+	filter := repositories.ContentFilter{Categories: []uint{category.ID}, IncludeSubcategories: true}
+	h.writeLanding(w, r, dtos.FromCategoryDetail(*category), dtos.FromCategories(ancestors), filter)
+}
+
+// Árvore completa, para menus de navegação
+func (h *LandingHandler) categoryTree(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.categories.WithContext(r.Context()).Tree()
+	if err != nil {
+		log.Printf("Falha ao montar a árvore de categorias: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar categorias")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromCategoryTree(categories))
+}
+
+// Da raiz até a categoria. Slug antigo é resolvido direto, sem 301, já que a
+// página que pede os breadcrumbs (ex.: um post) não muda de endereço.
+func (h *LandingHandler) breadcrumbs(w http.ResponseWriter, r *http.Request) {
+	repo := h.categories.WithContext(r.Context())
+	category, movedTo, err := repo.FindBySlugOrRedirect(r.PathValue("slug"))
+	if err == nil && movedTo != "" {
+		category, err = repo.FindBySlug(movedTo)
+	}
+	if err != nil {
+		writeLookupError(w, err, "categoria")
+		return
+	}
+	ancestors, err := repo.Ancestors(category.ID)
+	if err != nil {
+		writeLookupError(w, err, "categoria")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromCategories(ancestors))
+}
+
+func (h *LandingHandler) writeLanding(w http.ResponseWriter, r *http.Request, taxonomy dtos.TaxonomyDetailResponse, breadcrumbs []dtos.TaxonomyResponse, filter repositories.ContentFilter) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	filter.OnlyPosted = true
+
+	posts, postTotal, err := h.posts.WithContext(r.Context()).Search(page, pageSize, filter)
+	if err != nil {
+		log.Printf("Falha ao listar posts de %q: %v", taxonomy.Slug, err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar posts")
+		return
+	}
+	projects, projectTotal, err := h.projects.WithContext(r.Context()).Search(page, pageSize, filter)
+	if err != nil {
+		log.Printf("Falha ao listar projetos de %q: %v", taxonomy.Slug, err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar projetos")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.TaxonomyLandingResponse{
+		Taxonomy:    taxonomy,
+		Breadcrumbs: breadcrumbs,
+		Posts: dtos.PaginatedResponse[dtos.ContentResponse]{
+			Data:       dtos.FromPosts(posts),
+			Pagination: dtos.NewPaginationMeta(page, pageSize, postTotal),
+		},
+		Projects: dtos.PaginatedResponse[dtos.ContentResponse]{
+			Data:       dtos.FromProjects(projects),
+			Pagination: dtos.NewPaginationMeta(page, pageSize, projectTotal),
+		},
+	})
+}
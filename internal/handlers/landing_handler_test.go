@@ -0,0 +1,146 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLandingHandler(t *testing.T) {
+	db := SetupTestDB()
+	past := time.Now().UTC().Add(-time.Hour)
+	tags := repositories.NewTagRepository(db)
+
+	tag := models.Tag{Title: "Golang", Description: "Tudo sobre Go", MetaTitle: "Artigos de Go"}
+	cat := models.Category{Title: "Back-end"}
+	db.Create(&tag)
+	db.Create(&cat)
+	db.Create(&models.Post{Title: "Publicado", Slug: "publicado", PostedAt: &past, Tags: []models.Tag{tag}, Categories: []models.Category{cat}})
+	db.Create(&models.Post{Title: "Rascunho", Slug: "rascunho", Tags: []models.Tag{tag}})
+	db.Create(&models.Project{Title: "CLI", Slug: "cli", PostedAt: &past, Tags: []models.Tag{tag}})
+
+	mux := http.NewServeMux()
+	handlers.NewLandingHandler(tags, repositories.NewCategoryRepository(db),
+		repositories.NewPostRepository(db), repositories.NewProjectRepository(db)).Register(mux)
+
+	t.Run("Deve retornar a tag com posts e projetos publicados", func(t *testing.T) {
+		var res dtos.TaxonomyLandingResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/tags/golang", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "golang", res.Taxonomy.Slug)
+		assert.Equal(t, "Tudo sobre Go", res.Taxonomy.Description)
+		assert.Equal(t, "Artigos de Go", res.Taxonomy.MetaTitle)
+		if assert.Len(t, res.Posts.Data, 1, "rascunhos ficam de fora") {
+			assert.Equal(t, "publicado", res.Posts.Data[0].Slug)
+		}
+		assert.Equal(t, int64(1), res.Posts.Pagination.Total)
+		assert.Len(t, res.Projects.Data, 1)
+	})
+
+	t.Run("Deve retornar a categoria pelo slug", func(t *testing.T) {
+		var res dtos.TaxonomyLandingResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/categories/back-end", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Back-end", res.Taxonomy.Title)
+		assert.Len(t, res.Posts.Data, 1)
+		assert.Empty(t, res.Projects.Data)
+		assert.NotNil(t, res.Projects.Data, "listas devem ser [] e não null")
+	})
+
+	t.Run("Deve responder 301 para slug antigo de tag renomeada", func(t *testing.T) {
+		assert.NoError(t, tags.UpdateName(tag.ID, "Go"))
+
+		var res dtos.RedirectResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/tags/golang", nil), &res)
+
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+		assert.Equal(t, "/api/tags/go", rec.Header().Get("Location"))
+		assert.Equal(t, "/tags/go", res.MovedTo)
+	})
+
+	t.Run("Deve retornar 404 para slug desconhecido", func(t *testing.T) {
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/categories/nada", nil), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestLandingHandler_CategoryTree(t *testing.T) {
+	db := SetupTestDB()
+	past := time.Now().UTC().Add(-time.Hour)
+	categories := repositories.NewCategoryRepository(db)
+
+	backend := models.Category{Title: "Backend"}
+	golang := models.Category{Title: "Go"}
+	concorrencia := models.Category{Title: "Concorrência"}
+	design := models.Category{Title: "Design"}
+	for _, c := range []*models.Category{&backend, &golang, &concorrencia, &design} {
+		categories.Create(c)
+	}
+	categories.Move(golang.ID, &backend.ID, -1)
+	categories.Move(concorrencia.ID, &golang.ID, -1)
+	categories.Move(design.ID, nil, 0)
+	db.Create(&models.Post{Title: "Canais", Slug: "canais", PostedAt: &past, Categories: []models.Category{concorrencia}})
+
+	mux := http.NewServeMux()
+	handlers.NewLandingHandler(repositories.NewTagRepository(db), categories,
+		repositories.NewPostRepository(db), repositories.NewProjectRepository(db)).Register(mux)
+
+	t.Run("Deve listar a árvore na ordem das posições", func(t *testing.T) {
+		var tree []dtos.CategoryNodeResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/categories", nil), &tree)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, tree, 2) {
+			assert.Equal(t, "Design", tree[0].Title)
+			assert.NotNil(t, tree[0].Children, "folhas têm children [] e não null")
+			assert.Equal(t, "go", tree[1].Children[0].Slug)
+			assert.Equal(t, "Concorrência", tree[1].Children[0].Children[0].Title)
+		}
+	})
+
+	t.Run("Deve mostrar na categoria o conteúdo das subcategorias", func(t *testing.T) {
+		var res dtos.TaxonomyLandingResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/categories/backend", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, res.Posts.Data, 1) {
+			assert.Equal(t, "canais", res.Posts.Data[0].Slug)
+		}
+		assert.Len(t, res.Breadcrumbs, 1)
+	})
+
+	t.Run("Deve retornar breadcrumbs da raiz até a categoria", func(t *testing.T) {
+		var crumbs []dtos.TaxonomyResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/categories/concorrencia/breadcrumbs", nil), &crumbs)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, crumbs, 3) {
+			assert.Equal(t, []string{"backend", "go", "concorrencia"}, []string{crumbs[0].Slug, crumbs[1].Slug, crumbs[2].Slug})
+		}
+
+		rec = doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/categories/nada/breadcrumbs", nil), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Deve filtrar posts pela subárvore com include_subcategories", func(t *testing.T) {
+		public := http.NewServeMux()
+		handlers.NewPublicHandler(repositories.NewPostRepository(db), repositories.NewProjectRepository(db)).Register(public)
+
+		var res dtos.PaginatedResponse[dtos.ContentResponse]
+		doRequest(t, public, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/posts?category_id=%d", backend.ID), nil), &res)
+		assert.Empty(t, res.Data)
+
+		doRequest(t, public, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/posts?category_id=%d&include_subcategories=true", backend.ID), nil), &res)
+		assert.Len(t, res.Data, 1)
+	})
+}
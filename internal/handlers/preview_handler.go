@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/models"
+	"cms-headless/internal/preview"
+	"cms-headless/internal/services"
+	"cms-headless/internal/validators"
+	"net/http"
+	"time"
+)
+
+// Header (ou parâmetro ?token=) com o token de preview
+const PreviewTokenHeader = "X-Preview-Token"
+
+// Preview para o Draft Mode do Next.js: o admin emite tokens assinados de curta
+// duração e a rota pública correspondente devolve a cópia de trabalho (rascunhos
+// incluídos). Sem token válido nada além do publicado é exposto.
+type PreviewHandler struct {
+	posts    services.PostService
+	projects services.ProjectService
+	secret   []byte
+	maxTTL   time.Duration
+	now      func() time.Time
+}
+
+func NewPreviewHandler(secret []byte, maxTTL time.Duration, posts services.PostService, projects services.ProjectService) *PreviewHandler {
+	return &PreviewHandler{posts: posts, projects: projects, secret: secret, maxTTL: maxTTL, now: time.Now}
+}
+
+// Rota de emissão; deve ser registrada atrás do auth.Middleware
+func (h *PreviewHandler) RegisterAdmin(mux *http.ServeMux) {
+	mux.HandleFunc("POST /admin/preview-tokens", h.issue)
+}
+
+func (h *PreviewHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/preview/posts/{slug}", h.getPost)
+	mux.HandleFunc("GET /api/preview/projects/{slug}", h.getProject)
+}
+
+func (h *PreviewHandler) issue(w http.ResponseWriter, r *http.Request) {
+	var in dtos.PreviewTokenInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	if in.Type != "" && in.ID == 0 {
+		writeValidationErrors(w, []validators.FieldError{{Field: "id", Message: "campo obrigatório quando type é informado"}})
+		return
+	}
+
+	// Confere se o conteúdo existe para não emitir tokens que nunca servirão
+	var (
+		err    error
+		entity string
+	)
+	switch in.Type {
+	case dtos.TypePost:
+		_, err = h.posts.Get(r.Context(), in.ID)
+		entity = "post"
+	case dtos.TypeProject:
+		_, err = h.projects.Get(r.Context(), in.ID)
+		entity = "projeto"
+	}
+	if err != nil {
+		writeServiceError(w, err, entity)
+		return
+	}
+
+	ttl := h.maxTTL
+	if in.ExpiresIn > 0 && time.Duration(in.ExpiresIn)*time.Second < ttl {
+		ttl = time.Duration(in.ExpiresIn) * time.Second
+	}
+	expiresAt := h.now().Add(ttl).UTC().Truncate(time.Second)
+	token := preview.Issue(h.secret, preview.Claims{ContentType: in.Type, ContentID: in.ID, ExpiresAt: expiresAt.Unix()})
+
+	writeJSON(w, http.StatusCreated, dtos.PreviewTokenResponse{Token: token, ExpiresAt: expiresAt})
+}
+
+func (h *PreviewHandler) getPost(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.claims(w, r)
+	if !ok {
+		return
+	}
+	post, err := h.findPost(r, claims)
+	if err != nil {
+		writeLookupError(w, err, "post")
+		return
+	}
+	// Token de outro conteúdo responde 404, sem revelar que o slug existe
+	if !claims.Allows(dtos.TypePost, post.ID) {
+		writeError(w, http.StatusNotFound, notFoundMessage("post"))
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *PreviewHandler) getProject(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.claims(w, r)
+	if !ok {
+		return
+	}
+	project, err := h.findProject(r, claims)
+	if err != nil {
+		writeLookupError(w, err, "projeto")
+		return
+	}
+	// Token de outro conteúdo responde 404, sem revelar que o slug existe
+	if !claims.Allows(dtos.TypeProject, project.ID) {
+		writeError(w, http.StatusNotFound, notFoundMessage("projeto"))
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+// Token de um item só: busca pelo ID assinado, já com o rascunho aplicado, e aceita
+// tanto o slug do rascunho quanto o que está no ar. Token geral busca pelo slug.
+func (h *PreviewHandler) findPost(r *http.Request, claims preview.Claims) (*models.Post, error) {
+	slug := r.PathValue("slug")
+	if claims.ContentType != dtos.TypePost {
+		return h.posts.GetBySlug(r.Context(), slug)
+	}
+	post, err := h.posts.Get(r.Context(), claims.ContentID)
+	if err != nil || post.Slug == slug {
+		return post, err
+	}
+	return h.posts.GetBySlug(r.Context(), slug)
+}
+
+func (h *PreviewHandler) findProject(r *http.Request, claims preview.Claims) (*models.Project, error) {
+	slug := r.PathValue("slug")
+	if claims.ContentType != dtos.TypeProject {
+		return h.projects.GetBySlug(r.Context(), slug)
+	}
+	project, err := h.projects.Get(r.Context(), claims.ContentID)
+	if err != nil || project.Slug == slug {
+		return project, err
+	}
+	return h.projects.GetBySlug(r.Context(), slug)
+}
+
+// Valida o token; respostas de preview nunca devem ser cacheadas
+func (h *PreviewHandler) claims(w http.ResponseWriter, r *http.Request) (preview.Claims, bool) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	token := r.Header.Get(PreviewTokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, "token de preview ausente")
+		return preview.Claims{}, false
+	}
+	claims, err := preview.Parse(h.secret, token, h.now())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return preview.Claims{}, false
+	}
+	return claims, true
+}
@@ -0,0 +1,112 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewHandler(t *testing.T) {
+	db := SetupTestDB()
+	posts, projects := services.NewPostService(db), services.NewProjectService(db)
+	preview := handlers.NewPreviewHandler([]byte("segredo-preview"), time.Hour, posts, projects)
+
+	mux := http.NewServeMux()
+	adminMux := http.NewServeMux()
+	handlers.NewAdminHandler(posts, projects).Register(adminMux)
+	preview.RegisterAdmin(adminMux)
+	preview.Register(mux)
+	handlers.NewPublicHandler(repositories.NewPostRepository(db), repositories.NewProjectRepository(db)).Register(mux)
+	mux.Handle("/admin/", auth.Middleware(auth.ParseTokens("editor:"+adminToken), adminMux))
+
+	doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", `{"type":"post","title":"Rascunho Um","body":"segredo"}`), nil)
+	doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", `{"type":"post","title":"Rascunho Dois","body":"outro"}`), nil)
+
+	issue := func(body string) (string, *httptest.ResponseRecorder) {
+		var res dtos.PreviewTokenResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/preview-tokens", body), &res)
+		return res.Token, rec
+	}
+	previewRequest := func(target, token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(handlers.PreviewTokenHeader, token)
+		return req
+	}
+
+	t.Run("Requisição anônima não enxerga rascunhos", func(t *testing.T) {
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/rascunho-um", nil), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/preview/posts/rascunho-um", nil), nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Token de um post libera apenas aquele post", func(t *testing.T) {
+		token, rec := issue(`{"type":"post","id":1,"expires_in":60}`)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+
+		var res dtos.ContentResponse
+		rec = doRequest(t, mux, previewRequest("/api/preview/posts/rascunho-um", token), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "segredo", res.Body)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		rec = doRequest(t, mux, previewRequest("/api/preview/posts/rascunho-dois", token), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Token geral libera todos os rascunhos via query string", func(t *testing.T) {
+		token, _ := issue(`{}`)
+
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/preview/posts/rascunho-dois?token="+token, nil), nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("Deve recusar token adulterado e emissão inválida", func(t *testing.T) {
+		rec := doRequest(t, mux, previewRequest("/api/preview/posts/rascunho-um", "abc.def"), nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		_, rec = issue(`{"type":"post"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		_, rec = issue(`{"type":"project","id":99}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Deve mostrar o rascunho que renomeou um post no ar", func(t *testing.T) {
+		ctx := context.Background()
+		past := time.Now().UTC().Add(-time.Hour)
+		post, _ := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "No Ar", Body: "publicado"})
+		posts.Transition(ctx, post.ID, models.StatusInReview, "")
+		posts.Transition(ctx, post.ID, models.StatusApproved, "")
+		posts.SetPostedAt(ctx, post.ID, &past)
+		posts.Update(ctx, post.ID, dtos.ContentInput{Type: "post", Title: "No Ar", Slug: "novo-nome", Body: "revisado"})
+
+		token, _ := issue(fmt.Sprintf(`{"type":"post","id":%d}`, post.ID))
+		for _, slug := range []string{"novo-nome", "no-ar"} {
+			var res dtos.ContentResponse
+			rec := doRequest(t, mux, previewRequest("/api/preview/posts/"+slug, token), &res)
+			assert.Equal(t, http.StatusOK, rec.Code, slug)
+			assert.Equal(t, "revisado", res.Body)
+			assert.Equal(t, "novo-nome", res.Slug)
+		}
+
+		general, _ := issue(`{}`)
+		rec := doRequest(t, mux, previewRequest("/api/preview/posts/novo-nome", general), nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = doRequest(t, mux, previewRequest("/api/preview/posts/rascunho-um", token), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/utils"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// API pública somente leitura consumida pelo Next.js.
+// Todas as consultas usam onlyPosted=true: rascunhos e agendados nunca vazam.
+// Rascunhos só são servidos pelo PreviewHandler, mediante token assinado.
+type PublicHandler struct {
+	posts    repositories.PostRepository
+	projects repositories.ProjectRepository
+}
+
+func NewPublicHandler(posts repositories.PostRepository, projects repositories.ProjectRepository) *PublicHandler {
+	return &PublicHandler{posts: posts, projects: projects}
+}
+
+func (h *PublicHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/posts", h.listPosts)
+	mux.HandleFunc("GET /api/posts/search", h.searchPosts)
+	mux.HandleFunc("GET /api/posts/{slug}", h.getPost)
+	mux.HandleFunc("GET /api/projects", h.listProjects)
+	mux.HandleFunc("GET /api/projects/search", h.searchProjects)
+	mux.HandleFunc("GET /api/projects/{slug}", h.getProject)
+}
+
+func (h *PublicHandler) listPosts(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	filter, errs := contentFilter(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	filter.OnlyPosted = true
+	if wantsCursor(r) {
+		h.listPostsByCursor(w, r, filter)
+		return
+	}
+
+	var (
+		posts []models.Post
+		total int64
+		err   error
+	)
+	if filter.HasCriteria() {
+		posts, total, err = h.posts.WithContext(r.Context()).Search(page, pageSize, filter)
+	} else {
+		posts, total, err = h.posts.WithContext(r.Context()).FindAll(page, pageSize, true, "")
+	}
+	if err != nil {
+		log.Printf("Falha ao listar posts: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar posts")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.ContentResponse]{
+		Data:       dtos.FromPosts(posts),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+	})
+}
+
+// Rolagem infinita: ordem estável por (posted_at, id), total só com with_total=true
+func (h *PublicHandler) listPostsByCursor(w http.ResponseWriter, r *http.Request, filter repositories.ContentFilter) {
+	q, errs := cursorQuery(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	page, err := h.posts.WithContext(r.Context()).FindPage(q, filter)
+	if err != nil {
+		log.Printf("Falha ao listar posts por cursor: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar posts")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.CursorResponse[dtos.ContentResponse]{
+		Data:       dtos.FromPosts(page.Items),
+		Pagination: dtos.NewCursorMeta(q.Limit, page.Next, page.Total),
+	})
+}
+
+// Busca com relevância, trechos destacados e contagens por tag/categoria
+func (h *PublicHandler) searchPosts(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	filter, errs := contentFilter(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	filter.OnlyPosted = true
+
+	posts := h.posts.WithContext(r.Context())
+	hits, total, err := posts.SearchHits(page, pageSize, filter)
+	if err != nil {
+		log.Printf("Falha ao buscar posts: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao buscar posts")
+		return
+	}
+	facets, err := posts.SearchFacets(filter)
+	if err != nil {
+		log.Printf("Falha ao contar facetas da busca: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao buscar posts")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.SearchResponse{
+		Data:       dtos.FromPostHits(hits, filter.Query),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+		Facets:     dtos.FromFacets(facets),
+	})
+}
+
+func (h *PublicHandler) getPost(w http.ResponseWriter, r *http.Request) {
+	post, movedTo, err := h.posts.WithContext(r.Context()).FindBySlugOrRedirect(r.PathValue("slug"), true)
+	if err != nil {
+		writeLookupError(w, err, "post")
+		return
+	}
+	if movedTo != "" {
+		writeMoved(w, "posts", movedTo)
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *PublicHandler) listProjects(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	filter, errs := contentFilter(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	filter.OnlyPosted = true
+	if wantsCursor(r) {
+		h.listProjectsByCursor(w, r, filter)
+		return
+	}
+
+	var (
+		projects []models.Project
+		total    int64
+		err      error
+	)
+	if filter.HasCriteria() {
+		projects, total, err = h.projects.WithContext(r.Context()).Search(page, pageSize, filter)
+	} else {
+		projects, total, err = h.projects.WithContext(r.Context()).FindAll(page, pageSize, true, "")
+	}
+	if err != nil {
+		log.Printf("Falha ao listar projetos: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar projetos")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.ContentResponse]{
+		Data:       dtos.FromProjects(projects),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+	})
+}
+
+// Rolagem infinita: ordem estável por (posted_at, id), total só com with_total=true
+func (h *PublicHandler) listProjectsByCursor(w http.ResponseWriter, r *http.Request, filter repositories.ContentFilter) {
+	q, errs := cursorQuery(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	page, err := h.projects.WithContext(r.Context()).FindPage(q, filter)
+	if err != nil {
+		log.Printf("Falha ao listar projetos por cursor: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar projetos")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.CursorResponse[dtos.ContentResponse]{
+		Data:       dtos.FromProjects(page.Items),
+		Pagination: dtos.NewCursorMeta(q.Limit, page.Next, page.Total),
+	})
+}
+
+func (h *PublicHandler) searchProjects(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	filter, errs := contentFilter(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	filter.OnlyPosted = true
+
+	projects := h.projects.WithContext(r.Context())
+	hits, total, err := projects.SearchHits(page, pageSize, filter)
+	if err != nil {
+		log.Printf("Falha ao buscar projetos: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao buscar projetos")
+		return
+	}
+	facets, err := projects.SearchFacets(filter)
+	if err != nil {
+		log.Printf("Falha ao contar facetas da busca: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao buscar projetos")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.SearchResponse{
+		Data:       dtos.FromProjectHits(hits, filter.Query),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+		Facets:     dtos.FromFacets(facets),
+	})
+}
+
+func (h *PublicHandler) getProject(w http.ResponseWriter, r *http.Request) {
+	project, movedTo, err := h.projects.WithContext(r.Context()).FindBySlugOrRedirect(r.PathValue("slug"), true)
+	if err != nil {
+		writeLookupError(w, err, "projeto")
+		return
+	}
+	if movedTo != "" {
+		writeMoved(w, "projects", movedTo)
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+// Diferencia "não encontrado" (404) de falhas reais do banco (500)
+func writeLookupError(w http.ResponseWriter, err error, entity string) {
+	if errors.Is(err, repositories.ErrNotFound) {
+		writeError(w, http.StatusNotFound, notFoundMessage(entity))
+		return
+	}
+	log.Printf("Falha ao buscar %s: %v", entity, err)
+	writeError(w, http.StatusInternalServerError, "erro ao buscar "+entity)
+}
+
+// Slug antigo: 301 com Location para a API e o caminho do front no corpo
+func writeMoved(w http.ResponseWriter, resource, slug string) {
+	w.Header().Set("Location", "/api/"+resource+"/"+slug)
+	writeJSON(w, http.StatusMovedPermanently, dtos.RedirectResponse{
+		Slug:    slug,
+		MovedTo: "/" + resource + "/" + slug,
+	})
+}
@@ -0,0 +1,245 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupPublicMux() (*http.ServeMux, *models.Tag, *models.Category) {
+	db := SetupTestDB()
+	now := time.Now().UTC()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tag := models.Tag{Title: "Go"}
+	cat := models.Category{Title: "Backend"}
+	db.Create(&tag)
+	db.Create(&cat)
+
+	db.Create(&models.Post{Title: "Publicado", Slug: "publicado", PostedAt: &past, Tags: []models.Tag{tag}, Categories: []models.Category{cat}})
+	db.Create(&models.Post{Title: "Outro", Slug: "outro", PostedAt: &past})
+	db.Create(&models.Post{Title: "Agendado", Slug: "agendado", PostedAt: &future})
+	db.Create(&models.Post{Title: "Rascunho", Slug: "rascunho"})
+	db.Create(&models.Project{Title: "Portfolio", Slug: "portfolio", DemoURL: "https://demo.dev", PostedAt: &past})
+	db.Create(&models.Project{Title: "Secreto", Slug: "secreto"})
+	db.Create(&models.SlugHistory{ContentType: "post", ContentID: 2, Slug: "slug-antigo"})
+
+	mux := http.NewServeMux()
+	handlers.NewPublicHandler(repositories.NewPostRepository(db), repositories.NewProjectRepository(db)).Register(mux)
+	return mux, &tag, &cat
+}
+
+func TestPublicHandler(t *testing.T) {
+	mux, tag, _ := setupPublicMux()
+
+	t.Run("Deve listar apenas posts publicados com metadados de paginação", func(t *testing.T) {
+		var res dtos.PaginatedResponse[dtos.ContentResponse]
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts?page=1&page_size=1", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, res.Data, 1)
+		assert.Equal(t, int64(2), res.Pagination.Total)
+		assert.Equal(t, 2, res.Pagination.TotalPages)
+		assert.Equal(t, 1, res.Pagination.PageSize)
+	})
+
+	t.Run("Deve filtrar posts por tag via Search", func(t *testing.T) {
+		var res dtos.PaginatedResponse[dtos.ContentResponse]
+		req := httptest.NewRequest(http.MethodGet, "/api/posts?tag_id=1", nil)
+		doRequest(t, mux, req, &res)
+
+		if assert.Len(t, res.Data, 1) {
+			assert.Equal(t, "publicado", res.Data[0].Slug)
+			assert.Equal(t, "post", res.Data[0].Type)
+			assert.Equal(t, tag.Title, res.Data[0].Tags[0].Title)
+			assert.Equal(t, "Backend", res.Data[0].Categories[0].Title)
+		}
+	})
+
+	t.Run("Deve retornar post por slug", func(t *testing.T) {
+		var res dtos.ContentResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/publicado", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Publicado", res.Title)
+		assert.NotNil(t, res.PostedAt)
+	})
+
+	t.Run("Não deve expor rascunhos nem agendados", func(t *testing.T) {
+		for _, slug := range []string{"rascunho", "agendado"} {
+			rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/"+slug, nil), nil)
+			assert.Equal(t, http.StatusNotFound, rec.Code, slug)
+		}
+	})
+
+	t.Run("Deve listar projetos publicados com tipo project", func(t *testing.T) {
+		var res dtos.PaginatedResponse[dtos.ContentResponse]
+		doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/projects", nil), &res)
+
+		if assert.Len(t, res.Data, 1) {
+			assert.Equal(t, "project", res.Data[0].Type)
+			assert.Equal(t, "https://demo.dev", res.Data[0].DemoURL)
+			assert.NotNil(t, res.Data[0].Tags, "tags devem ser [] e não null")
+		}
+		assert.Equal(t, int64(1), res.Pagination.Total)
+	})
+
+	t.Run("Deve responder 301 para slug antigo de post renomeado", func(t *testing.T) {
+		var res dtos.RedirectResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/slug-antigo", nil), &res)
+
+		assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+		assert.Equal(t, "/api/posts/outro", rec.Header().Get("Location"))
+		assert.Equal(t, "/posts/outro", res.MovedTo)
+	})
+
+	t.Run("Deve retornar 404 para projeto não publicado", func(t *testing.T) {
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/projects/secreto", nil), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestPublicHandler_Search(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	past := time.Now().UTC().Add(-time.Hour)
+
+	golang := models.Tag{Title: "Go"}
+	sql := models.Tag{Title: "SQL"}
+	backend := models.Category{Title: "Backend"}
+	db.Create(&golang)
+	db.Create(&sql)
+	db.Create(&backend)
+
+	repo.Create(&models.Post{Title: "Canais em Go", Slug: "canais", Body: "<p>Sobre <b>canais</b> e goroutines</p>", PostedAt: &past, Tags: []models.Tag{golang}, Categories: []models.Category{backend}})
+	repo.Create(&models.Post{Title: "Diário", Slug: "diario", Body: "<p>Usei canais no banco</p>", PostedAt: &past, Tags: []models.Tag{golang, sql}})
+	repo.Create(&models.Post{Title: "Canais secretos", Slug: "secreto", Body: "rascunho"})
+
+	mux := http.NewServeMux()
+	handlers.NewPublicHandler(repo, repositories.NewProjectRepository(db)).Register(mux)
+
+	t.Run("Deve retornar trechos destacados, relevância e facetas", func(t *testing.T) {
+		var res dtos.SearchResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/search?q=canais", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int64(2), res.Pagination.Total, "rascunhos ficam de fora")
+		if assert.Len(t, res.Data, 2) {
+			assert.Equal(t, "canais", res.Data[0].Slug, "título pesa mais que o corpo")
+			assert.Equal(t, "<mark>Canais</mark> em Go", res.Data[0].TitleHighlight)
+			assert.Equal(t, "Sobre <mark>canais</mark> e goroutines", res.Data[0].Snippet)
+			assert.GreaterOrEqual(t, res.Data[0].Score, res.Data[1].Score)
+			assert.Equal(t, "post", res.Data[0].Type)
+		}
+
+		if assert.Len(t, res.Facets.Tags, 2) {
+			assert.Equal(t, dtos.FacetResponse{ID: golang.ID, Title: "Go", Count: 2}, res.Facets.Tags[0])
+			assert.Equal(t, int64(1), res.Facets.Tags[1].Count)
+		}
+		if assert.Len(t, res.Facets.Categories, 1) {
+			assert.Equal(t, int64(1), res.Facets.Categories[0].Count)
+		}
+	})
+
+	t.Run("Facetas acompanham o filtro aplicado", func(t *testing.T) {
+		var res dtos.SearchResponse
+		doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/search?q=canais&tag_id=2", nil), &res)
+
+		assert.Equal(t, int64(1), res.Pagination.Total)
+		assert.Len(t, res.Facets.Tags, 2)
+		assert.Empty(t, res.Facets.Categories)
+	})
+
+	t.Run("Deve combinar listas de tags e exclusões na listagem", func(t *testing.T) {
+		var res dtos.PaginatedResponse[dtos.ContentResponse]
+		path := fmt.Sprintf("/api/posts?tag_id=%d&tag_id_not=%d", golang.ID, sql.ID)
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, path, nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, res.Data, 1) {
+			assert.Equal(t, "canais", res.Data[0].Slug)
+		}
+
+		path = fmt.Sprintf("/api/posts/search?q=canais&tag_id=%d,%d", golang.ID, sql.ID)
+		var hits dtos.SearchResponse
+		doRequest(t, mux, httptest.NewRequest(http.MethodGet, path, nil), &hits)
+		assert.Equal(t, int64(1), hits.Pagination.Total)
+	})
+
+	t.Run("Deve filtrar por data, com posted_to incluindo o dia inteiro", func(t *testing.T) {
+		today := past.Format("2006-01-02")
+		var res dtos.PaginatedResponse[dtos.ContentResponse]
+		doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts?posted_from="+today+"&posted_to="+today, nil), &res)
+		assert.Equal(t, int64(2), res.Pagination.Total)
+
+		doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts?posted_to=2000-01-01", nil), &res)
+		assert.Equal(t, int64(0), res.Pagination.Total)
+	})
+
+	t.Run("Deve rejeitar filtros malformados com 422", func(t *testing.T) {
+		for _, query := range []string{"tag_id=go", "category_id_any=1,x", "posted_from=ontem", "posted_from=2024-02-01&posted_to=2024-01-01"} {
+			rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/search?"+query, nil), nil)
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, query)
+		}
+	})
+
+	t.Run("Sem texto lista por data com listas vazias serializadas", func(t *testing.T) {
+		var res dtos.SearchResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts/search?category_id=999", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotNil(t, res.Data)
+		assert.NotNil(t, res.Facets.Tags)
+	})
+}
+
+func TestPublicHandler_Cursor(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	base := time.Now().UTC().Add(-24 * time.Hour)
+
+	for i := range 3 {
+		at := base.Add(time.Duration(i) * time.Hour)
+		repo.Create(&models.Post{Title: fmt.Sprintf("Post %d", i), Slug: fmt.Sprintf("post-%d", i), PostedAt: &at})
+	}
+	repo.Create(&models.Post{Title: "Rascunho", Slug: "rascunho"})
+
+	mux := http.NewServeMux()
+	handlers.NewPublicHandler(repo, repositories.NewProjectRepository(db)).Register(mux)
+
+	t.Run("Deve paginar por cursor sem total por padrão", func(t *testing.T) {
+		var first dtos.CursorResponse[dtos.ContentResponse]
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts?cursor=&page_size=2", nil), &first)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Nil(t, first.Pagination.Total)
+		assert.True(t, first.Pagination.HasMore)
+		if assert.Len(t, first.Data, 2) {
+			assert.Equal(t, "post-2", first.Data[0].Slug)
+		}
+
+		var second dtos.CursorResponse[dtos.ContentResponse]
+		doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/posts?page_size=2&with_total=true&cursor="+first.Pagination.NextCursor, nil), &second)
+		if assert.Len(t, second.Data, 1, "rascunho nunca aparece") {
+			assert.Equal(t, "post-0", second.Data[0].Slug)
+		}
+		assert.Empty(t, second.Pagination.NextCursor)
+		if assert.NotNil(t, second.Pagination.Total) {
+			assert.Equal(t, int64(3), *second.Pagination.Total)
+		}
+	})
+
+	t.Run("Deve rejeitar cursor inválido", func(t *testing.T) {
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/projects?cursor=nao-e-cursor", nil), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"cms-headless/internal/validators"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("Falha ao serializar resposta: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// Lê um inteiro da query string, retornando o padrão se ausente ou inválido
+func queryInt(r *http.Request, key string, def int) int {
+	v, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Substantivos femininos usados como entity nas respostas de erro
+var feminineEntities = map[string]bool{"tag": true, "categoria": true, "revisão": true}
+
+func notFoundMessage(entity string) string {
+	if feminineEntities[entity] {
+		return entity + " não encontrada"
+	}
+	return entity + " não encontrado"
+}
+
+type validationErrorResponse struct {
+	Error  string                  `json:"error"`
+	Fields []validators.FieldError `json:"fields"`
+}
+
+func writeValidationErrors(w http.ResponseWriter, fields []validators.FieldError) {
+	writeJSON(w, http.StatusUnprocessableEntity, validationErrorResponse{Error: "dados inválidos", Fields: fields})
+}
+
+// Limite de 1MB por requisição para evitar payloads abusivos
+const maxBodyBytes = 1 << 20
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, out any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(out); err != nil {
+		writeError(w, http.StatusBadRequest, "JSON inválido: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// Decodifica o JSON e aplica as regras declaradas nas tags do DTO
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, out any) bool {
+	if !decodeJSON(w, r, out) {
+		return false
+	}
+	if errs := validators.ValidateStruct(out); len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return false
+	}
+	return true
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil || id == 0 {
+		writeError(w, http.StatusBadRequest, "id inválido")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/services"
+	"cms-headless/internal/utils"
+	"net/http"
+	"strconv"
+)
+
+// Histórico de revisões de posts e projetos. Deve ser registrada atrás do auth.Middleware.
+type RevisionHandler struct {
+	revisions services.RevisionService
+	posts     services.PostService
+	projects  services.ProjectService
+}
+
+func NewRevisionHandler(revisions services.RevisionService, posts services.PostService, projects services.ProjectService) *RevisionHandler {
+	return &RevisionHandler{revisions: revisions, posts: posts, projects: projects}
+}
+
+func (h *RevisionHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/posts/{id}/revisions", h.list(dtos.TypePost))
+	mux.HandleFunc("GET /admin/posts/{id}/revisions/diff", h.diff(dtos.TypePost))
+	mux.HandleFunc("GET /admin/posts/{id}/revisions/{number}", h.get(dtos.TypePost))
+	mux.HandleFunc("POST /admin/posts/{id}/revisions/{number}/restore", h.restorePost)
+
+	mux.HandleFunc("GET /admin/projects/{id}/revisions", h.list(dtos.TypeProject))
+	mux.HandleFunc("GET /admin/projects/{id}/revisions/diff", h.diff(dtos.TypeProject))
+	mux.HandleFunc("GET /admin/projects/{id}/revisions/{number}", h.get(dtos.TypeProject))
+	mux.HandleFunc("POST /admin/projects/{id}/revisions/{number}/restore", h.restoreProject)
+}
+
+func (h *RevisionHandler) list(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+		revisions, total, err := h.revisions.List(r.Context(), contentType, id, page, pageSize)
+		if err != nil {
+			writeServiceError(w, err, "revisão")
+			return
+		}
+		writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.RevisionSummaryResponse]{
+			Data:       dtos.FromRevisions(revisions),
+			Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+		})
+	}
+}
+
+func (h *RevisionHandler) get(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		number, ok := pathNumber(w, r)
+		if !ok {
+			return
+		}
+		rev, err := h.revisions.Get(r.Context(), contentType, id, number)
+		if err != nil {
+			writeServiceError(w, err, "revisão")
+			return
+		}
+		writeJSON(w, http.StatusOK, dtos.FromRevision(*rev))
+	}
+}
+
+// ?from=N&to=M
+func (h *RevisionHandler) diff(contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		from, to := queryInt(r, "from", 0), queryInt(r, "to", 0)
+		if from <= 0 || to <= 0 {
+			writeError(w, http.StatusBadRequest, "informe from e to")
+			return
+		}
+		res, err := h.revisions.Diff(r.Context(), contentType, id, from, to)
+		if err != nil {
+			writeServiceError(w, err, "revisão")
+			return
+		}
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+func (h *RevisionHandler) restorePost(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	number, ok := pathNumber(w, r)
+	if !ok {
+		return
+	}
+	post, err := h.posts.RestoreRevision(r.Context(), id, number)
+	if err != nil {
+		writeServiceError(w, err, "revisão")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromPost(*post))
+}
+
+func (h *RevisionHandler) restoreProject(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	number, ok := pathNumber(w, r)
+	if !ok {
+		return
+	}
+	project, err := h.projects.RestoreRevision(r.Context(), id, number)
+	if err != nil {
+		writeServiceError(w, err, "revisão")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromProject(*project))
+}
+
+func pathNumber(w http.ResponseWriter, r *http.Request) (int, bool) {
+	number, err := strconv.Atoi(r.PathValue("number"))
+	if err != nil || number <= 0 {
+		writeError(w, http.StatusBadRequest, "número de revisão inválido")
+		return 0, false
+	}
+	return number, true
+}
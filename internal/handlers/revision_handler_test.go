@@ -0,0 +1,61 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/services"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisionHandler(t *testing.T) {
+	db := SetupTestDB()
+	posts, projects := services.NewPostService(db), services.NewProjectService(db)
+	adminMux := http.NewServeMux()
+	handlers.NewAdminHandler(posts, projects).Register(adminMux)
+	handlers.NewRevisionHandler(services.NewRevisionService(db), posts, projects).Register(adminMux)
+	mux := auth.Middleware(auth.ParseTokens("editor:"+adminToken), adminMux)
+
+	doRequest(t, mux, adminRequest(http.MethodPost, "/admin/contents", `{"type":"post","title":"Versão Um","body":"a"}`), nil)
+	doRequest(t, mux, adminRequest(http.MethodPut, "/admin/posts/1", `{"type":"post","title":"Versão Dois","body":"b"}`), nil)
+
+	t.Run("Deve listar revisões com autor do token", func(t *testing.T) {
+		var res dtos.PaginatedResponse[dtos.RevisionSummaryResponse]
+		rec := doRequest(t, mux, adminRequest(http.MethodGet, "/admin/posts/1/revisions", ""), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int64(2), res.Pagination.Total)
+		assert.Equal(t, "editor", res.Data[0].Author)
+	})
+
+	t.Run("Deve retornar diff entre revisões", func(t *testing.T) {
+		var res dtos.RevisionDiffResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodGet, "/admin/posts/1/revisions/diff?from=1&to=2", ""), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, res.Changes)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, "/admin/posts/1/revisions/diff", ""), nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Deve restaurar revisão", func(t *testing.T) {
+		var res dtos.ContentResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/posts/1/revisions/1/restore", ""), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Versão Um", res.Title)
+	})
+
+	t.Run("Deve retornar 404 para revisão inexistente", func(t *testing.T) {
+		var res struct {
+			Error string `json:"error"`
+		}
+		rec := doRequest(t, mux, adminRequest(http.MethodGet, "/admin/projects/1/revisions/1", ""), &res)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "revisão não encontrada", res.Error)
+	})
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/utils"
+	"cms-headless/internal/validators"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Busca pública unificada: posts e projetos publicados numa única lista por relevância
+type SearchHandler struct {
+	contents repositories.ContentSearchRepository
+}
+
+func NewSearchHandler(contents repositories.ContentSearchRepository) *SearchHandler {
+	return &SearchHandler{contents: contents}
+}
+
+func (h *SearchHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/search", h.search)
+}
+
+func (h *SearchHandler) search(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := utils.NormalizePagination(queryInt(r, "page", 1), queryInt(r, "page_size", 0))
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeValidationErrors(w, []validators.FieldError{{Field: "q", Message: "campo obrigatório"}})
+		return
+	}
+
+	hits, total, err := h.contents.WithContext(r.Context()).Search(page, pageSize, q, true)
+	if err != nil {
+		log.Printf("Falha na busca unificada: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao buscar conteúdos")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dtos.PaginatedResponse[dtos.SearchHitResponse]{
+		Data:       dtos.FromContentHits(hits, q),
+		Pagination: dtos.NewPaginationMeta(page, pageSize, total),
+	})
+}
@@ -0,0 +1,60 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchHandler(t *testing.T) {
+	db := SetupTestDB()
+	past := time.Now().UTC().Add(-time.Hour)
+	posts := repositories.NewPostRepository(db)
+	projects := repositories.NewProjectRepository(db)
+
+	posts.Create(&models.Post{Title: "Filas com Go", Slug: "filas", Body: "<p>workers</p>", PostedAt: &past})
+	projects.Create(&models.Project{Title: "Broker", Slug: "broker", Body: "<p>um gerenciador de filas</p>", DemoURL: "https://demo.dev", PostedAt: &past})
+
+	mux := http.NewServeMux()
+	handlers.NewPublicHandler(posts, projects).Register(mux)
+	handlers.NewSearchHandler(repositories.NewContentSearchRepository(db)).Register(mux)
+
+	t.Run("Deve retornar posts e projetos com o tipo preenchido", func(t *testing.T) {
+		var res dtos.PaginatedResponse[dtos.SearchHitResponse]
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/search?q=filas", nil), &res)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, int64(2), res.Pagination.Total)
+		if assert.Len(t, res.Data, 2) {
+			assert.Equal(t, dtos.TypePost, res.Data[0].Type)
+			assert.Equal(t, dtos.TypeProject, res.Data[1].Type)
+			assert.Equal(t, "https://demo.dev", res.Data[1].DemoURL)
+			assert.Equal(t, "um gerenciador de <mark>filas</mark>", res.Data[1].Snippet)
+		}
+	})
+
+	t.Run("Deve exigir o texto da busca", func(t *testing.T) {
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/search?q=%20", nil), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Deve buscar só projetos com facetas e filtrar a listagem", func(t *testing.T) {
+		var res dtos.SearchResponse
+		rec := doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/projects/search?q=filas", nil), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, res.Data, 1) {
+			assert.Equal(t, "broker", res.Data[0].Slug)
+		}
+
+		var list dtos.PaginatedResponse[dtos.ContentResponse]
+		doRequest(t, mux, httptest.NewRequest(http.MethodGet, "/api/projects?q=inexistente", nil), &list)
+		assert.Equal(t, int64(0), list.Pagination.Total)
+	})
+}
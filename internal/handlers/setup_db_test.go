@@ -0,0 +1,33 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/search"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func SetupTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	models.Migrate(db)
+	search.Setup(db)
+	return db
+}
+
+// Executa a requisição contra o mux e decodifica o JSON de resposta em out
+func doRequest(t *testing.T, mux http.Handler, req *http.Request, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("resposta não é JSON válido: %v (%s)", err, rec.Body.String())
+		}
+	}
+	return rec
+}
@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/services"
+	"cms-headless/internal/validators"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Administração de tags e categorias. Deve ser registrada atrás do auth.Middleware.
+// As respostas incluem o uso (posts e projetos publicados) de cada item.
+type TaxonomyHandler struct {
+	taxonomies services.TaxonomyService
+}
+
+func NewTaxonomyHandler(taxonomies services.TaxonomyService) *TaxonomyHandler {
+	return &TaxonomyHandler{taxonomies: taxonomies}
+}
+
+func (h *TaxonomyHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/tags", h.listTags)
+	mux.HandleFunc("GET /admin/tags/lookup", h.lookupTag)
+	mux.HandleFunc("GET /admin/tags/autocomplete", h.autocompleteTags)
+	mux.HandleFunc("POST /admin/tags/merge", h.mergeTags)
+	mux.HandleFunc("GET /admin/tags/{id}", h.getTag)
+	mux.HandleFunc("PUT /admin/tags/{id}", h.updateTag)
+	mux.HandleFunc("DELETE /admin/tags/{id}", h.deleteTag)
+	mux.HandleFunc("GET /admin/tags/{id}/aliases", h.listTagAliases)
+	mux.HandleFunc("POST /admin/tags/{id}/aliases", h.addTagAlias)
+	mux.HandleFunc("DELETE /admin/tags/{id}/aliases/{aliasID}", h.deleteTagAlias)
+	mux.HandleFunc("GET /admin/posts/{id}/tag-suggestions", h.suggestPostTags)
+	mux.HandleFunc("GET /admin/projects/{id}/tag-suggestions", h.suggestProjectTags)
+
+	mux.HandleFunc("GET /admin/categories", h.listCategories)
+	mux.HandleFunc("GET /admin/categories/lookup", h.lookupCategory)
+	mux.HandleFunc("POST /admin/categories/merge", h.mergeCategories)
+	mux.HandleFunc("GET /admin/categories/{id}", h.getCategory)
+	mux.HandleFunc("PUT /admin/categories/{id}", h.updateCategory)
+	mux.HandleFunc("PUT /admin/categories/{id}/parent", h.moveCategory)
+	mux.HandleFunc("DELETE /admin/categories/{id}", h.deleteCategory)
+}
+
+// Sempre por cursor, das mais recentes para as mais antigas
+func (h *TaxonomyHandler) listTags(w http.ResponseWriter, r *http.Request) {
+	q, errs := cursorQuery(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	page, usage, err := h.taxonomies.ListTags(r.Context(), q)
+	if err != nil {
+		log.Printf("Falha ao listar tags: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar tags")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.CursorResponse[dtos.TaxonomyResponse]{
+		Data:       dtos.FromTagsWithUsage(page.Items, usage),
+		Pagination: dtos.NewCursorMeta(q.Limit, page.Next, page.Total),
+	})
+}
+
+func (h *TaxonomyHandler) getTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	tag, usage, err := h.taxonomies.GetTag(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	res := dtos.FromTagDetail(*tag)
+	res.TaxonomyResponse = dtos.FromTaxonomyUsage(res.TaxonomyResponse, usage)
+	writeJSON(w, http.StatusOK, res)
+}
+
+// ?title= sem diferenciar caixa nem acentos; aliases resolvem para a tag canônica
+func (h *TaxonomyHandler) lookupTag(w http.ResponseWriter, r *http.Request) {
+	title, ok := queryTitle(w, r)
+	if !ok {
+		return
+	}
+	tag, usage, err := h.taxonomies.FindTagByTitle(r.Context(), title)
+	if err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTaxonomyUsage(dtos.FromTag(*tag), usage))
+}
+
+// ?q= com o que o editor digitou; ?limit= (padrão 10, máximo 50)
+func (h *TaxonomyHandler) autocompleteTags(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeValidationErrors(w, []validators.FieldError{{Field: "q", Message: "campo obrigatório"}})
+		return
+	}
+	matches, err := h.taxonomies.AutocompleteTags(r.Context(), q, queryInt(r, "limit", 0))
+	if err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTagMatches(matches))
+}
+
+// Palavras-chave do post e tags existentes que aparecem nele, exceto as que já tem
+func (h *TaxonomyHandler) suggestPostTags(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	suggestions, err := h.taxonomies.SuggestPostTags(r.Context(), id, queryInt(r, "limit", 0))
+	if err != nil {
+		writeServiceError(w, err, "post")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTagSuggestions(suggestions))
+}
+
+func (h *TaxonomyHandler) suggestProjectTags(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	suggestions, err := h.taxonomies.SuggestProjectTags(r.Context(), id, queryInt(r, "limit", 0))
+	if err != nil {
+		writeServiceError(w, err, "projeto")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTagSuggestions(suggestions))
+}
+
+// Título, slug, descrição e SEO; slug antigo passa a redirecionar
+func (h *TaxonomyHandler) updateTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.TaxonomyInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	tag, err := h.taxonomies.UpdateTag(r.Context(), id, in)
+	if err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTagDetail(*tag))
+}
+
+// ?reassign_to=<id> move os itens para outra tag antes de remover
+func (h *TaxonomyHandler) deleteTag(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	reassignTo, ok := queryReassignTo(w, r)
+	if !ok {
+		return
+	}
+	if err := h.taxonomies.DeleteTag(r.Context(), id, reassignTo); err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaxonomyHandler) mergeTags(w http.ResponseWriter, r *http.Request) {
+	var in dtos.MergeInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	tag, usage, err := h.taxonomies.MergeTags(r.Context(), in.SourceIDs, in.TargetID)
+	if err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTaxonomyUsage(dtos.FromTag(*tag), usage))
+}
+
+func (h *TaxonomyHandler) listTagAliases(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	aliases, err := h.taxonomies.ListTagAliases(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTagAliases(aliases))
+}
+
+// 409 quando o nome já é título de uma tag ou alias de qualquer tag
+func (h *TaxonomyHandler) addTagAlias(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.TagAliasInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	alias, err := h.taxonomies.AddTagAlias(r.Context(), id, in.Alias)
+	if err != nil {
+		writeServiceError(w, err, "tag")
+		return
+	}
+	writeJSON(w, http.StatusCreated, dtos.FromTagAlias(*alias))
+}
+
+func (h *TaxonomyHandler) deleteTagAlias(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	aliasID, err := strconv.ParseUint(r.PathValue("aliasID"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "id inválido")
+		return
+	}
+	if err := h.taxonomies.DeleteTagAlias(r.Context(), id, uint(aliasID)); err != nil {
+		writeServiceError(w, err, "alias")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaxonomyHandler) listCategories(w http.ResponseWriter, r *http.Request) {
+	q, errs := cursorQuery(r)
+	if len(errs) > 0 {
+		writeValidationErrors(w, errs)
+		return
+	}
+	page, usage, err := h.taxonomies.ListCategories(r.Context(), q)
+	if err != nil {
+		log.Printf("Falha ao listar categorias: %v", err)
+		writeError(w, http.StatusInternalServerError, "erro ao listar categorias")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.CursorResponse[dtos.TaxonomyResponse]{
+		Data:       dtos.FromCategoriesWithUsage(page.Items, usage),
+		Pagination: dtos.NewCursorMeta(q.Limit, page.Next, page.Total),
+	})
+}
+
+func (h *TaxonomyHandler) getCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	category, usage, err := h.taxonomies.GetCategory(r.Context(), id)
+	if err != nil {
+		writeServiceError(w, err, "categoria")
+		return
+	}
+	res := dtos.FromCategoryDetail(*category)
+	res.TaxonomyResponse = dtos.FromTaxonomyUsage(res.TaxonomyResponse, usage)
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *TaxonomyHandler) lookupCategory(w http.ResponseWriter, r *http.Request) {
+	title, ok := queryTitle(w, r)
+	if !ok {
+		return
+	}
+	category, usage, err := h.taxonomies.FindCategoryByTitle(r.Context(), title)
+	if err != nil {
+		writeServiceError(w, err, "categoria")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTaxonomyUsage(dtos.FromCategory(*category), usage))
+}
+
+func (h *TaxonomyHandler) updateCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.TaxonomyInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	category, err := h.taxonomies.UpdateCategory(r.Context(), id, in)
+	if err != nil {
+		writeServiceError(w, err, "categoria")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromCategoryDetail(*category))
+}
+
+// {"parent_id": 3, "position": 0}; parent_id null torna raiz
+func (h *TaxonomyHandler) moveCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var in dtos.CategoryMoveInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	category, err := h.taxonomies.MoveCategory(r.Context(), id, in)
+	if err != nil {
+		writeServiceError(w, err, "categoria")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromCategoryDetail(*category))
+}
+
+func (h *TaxonomyHandler) deleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	reassignTo, ok := queryReassignTo(w, r)
+	if !ok {
+		return
+	}
+	if err := h.taxonomies.DeleteCategory(r.Context(), id, reassignTo); err != nil {
+		writeServiceError(w, err, "categoria")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaxonomyHandler) mergeCategories(w http.ResponseWriter, r *http.Request) {
+	var in dtos.MergeInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	category, usage, err := h.taxonomies.MergeCategories(r.Context(), in.SourceIDs, in.TargetID)
+	if err != nil {
+		writeServiceError(w, err, "categoria")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromTaxonomyUsage(dtos.FromCategory(*category), usage))
+}
+
+func queryTitle(w http.ResponseWriter, r *http.Request) (string, bool) {
+	title := strings.TrimSpace(r.URL.Query().Get("title"))
+	if title == "" {
+		writeValidationErrors(w, []validators.FieldError{{Field: "title", Message: "campo obrigatório"}})
+		return "", false
+	}
+	return title, true
+}
+
+// Ausente é 0 (remover sem reatribuir)
+func queryReassignTo(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	v := r.URL.Query().Get("reassign_to")
+	if v == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || id == 0 {
+		writeValidationErrors(w, []validators.FieldError{{Field: "reassign_to", Message: "deve ser um id numérico"}})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,214 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaxonomyHandler(t *testing.T) {
+	db := SetupTestDB()
+	adminMux := http.NewServeMux()
+	handlers.NewTaxonomyHandler(services.NewTaxonomyService(db)).Register(adminMux)
+	mux := auth.Middleware(auth.ParseTokens("editor:"+adminToken), adminMux)
+
+	t.Run("Deve renomear categoria e responder 409 para título em uso", func(t *testing.T) {
+		db.Create(&models.Category{Title: "Backend"})
+		db.Create(&models.Category{Title: "Frontend"})
+
+		var res dtos.TaxonomyResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/1", `{"title":"Servidor"}`), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Servidor", res.Title)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/1", `{"title":"Frontend"}`), nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("Deve editar slug, descrição e SEO da categoria", func(t *testing.T) {
+		var res dtos.TaxonomyDetailResponse
+		body := `{"title":"Servidor","slug":"Back End","description":"APIs e bancos","meta_description":"Posts sobre servidor"}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/1", body), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "back-end", res.Slug)
+		assert.Equal(t, "APIs e bancos", res.Description)
+
+		doRequest(t, mux, adminRequest(http.MethodGet, "/admin/categories/1", ""), &res)
+		assert.Equal(t, "Posts sobre servidor", res.MetaDescription)
+		assert.NotNil(t, res.Usage)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/2", `{"title":"Frontend","slug":"back-end"}`), nil)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+
+		long := fmt.Sprintf(`{"title":"Servidor","meta_title":"%s"}`, strings.Repeat("a", 71))
+		rec = doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/1", long), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Deve mover categoria recusando ciclos", func(t *testing.T) {
+		var res dtos.TaxonomyDetailResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/2/parent", `{"parent_id":1}`), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.NotNil(t, res.ParentID) {
+			assert.Equal(t, uint(1), *res.ParentID)
+		}
+
+		for _, body := range []string{`{"parent_id":2}`, `{"parent_id":999}`, `{"parent_id":null,"position":-1}`} {
+			rec = doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/1/parent", body), nil)
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, body)
+		}
+
+		var root dtos.TaxonomyDetailResponse
+		rec = doRequest(t, mux, adminRequest(http.MethodPut, "/admin/categories/2/parent", `{"parent_id":null}`), &root)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Nil(t, root.ParentID)
+	})
+
+	t.Run("Deve listar categorias por cursor", func(t *testing.T) {
+		var first dtos.CursorResponse[dtos.TaxonomyResponse]
+		rec := doRequest(t, mux, adminRequest(http.MethodGet, "/admin/categories?page_size=1&with_total=true", ""), &first)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Len(t, first.Data, 1)
+		assert.True(t, first.Pagination.HasMore)
+		if assert.NotNil(t, first.Pagination.Total) {
+			assert.Equal(t, int64(2), *first.Pagination.Total)
+		}
+
+		var second dtos.CursorResponse[dtos.TaxonomyResponse]
+		doRequest(t, mux, adminRequest(http.MethodGet, "/admin/categories?page_size=1&cursor="+first.Pagination.NextCursor, ""), &second)
+		if assert.Len(t, second.Data, 1) {
+			assert.NotEqual(t, first.Data[0].ID, second.Data[0].ID)
+		}
+		assert.False(t, second.Pagination.HasMore)
+		assert.Nil(t, second.Pagination.Total)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, "/admin/tags?cursor=lixo", ""), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Deve fundir tags, mostrar o uso e remover com reatribuição", func(t *testing.T) {
+		past := time.Now().UTC().Add(-time.Hour)
+		golang := models.Tag{Title: "Go"}
+		golangAlt := models.Tag{Title: "Golang"}
+		velha := models.Tag{Title: "Velha"}
+		db.Create(&golang)
+		db.Create(&golangAlt)
+		db.Create(&velha)
+		repositories.NewPostRepository(db).Create(&models.Post{Title: "Post", Slug: "post", PostedAt: &past, Tags: []models.Tag{golangAlt, velha}})
+
+		var merged dtos.TaxonomyResponse
+		body := fmt.Sprintf(`{"source_ids":[%d],"target_id":%d}`, golangAlt.ID, golang.ID)
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/tags/merge", body), &merged)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.NotNil(t, merged.Usage) {
+			assert.Equal(t, dtos.TaxonomyUsageResponse{Posts: 1}, *merged.Usage)
+		}
+
+		var found dtos.TaxonomyResponse
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, "/admin/tags/lookup?title=go", ""), &found)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, golang.ID, found.ID)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, fmt.Sprintf("/admin/tags/%d", golangAlt.ID), ""), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, fmt.Sprintf("/admin/tags/%d?reassign_to=999", velha.ID), ""), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, fmt.Sprintf("/admin/tags/%d?reassign_to=%d", velha.ID, golang.ID), ""), nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		var list dtos.CursorResponse[dtos.TaxonomyResponse]
+		doRequest(t, mux, adminRequest(http.MethodGet, "/admin/tags", ""), &list)
+		if assert.Len(t, list.Data, 1) {
+			assert.Equal(t, int64(1), list.Data[0].Usage.Posts)
+		}
+	})
+
+	t.Run("Deve cadastrar aliases e resolver o lookup por eles", func(t *testing.T) {
+		var alias dtos.TagAliasResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/tags/1/aliases", `{"alias":"Linguagem Go"}`), &alias)
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "Linguagem Go", alias.Alias)
+
+		var found dtos.TaxonomyResponse
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, "/admin/tags/lookup?title=linguagem%20go", ""), &found)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, uint(1), found.ID)
+
+		var aliases []dtos.TagAliasResponse
+		doRequest(t, mux, adminRequest(http.MethodGet, "/admin/tags/1/aliases", ""), &aliases)
+		assert.Len(t, aliases, 3, "títulos das tags fundidas ou reatribuídas também viram alias")
+
+		for body, code := range map[string]int{`{"alias":"golang"}`: http.StatusConflict, `{"alias":"GO"}`: http.StatusConflict, `{"alias":"  "}`: http.StatusUnprocessableEntity} {
+			rec = doRequest(t, mux, adminRequest(http.MethodPost, "/admin/tags/1/aliases", body), nil)
+			assert.Equal(t, code, rec.Code, body)
+		}
+
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, fmt.Sprintf("/admin/tags/1/aliases/%d", alias.ID), ""), nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, fmt.Sprintf("/admin/tags/1/aliases/%d", alias.ID), ""), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Deve validar a fusão", func(t *testing.T) {
+		for _, body := range []string{`{"target_id":1}`, `{"source_ids":[1],"target_id":1}`, `{"source_ids":[998],"target_id":1}`} {
+			rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/categories/merge", body), nil)
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, body)
+		}
+	})
+}
+
+func TestTaxonomyHandler_Suggestions(t *testing.T) {
+	db := SetupTestDB()
+	adminMux := http.NewServeMux()
+	handlers.NewTaxonomyHandler(services.NewTaxonomyService(db)).Register(adminMux)
+	mux := auth.Middleware(auth.ParseTokens("editor:"+adminToken), adminMux)
+
+	golang := models.Tag{Title: "Go"}
+	docker := models.Tag{Title: "Docker"}
+	db.Create(&golang)
+	db.Create(&docker)
+	db.Create(&models.TagAlias{TagID: docker.ID, Alias: "Containers"})
+	post := models.Post{Title: "Deploy", Slug: "deploy", Body: "<p>Empacotando um serviço em Go com containers.</p>", Tags: []models.Tag{golang}}
+	repositories.NewPostRepository(db).Create(&post)
+
+	t.Run("Deve completar tags pelo prefixo e exigir q", func(t *testing.T) {
+		var res []dtos.TagAutocompleteResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodGet, "/admin/tags/autocomplete?q=conta", ""), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "Docker", res[0].Title)
+			assert.Equal(t, "Containers", res[0].Alias)
+			assert.NotNil(t, res[0].Usage)
+		}
+
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, "/admin/tags/autocomplete?q=%20", ""), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Deve sugerir tags do post que ele ainda não tem", func(t *testing.T) {
+		var res dtos.TagSuggestionsResponse
+		rec := doRequest(t, mux, adminRequest(http.MethodGet, fmt.Sprintf("/admin/posts/%d/tag-suggestions", post.ID), ""), &res)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, res.Keywords)
+		if assert.Len(t, res.Tags, 1) {
+			assert.Equal(t, docker.ID, res.Tags[0].ID)
+			assert.Equal(t, "Containers", res.Tags[0].Keyword)
+		}
+
+		rec = doRequest(t, mux, adminRequest(http.MethodGet, "/admin/projects/999/tag-suggestions", ""), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/services"
+	"net/http"
+)
+
+// Cadastro de assinaturas de webhook. Deve ser registrada atrás do auth.Middleware.
+type WebhookHandler struct {
+	webhooks services.WebhookService
+}
+
+func NewWebhookHandler(webhooks services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhooks: webhooks}
+}
+
+func (h *WebhookHandler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/webhooks", h.list)
+	mux.HandleFunc("POST /admin/webhooks", h.create)
+	mux.HandleFunc("DELETE /admin/webhooks/{id}", h.delete)
+}
+
+func (h *WebhookHandler) list(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.webhooks.List(r.Context())
+	if err != nil {
+		writeServiceError(w, err, "webhook")
+		return
+	}
+	writeJSON(w, http.StatusOK, dtos.FromWebhooks(subs))
+}
+
+func (h *WebhookHandler) create(w http.ResponseWriter, r *http.Request) {
+	var in dtos.WebhookInput
+	if !decodeAndValidate(w, r, &in) {
+		return
+	}
+	sub, err := h.webhooks.Create(r.Context(), in)
+	if err != nil {
+		writeServiceError(w, err, "webhook")
+		return
+	}
+
+	res := dtos.FromWebhook(*sub)
+	res.Secret = sub.Secret
+	writeJSON(w, http.StatusCreated, res)
+}
+
+func (h *WebhookHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.webhooks.Delete(r.Context(), id); err != nil {
+		writeServiceError(w, err, "webhook")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
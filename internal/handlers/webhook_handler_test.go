@@ -0,0 +1,48 @@
+package handlers_test
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/handlers"
+	"cms-headless/internal/services"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	db := SetupTestDB()
+	adminMux := http.NewServeMux()
+	handlers.NewWebhookHandler(services.NewWebhookService(db)).Register(adminMux)
+	mux := auth.Middleware(auth.ParseTokens("editor:"+adminToken), adminMux)
+
+	t.Run("Deve cadastrar webhook expondo o secret apenas na criação", func(t *testing.T) {
+		var created dtos.WebhookResponse
+		body := `{"url":"https://site.dev/api/revalidate","events":["content.published"]}`
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/webhooks", body), &created)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotEmpty(t, created.Secret)
+		assert.Equal(t, []string{"content.published"}, created.Events)
+
+		var list []dtos.WebhookResponse
+		doRequest(t, mux, adminRequest(http.MethodGet, "/admin/webhooks", ""), &list)
+		if assert.Len(t, list, 1) {
+			assert.Empty(t, list[0].Secret)
+		}
+	})
+
+	t.Run("Deve validar URL do webhook", func(t *testing.T) {
+		rec := doRequest(t, mux, adminRequest(http.MethodPost, "/admin/webhooks", `{"url":"nao-e-url"}`), nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	})
+
+	t.Run("Deve remover webhook", func(t *testing.T) {
+		rec := doRequest(t, mux, adminRequest(http.MethodDelete, "/admin/webhooks/1", ""), nil)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = doRequest(t, mux, adminRequest(http.MethodDelete, "/admin/webhooks/1", ""), nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
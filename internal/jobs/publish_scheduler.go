@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const publishSchedulerJob = "publish-scheduler"
+
+// Detecta posts/projetos cujo PostedAt (ou UnpublishAt) acabou de passar e emite
+// content.published / content.unpublished. O intervalo já processado fica salvo em
+// JobCheckpoint, então nada se perde (nem se repete) quando o servidor reinicia.
+// Conteúdo em scheduled passa para published junto com o evento.
+type PublishScheduler struct {
+	db  *gorm.DB
+	bus *events.Bus
+	now func() time.Time
+}
+
+func NewPublishScheduler(db *gorm.DB, bus *events.Bus) *PublishScheduler {
+	return &PublishScheduler{db: db, bus: bus, now: time.Now}
+}
+
+// Processa o intervalo (último checkpoint, agora]; retorna quantos eventos foram emitidos.
+// Os estados, as entregas do outbox e o checkpoint são gravados na mesma transação:
+// se algo falhar, nada fica gravado e a próxima execução refaz o intervalo inteiro.
+// O bus só recebe os eventos depois do commit e serve a assinantes que não precisam
+// de garantia de entrega (logs, métricas).
+func (s *PublishScheduler) RunOnce(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	db := s.db.WithContext(ctx)
+
+	var checkpoint models.JobCheckpoint
+	err := db.First(&checkpoint, "name = ?", publishSchedulerJob).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Primeira execução: começamos de agora para não reemitir publicações antigas
+		return 0, saveCheckpoint(db, now)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var collected []events.Event
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if collected, err = s.collect(tx, checkpoint.LastRunAt, now); err != nil {
+			return err
+		}
+		outbox := repositories.NewWebhookRepository(tx)
+		for _, e := range collected {
+			if err := outbox.Enqueue(e); err != nil {
+				return err
+			}
+		}
+		return saveCheckpoint(tx, now)
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range collected {
+		s.bus.Publish(ctx, e)
+	}
+	return len(collected), nil
+}
+
+func (s *PublishScheduler) Run(ctx context.Context, interval time.Duration) {
+	Every(ctx, publishSchedulerJob, interval, func(ctx context.Context) error {
+		_, err := s.RunOnce(ctx)
+		return err
+	})
+}
+
+func (s *PublishScheduler) collect(tx *gorm.DB, from, to time.Time) ([]events.Event, error) {
+	posts := repositories.NewPostRepository(tx)
+	projects := repositories.NewProjectRepository(tx)
+	var out []events.Event
+
+	published, err := posts.FindPublishedBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range published {
+		if err := markPublished(tx, "post", p.ID, p.Status, posts.SetStatus); err != nil {
+			return nil, err
+		}
+		if err := posts.MarkPublishNotified(p.ID, to); err != nil {
+			return nil, err
+		}
+		out = append(out, events.Event{Type: events.ContentPublished, ContentType: "post", ContentID: p.ID, Slug: p.Slug, OccurredAt: *p.PostedAt})
+	}
+	unpublished, err := posts.FindUnpublishedBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range unpublished {
+		if err := posts.MarkUnpublishNotified(p.ID, to); err != nil {
+			return nil, err
+		}
+		out = append(out, events.Event{Type: events.ContentUnpublished, ContentType: "post", ContentID: p.ID, Slug: p.Slug, OccurredAt: *p.UnpublishAt})
+	}
+
+	publishedProjects, err := projects.FindPublishedBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range publishedProjects {
+		if err := markPublished(tx, "project", p.ID, p.Status, projects.SetStatus); err != nil {
+			return nil, err
+		}
+		if err := projects.MarkPublishNotified(p.ID, to); err != nil {
+			return nil, err
+		}
+		out = append(out, events.Event{Type: events.ContentPublished, ContentType: "project", ContentID: p.ID, Slug: p.Slug, OccurredAt: *p.PostedAt})
+	}
+	unpublishedProjects, err := projects.FindUnpublishedBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range unpublishedProjects {
+		if err := projects.MarkUnpublishNotified(p.ID, to); err != nil {
+			return nil, err
+		}
+		out = append(out, events.Event{Type: events.ContentUnpublished, ContentType: "project", ContentID: p.ID, Slug: p.Slug, OccurredAt: *p.UnpublishAt})
+	}
+
+	return out, nil
+}
+
+func markPublished(tx *gorm.DB, contentType string, id uint, status string, setStatus func(uint, string) error) error {
+	if status != models.StatusScheduled {
+		return nil
+	}
+	if err := setStatus(id, models.StatusPublished); err != nil {
+		return err
+	}
+	return repositories.NewStateChangeRepository(tx).Create(&models.StateChange{
+		ContentType: contentType, ContentID: id, From: status, To: models.StatusPublished, Actor: models.SystemActor,
+	})
+}
+
+func saveCheckpoint(db *gorm.DB, at time.Time) error {
+	return db.Save(&models.JobCheckpoint{Name: publishSchedulerJob, LastRunAt: at}).Error
+}
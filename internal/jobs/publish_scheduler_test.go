@@ -0,0 +1,234 @@
+package jobs_test
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/jobs"
+	"cms-headless/internal/models"
+	"cms-headless/internal/services"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestPublishScheduler(t *testing.T) {
+	db := SetupTestDB()
+	bus := events.NewBus()
+	var received []events.Event
+	bus.Subscribe(func(_ context.Context, e events.Event) error {
+		received = append(received, e)
+		return nil
+	})
+	scheduler := jobs.NewPublishScheduler(db, bus)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	t.Run("Primeira execução apenas grava o checkpoint", func(t *testing.T) {
+		past := now.Add(-24 * time.Hour)
+		db.Create(&models.Post{Title: "Antigo", Slug: "antigo", PostedAt: &past})
+
+		emitted, err := scheduler.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, emitted)
+		assert.Empty(t, received)
+	})
+
+	t.Run("Deve emitir publicação e retirada ocorridas desde o checkpoint", func(t *testing.T) {
+		// Recua o checkpoint para simular uma hora sem execuções (ex.: servidor parado)
+		db.Model(&models.JobCheckpoint{}).Where("name = ?", "publish-scheduler").Update("last_run_at", now.Add(-time.Hour))
+
+		justPosted := now.Add(-10 * time.Minute)
+		scheduled := now.Add(time.Hour)
+		takenDown := now.Add(-5 * time.Minute)
+		older := now.Add(-48 * time.Hour)
+		novo := models.Post{Title: "Novo", Slug: "novo", PostedAt: &justPosted, Status: models.StatusScheduled}
+		retirado := models.Project{Title: "Retirado", Slug: "retirado", PostedAt: &older, UnpublishAt: &takenDown}
+		db.Create(&novo)
+		db.Create(&models.Post{Title: "Futuro", Slug: "futuro", PostedAt: &scheduled})
+		db.Create(&retirado)
+		// "Imediato" foi publicado na hora e o serviço já emitiu o evento
+		db.Create(&models.Post{Title: "Imediato", Slug: "imediato", PostedAt: &justPosted, PublishedNotifiedAt: &justPosted})
+		// Editado depois de vencer e antes da execução: o evento não pode se perder
+		db.Model(&novo).Update("short_description", "revisado")
+
+		emitted, err := scheduler.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, emitted)
+		if assert.Len(t, received, 2) {
+			assert.Equal(t, events.ContentPublished, received[0].Type)
+			assert.Equal(t, "novo", received[0].Slug)
+			assert.Equal(t, events.ContentUnpublished, received[1].Type)
+			assert.Equal(t, "project", received[1].ContentType)
+		}
+
+		var post models.Post
+		db.First(&post, novo.ID)
+		assert.Equal(t, models.StatusPublished, post.Status)
+
+		var change models.StateChange
+		assert.NoError(t, db.Where("content_id = ?", novo.ID).First(&change).Error)
+		assert.Equal(t, models.StatusScheduled, change.From)
+		assert.Equal(t, models.SystemActor, change.Actor)
+	})
+
+	t.Run("Não deve reemitir eventos já processados", func(t *testing.T) {
+		received = nil
+		emitted, err := scheduler.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, emitted)
+		assert.Empty(t, received)
+	})
+
+	t.Run("Não deve reemitir publicação imediata feita pelo serviço", func(t *testing.T) {
+		db.Model(&models.JobCheckpoint{}).Where("name = ?", "publish-scheduler").Update("last_run_at", now.Add(-time.Hour))
+		posts := services.NewPostService(db)
+		post, _ := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "Na Hora", Body: "corpo"})
+		posts.Transition(ctx, post.ID, models.StatusInReview, "")
+		posts.Transition(ctx, post.ID, models.StatusApproved, "")
+		justPosted := time.Now().UTC().Add(-time.Second)
+		_, err := posts.SetPostedAt(ctx, post.ID, &justPosted)
+		assert.NoError(t, err)
+
+		emitted, err := scheduler.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, emitted)
+	})
+}
+
+func TestPublishScheduler_Archived(t *testing.T) {
+	db := SetupTestDB()
+	bus := events.NewBus()
+	var received []events.Event
+	bus.Subscribe(func(_ context.Context, e events.Event) error {
+		received = append(received, e)
+		return nil
+	})
+	scheduler := jobs.NewPublishScheduler(db, bus)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	t.Run("Não deve publicar nem retirar itens arquivados", func(t *testing.T) {
+		db.Create(&models.JobCheckpoint{Name: "publish-scheduler", LastRunAt: now.Add(-time.Hour)})
+
+		// Arquivados ainda agendados: o PostedAt/UnpublishAt vence depois do arquivamento
+		justPosted := now.Add(-10 * time.Minute)
+		older := now.Add(-48 * time.Hour)
+		takenDown := now.Add(-5 * time.Minute)
+		arquivado := models.Post{Title: "Arquivado", Slug: "arquivado", PostedAt: &justPosted, Status: models.StatusArchived}
+		retirado := models.Project{Title: "Retirado", Slug: "retirado", PostedAt: &older, UnpublishAt: &takenDown, Status: models.StatusArchived}
+		db.Create(&arquivado)
+		db.Create(&retirado)
+
+		emitted, err := scheduler.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, emitted)
+		assert.Empty(t, received)
+	})
+}
+
+func TestPublishScheduler_Retry(t *testing.T) {
+	db := SetupTestDB()
+	bus := events.NewBus()
+	var received []events.Event
+	bus.Subscribe(func(_ context.Context, e events.Event) error {
+		received = append(received, e)
+		return nil
+	})
+	scheduler := jobs.NewPublishScheduler(db, bus)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	db.Create(&models.JobCheckpoint{Name: "publish-scheduler", LastRunAt: now.Add(-time.Hour)})
+	justPosted := now.Add(-10 * time.Minute)
+	novo := models.Post{Title: "Novo", Slug: "novo", PostedAt: &justPosted, Status: models.StatusScheduled}
+	db.Create(&novo)
+
+	t.Run("Não deve gravar o estado se o checkpoint falhar", func(t *testing.T) {
+		failCheckpoint := func(tx *gorm.DB) {
+			if tx.Statement.Table == "job_checkpoints" {
+				tx.AddError(errors.New("banco indisponível"))
+			}
+		}
+		db.Callback().Update().Before("gorm:update").Register("test:fail_checkpoint", failCheckpoint)
+		db.Callback().Create().Before("gorm:create").Register("test:fail_checkpoint", failCheckpoint)
+
+		_, err := scheduler.RunOnce(ctx)
+
+		assert.Error(t, err)
+		assert.Empty(t, received, "eventos só saem depois do commit")
+		var post models.Post
+		db.First(&post, novo.ID)
+		assert.Equal(t, models.StatusScheduled, post.Status)
+
+		db.Callback().Update().Remove("test:fail_checkpoint")
+		db.Callback().Create().Remove("test:fail_checkpoint")
+	})
+
+	t.Run("Deve emitir a publicação na execução seguinte", func(t *testing.T) {
+		emitted, err := scheduler.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, emitted)
+		if assert.Len(t, received, 1) {
+			assert.Equal(t, "novo", received[0].Slug)
+		}
+		var post models.Post
+		db.First(&post, novo.ID)
+		assert.Equal(t, models.StatusPublished, post.Status)
+	})
+}
+
+func TestPublishScheduler_Outbox(t *testing.T) {
+	db := SetupTestDB()
+	scheduler := jobs.NewPublishScheduler(db, events.NewBus())
+	ctx := context.Background()
+	now := time.Now().UTC()
+	checkpoint := now.Add(-time.Hour)
+
+	db.Create(&models.JobCheckpoint{Name: "publish-scheduler", LastRunAt: checkpoint})
+	db.Create(&models.WebhookSubscription{URL: "http://localhost/hook", Secret: "segredo", Active: true})
+	justPosted := now.Add(-10 * time.Minute)
+	novo := models.Post{Title: "Novo", Slug: "novo", PostedAt: &justPosted, Status: models.StatusScheduled}
+	db.Create(&novo)
+
+	t.Run("Não deve avançar o checkpoint se o outbox falhar", func(t *testing.T) {
+		failOutbox := func(tx *gorm.DB) {
+			if tx.Statement.Table == "webhook_deliveries" {
+				tx.AddError(errors.New("banco indisponível"))
+			}
+		}
+		db.Callback().Create().Before("gorm:create").Register("test:fail_outbox", failOutbox)
+
+		_, err := scheduler.RunOnce(ctx)
+
+		db.Callback().Create().Remove("test:fail_outbox")
+		assert.Error(t, err)
+		var saved models.JobCheckpoint
+		db.First(&saved, "name = ?", "publish-scheduler")
+		assert.WithinDuration(t, checkpoint, saved.LastRunAt, time.Second)
+		var post models.Post
+		db.First(&post, novo.ID)
+		assert.Equal(t, models.StatusScheduled, post.Status)
+	})
+
+	t.Run("Deve gravar a entrega junto com a publicação", func(t *testing.T) {
+		emitted, err := scheduler.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, emitted)
+		var deliveries []models.WebhookDelivery
+		db.Find(&deliveries)
+		if assert.Len(t, deliveries, 1) {
+			assert.Equal(t, string(events.ContentPublished), deliveries[0].EventType)
+		}
+	})
+}
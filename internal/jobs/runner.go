@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Executa fn imediatamente e depois a cada intervalo, até o ctx ser cancelado.
+// Erros são apenas logados: uma falha pontual não deve derrubar o job.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Job %s falhou: %v", name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package jobs_test
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/search"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func SetupTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	models.Migrate(db)
+	search.Setup(db)
+	return db
+}
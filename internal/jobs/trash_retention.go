@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"cms-headless/internal/repositories"
+	"context"
+	"log"
+	"time"
+)
+
+// Expurga definitivamente posts e projetos que estão na lixeira há mais tempo que Retention
+type TrashRetention struct {
+	posts     repositories.PostRepository
+	projects  repositories.ProjectRepository
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewTrashRetention(posts repositories.PostRepository, projects repositories.ProjectRepository, retention time.Duration) *TrashRetention {
+	return &TrashRetention{posts: posts, projects: projects, retention: retention, now: time.Now}
+}
+
+// Uma passada do job; retorna quantos itens foram removidos
+func (j *TrashRetention) RunOnce(ctx context.Context) (int64, error) {
+	cutoff := j.now().UTC().Add(-j.retention)
+
+	posts, err := j.posts.WithContext(ctx).PurgeDeletedBefore(cutoff)
+	if err != nil {
+		return 0, err
+	}
+	projects, err := j.projects.WithContext(ctx).PurgeDeletedBefore(cutoff)
+	if err != nil {
+		return posts, err
+	}
+
+	if total := posts + projects; total > 0 {
+		log.Printf("Lixeira: %d posts e %d projetos expurgados (anteriores a %s)", posts, projects, cutoff.Format(time.RFC3339))
+	}
+	return posts + projects, nil
+}
+
+func (j *TrashRetention) Run(ctx context.Context, interval time.Duration) {
+	Every(ctx, "trash-retention", interval, func(ctx context.Context) error {
+		_, err := j.RunOnce(ctx)
+		return err
+	})
+}
@@ -0,0 +1,78 @@
+package jobs_test
+
+import (
+	"cms-headless/internal/jobs"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestTrashRetention(t *testing.T) {
+	db := SetupTestDB()
+	posts := repositories.NewPostRepository(db)
+	projects := repositories.NewProjectRepository(db)
+	now := time.Now().UTC()
+
+	tag := models.Tag{Title: "Go"}
+	cat := models.Category{Title: "Backend"}
+	db.Create(&tag)
+	db.Create(&cat)
+
+	old := models.Post{Title: "Antigo", Slug: "antigo", Tags: []models.Tag{tag}, Categories: []models.Category{cat}}
+	recent := models.Post{Title: "Recente", Slug: "recente", Tags: []models.Tag{tag}}
+	live := models.Post{Title: "Ativo", Slug: "ativo", Tags: []models.Tag{tag}}
+	oldProject := models.Project{Title: "Projeto Antigo", Slug: "projeto-antigo", Categories: []models.Category{cat}}
+	db.Create(&old)
+	db.Create(&recent)
+	db.Create(&live)
+	db.Create(&oldProject)
+
+	// Simula itens apagados em momentos diferentes
+	db.Model(&old).Update("deleted_at", now.Add(-40*24*time.Hour))
+	db.Model(&recent).Update("deleted_at", now.Add(-2*24*time.Hour))
+	db.Model(&oldProject).Update("deleted_at", now.Add(-31*24*time.Hour))
+
+	job := jobs.NewTrashRetention(posts, projects, 30*24*time.Hour)
+
+	t.Run("Deve expurgar apenas itens além do período de retenção", func(t *testing.T) {
+		purged, err := job.RunOnce(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		assert.Equal(t, int64(0), countUnscoped(db, &models.Post{}, old.ID))
+		assert.Equal(t, int64(1), countUnscoped(db, &models.Post{}, recent.ID), "item recente continua na lixeira")
+		assert.Equal(t, int64(1), countUnscoped(db, &models.Post{}, live.ID))
+		assert.Equal(t, int64(0), countUnscoped(db, &models.Project{}, oldProject.ID))
+	})
+
+	t.Run("Deve remover as linhas de junção dos itens expurgados", func(t *testing.T) {
+		var joins int64
+		db.Table("post_tags").Where("post_id = ?", old.ID).Count(&joins)
+		assert.Zero(t, joins)
+		db.Table("post_categories").Where("post_id = ?", old.ID).Count(&joins)
+		assert.Zero(t, joins)
+		db.Table("project_categories").Where("project_id = ?", oldProject.ID).Count(&joins)
+		assert.Zero(t, joins)
+
+		db.Table("post_tags").Where("post_id = ?", live.ID).Count(&joins)
+		assert.Equal(t, int64(1), joins, "itens ativos mantêm suas tags")
+	})
+
+	t.Run("Segunda execução não deve expurgar nada", func(t *testing.T) {
+		purged, err := job.RunOnce(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, purged)
+	})
+}
+
+func countUnscoped(db *gorm.DB, model any, id uint) int64 {
+	var count int64
+	db.Unscoped().Model(model).Where("id = ?", id).Count(&count)
+	return count
+}
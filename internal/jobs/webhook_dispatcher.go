@@ -0,0 +1,107 @@
+package jobs
+
+import (
+	"bytes"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/webhooks"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Política de retentativa: 30s, 1min, 2min... (dobrando, até 1h), desistindo após 10 tentativas
+const (
+	webhookMaxAttempts = 10
+	webhookBackoffBase = 30 * time.Second
+	webhookBackoffMax  = time.Hour
+	webhookBatchSize   = 50
+)
+
+// Envia as entregas pendentes do outbox. Como o outbox sobrevive a reinícios, a entrega é
+// "pelo menos uma vez": receptores devem ser idempotentes (o header X-Webhook-Delivery ajuda).
+type WebhookDispatcher struct {
+	webhooks repositories.WebhookRepository
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewWebhookDispatcher(webhooks repositories.WebhookRepository, client *http.Client) *WebhookDispatcher {
+	return &WebhookDispatcher{webhooks: webhooks, client: client, now: time.Now}
+}
+
+// Uma passada do job; retorna quantas entregas tiveram sucesso
+func (d *WebhookDispatcher) RunOnce(ctx context.Context) (int, error) {
+	repo := d.webhooks.WithContext(ctx)
+	due, err := repo.FindDue(d.now().UTC(), webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range due {
+		sendErr := d.send(ctx, delivery)
+		now := d.now().UTC()
+		attempts := delivery.Attempts + 1
+
+		switch {
+		case sendErr == nil:
+			err = repo.MarkDelivered(delivery.ID, now)
+			delivered++
+		case attempts >= webhookMaxAttempts:
+			err = repo.MarkFailed(delivery.ID, attempts, now, sendErr.Error())
+		default:
+			err = repo.MarkRetry(delivery.ID, attempts, now.Add(webhookBackoff(attempts)), sendErr.Error())
+		}
+		if err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	Every(ctx, "webhook-dispatcher", interval, func(ctx context.Context) error {
+		_, err := d.RunOnce(ctx)
+		return err
+	})
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery models.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	timestamp := d.now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.HeaderEvent, delivery.EventType)
+	req.Header.Set(webhooks.HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(webhooks.HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhooks.HeaderSignature, webhooks.Sign(delivery.Subscription.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("resposta inesperada: %s", res.Status)
+	}
+	return nil
+}
+
+// Espera antes da tentativa seguinte à de número attempts
+func webhookBackoff(attempts int) time.Duration {
+	wait := webhookBackoffBase
+	for i := 1; i < attempts && wait < webhookBackoffMax; i++ {
+		wait *= 2
+	}
+	return min(wait, webhookBackoffMax)
+}
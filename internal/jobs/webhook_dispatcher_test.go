@@ -0,0 +1,85 @@
+package jobs_test
+
+import (
+	"cms-headless/internal/events"
+	"cms-headless/internal/jobs"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/webhooks"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDispatcher(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewWebhookRepository(db)
+	ctx := context.Background()
+
+	status := http.StatusOK
+	var verified bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+		verified = webhooks.Verify("chave", ts, body, r.Header.Get(webhooks.HeaderSignature))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	db.Create(&models.WebhookSubscription{URL: server.URL, Secret: "chave", Active: true})
+	dispatcher := jobs.NewWebhookDispatcher(repo, server.Client())
+
+	t.Run("Deve entregar com assinatura HMAC válida", func(t *testing.T) {
+		assert.NoError(t, repo.Enqueue(events.Event{Type: events.ContentCreated, ContentType: "post", ContentID: 1, Slug: "ola"}))
+
+		delivered, err := dispatcher.RunOnce(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.True(t, verified, "assinatura deve conferir com o secret")
+
+		var d models.WebhookDelivery
+		db.First(&d)
+		assert.NotNil(t, d.DeliveredAt)
+		assert.Equal(t, 1, d.Attempts)
+	})
+
+	t.Run("Deve reagendar com backoff quando o destino falha", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		assert.NoError(t, repo.Enqueue(events.Event{Type: events.ContentUpdated, ContentType: "post", ContentID: 1}))
+
+		delivered, err := dispatcher.RunOnce(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+
+		var d models.WebhookDelivery
+		db.Last(&d)
+		assert.Nil(t, d.DeliveredAt)
+		assert.Equal(t, 1, d.Attempts)
+		assert.True(t, d.NextAttemptAt.After(time.Now().Add(20*time.Second)), "próxima tentativa no futuro")
+		assert.Contains(t, d.LastError, "500")
+
+		// Ainda não venceu: nada a fazer
+		delivered, _ = dispatcher.RunOnce(ctx)
+		assert.Equal(t, 0, delivered)
+	})
+
+	t.Run("Deve desistir após o número máximo de tentativas", func(t *testing.T) {
+		var d models.WebhookDelivery
+		db.Last(&d)
+		db.Model(&d).Updates(map[string]any{"attempts": 9, "next_attempt_at": time.Now().UTC().Add(-time.Second)})
+
+		_, err := dispatcher.RunOnce(ctx)
+		assert.NoError(t, err)
+
+		db.Last(&d)
+		assert.NotNil(t, d.FailedAt)
+		assert.Equal(t, 10, d.Attempts)
+	})
+}
@@ -2,11 +2,30 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// Categorias formam uma árvore (Backend > Go > Concorrência). Raízes têm
+// ParentID nulo; irmãs são ordenadas por Position e, no empate, pelo título.
 type Category struct {
-	ID        uint   `gorm:"primaryKey;autoIncrement"`
-	Title     string `gorm:"uniqueIndex;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	Title           string `gorm:"uniqueIndex;not null"`
+	Slug            string `gorm:"uniqueIndex;not null;default:''"`
+	Description     string `gorm:"type:text"`
+	MetaTitle       string
+	MetaDescription string
+	ParentID        *uint `gorm:"index:idx_categories_parent,priority:1"`
+	Position        int   `gorm:"not null;default:0;index:idx_categories_parent,priority:2"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.Slug != "" {
+		return nil
+	}
+	slug, err := uniqueTaxonomySlug(tx, "categories", c.Title, 0)
+	c.Slug = slug
+	return err
 }
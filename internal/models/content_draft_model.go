@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Edições ainda não publicadas de um post/projeto que já está no ar.
+// A linha em posts/projects continua sendo a versão servida pela API pública.
+type ContentDraft struct {
+	ID               uint   `gorm:"primaryKey;autoIncrement"`
+	ContentType      string `gorm:"not null;uniqueIndex:idx_content_drafts_content"` // "post" ou "project"
+	ContentID        uint   `gorm:"not null;uniqueIndex:idx_content_drafts_content"`
+	Title            string `gorm:"not null"`
+	Slug             string `gorm:"not null"`
+	ShortDescription string
+	Body             string `gorm:"type:text"`
+	DemoURL          string
+	RepoURL          string
+	TagIDs           []uint `gorm:"serializer:json"`
+	CategoryIDs      []uint `gorm:"serializer:json"`
+	Author           string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+// Último instante processado por um job periódico, para não perder eventos entre reinícios
+type JobCheckpoint struct {
+	Name      string `gorm:"primaryKey"`
+	LastRunAt time.Time
+	UpdatedAt time.Time
+}
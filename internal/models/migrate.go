@@ -0,0 +1,112 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Índices únicos antigos (sem filtro de soft delete) substituídos pelos parciais *_active
+var legacyIndexes = []struct {
+	model any
+	name  string
+}{
+	{&Post{}, "idx_posts_title"},
+	{&Post{}, "idx_posts_slug"},
+	{&Project{}, "idx_projects_title"},
+	{&Project{}, "idx_projects_slug"},
+}
+
+// Cria/atualiza o schema de todas as entidades do CMS
+func Migrate(db *gorm.DB) error {
+	// Bancos anteriores ao fluxo editorial: a coluna status nasce como "draft" e precisa ser preenchida
+	m := db.Migrator()
+	backfillPosts := m.HasTable(&Post{}) && !m.HasColumn(&Post{}, "Status")
+	backfillProjects := m.HasTable(&Project{}) && !m.HasColumn(&Project{}, "Status")
+	// Antes das colunas *_notified_at o scheduler considerava anunciado o que fora editado depois de vencer
+	notifiedPosts := m.HasTable(&Post{}) && !m.HasColumn(&Post{}, "PublishedNotifiedAt")
+	notifiedProjects := m.HasTable(&Project{}) && !m.HasColumn(&Project{}, "PublishedNotifiedAt")
+
+	if err := backfillTaxonomySlugs(db, &Tag{}, "tags"); err != nil {
+		return err
+	}
+	if err := backfillTaxonomySlugs(db, &Category{}, "categories"); err != nil {
+		return err
+	}
+	if err := backfillTagNormalization(db); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(
+		&Category{},
+		&Tag{},
+		&TagAlias{},
+		&Post{},
+		&Project{},
+		&SlugHistory{},
+		&JobCheckpoint{},
+		&WebhookSubscription{},
+		&WebhookDelivery{},
+		&Revision{},
+		&ContentDraft{},
+		&StateChange{},
+		&SearchDocument{},
+		&SearchTerm{},
+	); err != nil {
+		return err
+	}
+	if backfillPosts {
+		if err := backfillStatus(db, "posts"); err != nil {
+			return err
+		}
+	}
+	if backfillProjects {
+		if err := backfillStatus(db, "projects"); err != nil {
+			return err
+		}
+	}
+	if notifiedPosts {
+		if err := backfillNotified(db, "posts"); err != nil {
+			return err
+		}
+	}
+	if notifiedProjects {
+		if err := backfillNotified(db, "projects"); err != nil {
+			return err
+		}
+	}
+	return dropLegacyIndexes(db)
+}
+
+// Marca como já anunciadas as datas que o critério antigo (updated_at posterior à
+// data) tirava do scheduler, para a troca de critério não reemitir eventos
+func backfillNotified(db *gorm.DB, table string) error {
+	if err := db.Table(table).Where("posted_at IS NOT NULL AND updated_at >= posted_at").
+		UpdateColumn("published_notified_at", gorm.Expr("posted_at")).Error; err != nil {
+		return err
+	}
+	return db.Table(table).Where("unpublish_at IS NOT NULL AND updated_at >= unpublish_at").
+		UpdateColumn("unpublished_notified_at", gorm.Expr("unpublish_at")).Error
+}
+
+// Deriva o estado de itens antigos a partir do PostedAt
+func backfillStatus(db *gorm.DB, table string) error {
+	now := time.Now().UTC()
+	if err := db.Table(table).Where("posted_at IS NOT NULL AND posted_at <= ?", now).
+		Update("status", StatusPublished).Error; err != nil {
+		return err
+	}
+	return db.Table(table).Where("posted_at > ?", now).Update("status", StatusScheduled).Error
+}
+
+func dropLegacyIndexes(db *gorm.DB) error {
+	m := db.Migrator()
+	for _, idx := range legacyIndexes {
+		if m.HasIndex(idx.model, idx.name) {
+			if err := m.DropIndex(idx.model, idx.name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package models_test
+
+import (
+	"cms-headless/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrate(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+
+	t.Run("Deve substituir índices únicos antigos pelos parciais", func(t *testing.T) {
+		// Schema anterior: índice único sem filtro de deleted_at
+		db.Exec("CREATE TABLE posts (id integer PRIMARY KEY AUTOINCREMENT, title text NOT NULL, slug text NOT NULL)")
+		db.Exec("CREATE UNIQUE INDEX idx_posts_slug ON posts(slug)")
+		db.Exec("CREATE UNIQUE INDEX idx_posts_title ON posts(title)")
+
+		err := models.Migrate(db)
+		assert.NoError(t, err)
+
+		m := db.Migrator()
+		assert.False(t, m.HasIndex(&models.Post{}, "idx_posts_slug"))
+		assert.False(t, m.HasIndex(&models.Post{}, "idx_posts_title"))
+		assert.True(t, m.HasIndex(&models.Post{}, "idx_posts_slug_active"))
+		assert.True(t, m.HasIndex(&models.Project{}, "idx_projects_title_active"))
+	})
+
+	t.Run("Deve ser idempotente", func(t *testing.T) {
+		assert.NoError(t, models.Migrate(db))
+	})
+}
+
+func TestMigrate_StatusBackfill(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	db.Exec("CREATE TABLE posts (id integer PRIMARY KEY AUTOINCREMENT, title text NOT NULL, slug text NOT NULL, posted_at datetime)")
+	db.Exec("INSERT INTO posts (title, slug, posted_at) VALUES ('a', 'a', ?), ('b', 'b', ?), ('c', 'c', NULL)",
+		time.Now().UTC().Add(-time.Hour), time.Now().UTC().Add(time.Hour))
+
+	t.Run("Deve derivar o estado de itens antigos pelo PostedAt", func(t *testing.T) {
+		assert.NoError(t, models.Migrate(db))
+
+		var statuses []string
+		db.Table("posts").Order("id").Pluck("status", &statuses)
+		assert.Equal(t, []string{models.StatusPublished, models.StatusScheduled, models.StatusDraft}, statuses)
+	})
+}
+
+func TestMigrate_NotifiedBackfill(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	now := time.Now().UTC()
+	db.Exec("CREATE TABLE posts (id integer PRIMARY KEY AUTOINCREMENT, title text NOT NULL, slug text NOT NULL, status text, updated_at datetime, posted_at datetime, unpublish_at datetime)")
+	// "a" foi publicado na hora (editado depois de vencer); "b" ainda aguarda o scheduler
+	db.Exec("INSERT INTO posts (title, slug, status, updated_at, posted_at) VALUES ('a', 'a', 'published', ?, ?), ('b', 'b', 'scheduled', ?, ?)",
+		now, now.Add(-time.Hour), now, now.Add(time.Hour))
+
+	t.Run("Deve marcar como anunciadas as datas que o critério antigo já ignorava", func(t *testing.T) {
+		assert.NoError(t, models.Migrate(db))
+
+		var posts []models.Post
+		db.Order("id").Find(&posts)
+		if assert.Len(t, posts, 2) {
+			assert.NotNil(t, posts[0].PublishedNotifiedAt)
+			assert.Nil(t, posts[1].PublishedNotifiedAt)
+			assert.Nil(t, posts[0].UnpublishedNotifiedAt)
+		}
+	})
+}
+
+func TestMigrate_TaxonomySlugBackfill(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	// Schema anterior: tags só com título
+	db.Exec("CREATE TABLE tags (id integer PRIMARY KEY AUTOINCREMENT, title text NOT NULL, created_at datetime, updated_at datetime)")
+	db.Exec("CREATE UNIQUE INDEX idx_tags_title ON tags(title)")
+	db.Exec("INSERT INTO tags (title) VALUES ('Go'), ('GO!'), ('Banco de Dados')")
+
+	t.Run("Deve gerar slugs únicos para tags existentes", func(t *testing.T) {
+		assert.NoError(t, models.Migrate(db))
+
+		var slugs []string
+		db.Table("tags").Order("id").Pluck("slug", &slugs)
+		assert.Equal(t, []string{"go", "go-2", "banco-de-dados"}, slugs)
+		assert.True(t, db.Migrator().HasIndex(&models.Tag{}, "idx_tags_slug"))
+	})
+
+	t.Run("Deve ser idempotente", func(t *testing.T) {
+		assert.NoError(t, models.Migrate(db))
+	})
+}
+
+func TestMigrate_TagNormalizationBackfill(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	// Schema anterior: tags com slug, mas sem título normalizado
+	db.Exec("CREATE TABLE tags (id integer PRIMARY KEY AUTOINCREMENT, title text NOT NULL, slug text NOT NULL, created_at datetime, updated_at datetime)")
+	db.Exec("CREATE TABLE post_tags (post_id integer, tag_id integer, PRIMARY KEY (post_id, tag_id))")
+	db.Exec("INSERT INTO tags (title, slug) VALUES ('Go', 'go'), ('Rust', 'rust'), ('go', 'go-2')")
+	db.Exec("INSERT INTO post_tags (post_id, tag_id) VALUES (1, 1), (1, 3), (2, 3)")
+
+	t.Run("Deve fundir na mais antiga as tags que só diferiam na caixa", func(t *testing.T) {
+		assert.NoError(t, models.Migrate(db))
+
+		var titles []string
+		db.Table("tags").Order("id").Pluck("normalized_title", &titles)
+		assert.Equal(t, []string{"go", "rust"}, titles)
+
+		var tagIDs []uint
+		db.Table("post_tags").Order("post_id").Pluck("tag_id", &tagIDs)
+		assert.Equal(t, []uint{1, 1}, tagIDs)
+
+		var history models.SlugHistory
+		db.Where("content_type = ? AND slug = ?", "tag", "go-2").First(&history)
+		assert.Equal(t, uint(1), history.ContentID)
+	})
+
+	t.Run("Deve ser idempotente", func(t *testing.T) {
+		assert.NoError(t, models.Migrate(db))
+	})
+}
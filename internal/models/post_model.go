@@ -7,15 +7,21 @@ import (
 )
 
 type Post struct {
-	ID               uint   `gorm:"primaryKey;autoIncrement"`
-	Title            string `gorm:"uniqueIndex;not null"`
-	Slug             string `gorm:"uniqueIndex;not null"`
-	ShortDescription string
-	Body             string `gorm:"type:text"`
-	CreatedAt        time.Time
-	UpdatedAt        time.Time
-	PostedAt         *time.Time     `gorm:"index"`
-	DeletedAt        gorm.DeletedAt `gorm:"index"`
+	ID                    uint   `gorm:"primaryKey;autoIncrement"`
+	Title                 string `gorm:"not null;uniqueIndex:idx_posts_title_active,where:deleted_at IS NULL"` // Únicos só entre itens fora da lixeira
+	Slug                  string `gorm:"not null;uniqueIndex:idx_posts_slug_active,where:deleted_at IS NULL"`
+	ShortDescription      string
+	Body                  string `gorm:"type:text"`
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+	PostedAt              *time.Time     `gorm:"index"`
+	UnpublishAt           *time.Time     `gorm:"index"` // Retirada automática do ar
+	PublishedNotifiedAt   *time.Time     // Quando o content.published do PostedAt atual saiu
+	UnpublishedNotifiedAt *time.Time     // Idem para o content.unpublished do UnpublishAt
+	Status                string         `gorm:"not null;default:draft;index"` // Estado no fluxo editorial
+	DeletedAt             gorm.DeletedAt `gorm:"index"`
+
+	HasDraft bool `gorm:"-"` // Preenchido pelos serviços quando há edições não publicadas
 
 	// Relacionamentos
 	Tags       []Tag      `gorm:"many2many:post_tags;"`
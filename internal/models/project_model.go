@@ -7,17 +7,23 @@ import (
 )
 
 type Project struct {
-	ID               uint   `gorm:"primaryKey;autoIncrement"`
-	Title            string `gorm:"uniqueIndex;not null"`
-	Slug             string `gorm:"uniqueIndex;not null"`
-	ShortDescription string
-	Body             string `gorm:"type:text"`
-	DemoURL          string
-	RepoURL          string
-	CreatedAt        time.Time      // Padronizado para CreatedAt
-	UpdatedAt        time.Time      // Padronizado para UpdatedAt
-	PostedAt         *time.Time     `gorm:"index"`
-	DeletedAt        gorm.DeletedAt `gorm:"index"` // Alterado para Soft Delete do GORM
+	ID                    uint   `gorm:"primaryKey;autoIncrement"`
+	Title                 string `gorm:"not null;uniqueIndex:idx_projects_title_active,where:deleted_at IS NULL"` // Únicos só entre itens fora da lixeira
+	Slug                  string `gorm:"not null;uniqueIndex:idx_projects_slug_active,where:deleted_at IS NULL"`
+	ShortDescription      string
+	Body                  string `gorm:"type:text"`
+	DemoURL               string
+	RepoURL               string
+	CreatedAt             time.Time      // Padronizado para CreatedAt
+	UpdatedAt             time.Time      // Padronizado para UpdatedAt
+	PostedAt              *time.Time     `gorm:"index"`
+	UnpublishAt           *time.Time     `gorm:"index"` // Retirada automática do ar
+	PublishedNotifiedAt   *time.Time     // Quando o content.published do PostedAt atual saiu
+	UnpublishedNotifiedAt *time.Time     // Idem para o content.unpublished do UnpublishAt
+	Status                string         `gorm:"not null;default:draft;index"` // Estado no fluxo editorial
+	DeletedAt             gorm.DeletedAt `gorm:"index"`                        // Alterado para Soft Delete do GORM
+
+	HasDraft bool `gorm:"-"` // Preenchido pelos serviços quando há edições não publicadas
 
 	// Relacionamentos
 	Tags       []Tag      `gorm:"many2many:project_tags;"`
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// Snapshot imutável de um post/projeto, gravado a cada alteração salva
+type Revision struct {
+	ID               uint   `gorm:"primaryKey;autoIncrement"`
+	ContentType      string `gorm:"not null;uniqueIndex:idx_revisions_content_number"` // "post" ou "project"
+	ContentID        uint   `gorm:"not null;uniqueIndex:idx_revisions_content_number"`
+	Number           int    `gorm:"not null;uniqueIndex:idx_revisions_content_number"` // Sequencial por conteúdo, a partir de 1
+	Title            string `gorm:"not null"`
+	Slug             string `gorm:"not null"`
+	ShortDescription string
+	Body             string `gorm:"type:text"`
+	DemoURL          string
+	RepoURL          string
+	TagIDs           []uint `gorm:"serializer:json"`
+	CategoryIDs      []uint `gorm:"serializer:json"`
+	Author           string // Nome do token administrativo que fez a alteração
+	CreatedAt        time.Time
+}
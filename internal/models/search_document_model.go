@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// Texto indexado de um post/projeto. A estrutura de busca específica do banco
+// (tsvector no Postgres, FTS5 no SQLite) é derivada desta tabela; ver internal/search.
+type SearchDocument struct {
+	ContentType      string `gorm:"primaryKey;size:20"` // "post" ou "project"
+	ContentID        uint   `gorm:"primaryKey;autoIncrement:false"`
+	Title            string
+	ShortDescription string
+	Body             string `gorm:"type:text"` // Sem HTML
+	Tags             string // Títulos das tags separados por espaço
+	UpdatedAt        time.Time
+}
+
+// Termos distintos (sem acentos, sem stopwords) de cada documento de busca. Dão a
+// frequência exata de um termo no acervo numa consulta agrupada, sem o casamento
+// por prefixo da busca textual.
+type SearchTerm struct {
+	ContentType string `gorm:"primaryKey;size:20"`
+	ContentID   uint   `gorm:"primaryKey;autoIncrement:false"`
+	Term        string `gorm:"primaryKey;index"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Slug antigo de um post/projeto/tag/categoria, usado para redirecionar links após renomeações
+type SlugHistory struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	ContentType string `gorm:"not null;uniqueIndex:idx_slug_histories_type_slug"` // "post", "project", "tag" ou "category"
+	Slug        string `gorm:"not null;uniqueIndex:idx_slug_histories_type_slug"`
+	ContentID   uint   `gorm:"not null;index"`
+	CreatedAt   time.Time
+}
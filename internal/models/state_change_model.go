@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Estados do fluxo editorial: draft → in_review → approved → scheduled/published → archived
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusApproved  = "approved"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// Autor das mudanças feitas sem token: jobs (ex.: o agendador) e chamadas internas
+const SystemActor = "sistema"
+
+// Registro de cada mudança de estado de um post/projeto
+type StateChange struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	ContentType string `gorm:"not null;index:idx_state_changes_content"` // "post" ou "project"
+	ContentID   uint   `gorm:"not null;index:idx_state_changes_content"`
+	From        string `gorm:"not null"`
+	To          string `gorm:"not null"`
+	Actor       string `gorm:"not null"` // Nome do token, ou SystemActor
+	Note        string
+	CreatedAt   time.Time
+}
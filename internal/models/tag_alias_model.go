@@ -0,0 +1,25 @@
+package models
+
+import (
+	"cms-headless/internal/validators"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Nome alternativo de uma tag ("golang" -> "Go"). Atribuir conteúdo pelo nome
+// do alias usa a tag canônica; aliases e títulos de tags não podem colidir.
+type TagAlias struct {
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	TagID           uint   `gorm:"not null;index"`
+	Alias           string `gorm:"not null"`
+	NormalizedAlias string `gorm:"uniqueIndex;not null"`
+	CreatedAt       time.Time
+}
+
+func (a *TagAlias) BeforeSave(tx *gorm.DB) error {
+	a.Alias = strings.TrimSpace(a.Alias)
+	a.NormalizedAlias = validators.NormalizeTitle(a.Alias)
+	return nil
+}
@@ -1,12 +1,37 @@
 package models
 
 import (
+	"cms-headless/internal/validators"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Tag struct {
-	ID        uint   `gorm:"primaryKey;autoIncrement"`
-	Title     string `gorm:"uniqueIndex;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              uint   `gorm:"primaryKey;autoIncrement"`
+	Title           string `gorm:"uniqueIndex;not null"`
+	NormalizedTitle string `gorm:"uniqueIndex;not null;default:''"` // "Go", " go " e "GÔ" colidem aqui
+	Slug            string `gorm:"uniqueIndex;not null;default:''"` // Gerado do título quando vazio
+	Description     string `gorm:"type:text"`
+	MetaTitle       string // SEO: <title> da página da tag (vazio usa Title)
+	MetaDescription string // SEO: meta description (vazio usa Description)
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Vale para Create e Save: o título normalizado nunca fica defasado
+func (t *Tag) BeforeSave(tx *gorm.DB) error {
+	t.Title = strings.TrimSpace(t.Title)
+	t.NormalizedTitle = validators.NormalizeTitle(t.Title)
+	return nil
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.Slug != "" {
+		return nil
+	}
+	slug, err := uniqueTaxonomySlug(tx, "tags", t.Title, 0)
+	t.Slug = slug
+	return err
 }
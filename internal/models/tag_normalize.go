@@ -0,0 +1,100 @@
+package models
+
+import (
+	"cms-headless/internal/validators"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Bancos anteriores à normalização: preenche normalized_title e funde na mais
+// antiga as tags que só diferiam em caixa, acentos ou espaços ("Go" e "go"). O
+// conteúdo e os rascunhos passam para a canônica e o slug da fundida redireciona
+// para ela. Roda antes do AutoMigrate, que cria o índice único.
+func backfillTagNormalization(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&Tag{}) || db.Migrator().HasColumn(&Tag{}, "NormalizedTitle") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&Tag{}, "NormalizedTitle"); err != nil {
+			return err
+		}
+
+		var rows []struct {
+			ID    uint
+			Title string
+			Slug  string
+		}
+		if err := tx.Table("tags").Select("id", "title", "slug").Order("id").Scan(&rows).Error; err != nil {
+			return err
+		}
+		canonical := make(map[string]uint, len(rows))
+		for _, row := range rows {
+			key := validators.NormalizeTitle(row.Title)
+			if target, ok := canonical[key]; ok {
+				if err := mergeLegacyTag(tx, row.ID, row.Slug, target); err != nil {
+					return err
+				}
+				continue
+			}
+			canonical[key] = row.ID
+			if err := tx.Table("tags").Where("id = ?", row.ID).Update("normalized_title", key).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Versão em SQL da fusão do repositório, só com as tabelas que já existirem
+func mergeLegacyTag(tx *gorm.DB, id uint, slug string, target uint) error {
+	m := tx.Migrator()
+	for table, fk := range map[string]string{"post_tags": "post_id", "project_tags": "project_id"} {
+		if !m.HasTable(table) {
+			continue
+		}
+		err := tx.Exec(
+			"INSERT INTO "+table+" ("+fk+", tag_id) SELECT "+fk+", ? FROM "+table+
+				" WHERE tag_id = ? AND "+fk+" NOT IN (SELECT "+fk+" FROM "+table+" WHERE tag_id = ?)",
+			target, id, target,
+		).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM "+table+" WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+	}
+
+	if m.HasTable(&ContentDraft{}) {
+		var drafts []ContentDraft
+		if err := tx.Select("id", "tag_ids").Find(&drafts).Error; err != nil {
+			return err
+		}
+		for i := range drafts {
+			if !slices.Contains(drafts[i].TagIDs, id) {
+				continue
+			}
+			ids := slices.DeleteFunc(drafts[i].TagIDs, func(t uint) bool { return t == id || t == target })
+			drafts[i].TagIDs = append(ids, target)
+			if err := tx.Model(&drafts[i]).Select("TagIDs").Updates(&drafts[i]).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	// O histórico pode ainda não existir; sem ele o slug da fundida daria 404
+	if err := m.AutoMigrate(&SlugHistory{}); err != nil {
+		return err
+	}
+	if err := tx.Model(&SlugHistory{}).Where("content_type = ? AND content_id = ?", "tag", id).
+		Update("content_id", target).Error; err != nil {
+		return err
+	}
+	if slug != "" {
+		if err := tx.Create(&SlugHistory{ContentType: "tag", Slug: slug, ContentID: target}).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec("DELETE FROM tags WHERE id = ?", id).Error
+}
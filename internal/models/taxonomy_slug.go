@@ -0,0 +1,48 @@
+package models
+
+import (
+	"cms-headless/internal/validators"
+
+	"gorm.io/gorm"
+)
+
+// Slug único gerado do título, no mesmo formato dos posts ("-2", "-3"... em colisões)
+func uniqueTaxonomySlug(tx *gorm.DB, table, title string, excludeID uint) (string, error) {
+	// Sessão nova para não herdar o Model/Dest do Create em andamento (mantém a transação)
+	db := tx.Session(&gorm.Session{NewDB: true})
+	return validators.UniqueSlug(validators.GenerateSlug(title), func(slug string) (bool, error) {
+		var count int64
+		err := db.Table(table).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+		return count > 0, err
+	})
+}
+
+// Bancos anteriores aos slugs de tags/categorias: a coluna nasce vazia e o índice
+// único só pode ser criado depois de preenchê-la
+func backfillTaxonomySlugs(db *gorm.DB, model any, table string) error {
+	m := db.Migrator()
+	if !m.HasTable(model) || m.HasColumn(model, "Slug") {
+		return nil
+	}
+	if err := m.AddColumn(model, "Slug"); err != nil {
+		return err
+	}
+
+	var rows []struct {
+		ID    uint
+		Title string
+	}
+	if err := db.Table(table).Select("id", "title").Order("id").Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		slug, err := uniqueTaxonomySlug(db, table, row.Title, row.ID)
+		if err != nil {
+			return err
+		}
+		if err := db.Table(table).Where("id = ?", row.ID).Update("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
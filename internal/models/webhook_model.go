@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Destino de webhooks (ex.: rota de revalidação ISR do Next.js)
+type WebhookSubscription struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	URL       string `gorm:"not null"`
+	Secret    string `gorm:"not null"` // Chave do HMAC-SHA256 das entregas
+	Events    string // Tipos de evento separados por vírgula; vazio assina todos
+	Active    bool   `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Entrega pendente (outbox): gravada na mesma transação da mudança e enviada pelo dispatcher
+type WebhookDelivery struct {
+	ID             uint `gorm:"primaryKey;autoIncrement"`
+	SubscriptionID uint `gorm:"not null;index"`
+	Subscription   WebhookSubscription
+	EventType      string    `gorm:"not null"`
+	Payload        string    `gorm:"type:text;not null"`
+	Attempts       int       `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time `gorm:"not null;index"`
+	DeliveredAt    *time.Time
+	FailedAt       *time.Time // Desistimos após o número máximo de tentativas
+	LastError      string
+	CreatedAt      time.Time
+}
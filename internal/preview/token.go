@@ -0,0 +1,62 @@
+package preview
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("token de preview inválido")
+	ErrExpiredToken = errors.New("token de preview expirado")
+)
+
+// Escopo do token: ContentType vazio libera qualquer rascunho; caso contrário
+// apenas o conteúdo ContentType/ContentID
+type Claims struct {
+	ContentType string `json:"type,omitempty"`
+	ContentID   uint   `json:"id,omitempty"`
+	ExpiresAt   int64  `json:"exp"`
+}
+
+func (c Claims) Allows(contentType string, id uint) bool {
+	return c.ContentType == "" || (c.ContentType == contentType && c.ContentID == id)
+}
+
+// Formato "<payload>.<assinatura>", ambos em base64url sem padding; a assinatura
+// é o HMAC-SHA256 do payload JSON
+func Issue(secret []byte, c Claims) string {
+	payload, _ := json.Marshal(c)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(secret, encoded)
+}
+
+// Valida assinatura (em tempo constante) e expiração
+func Parse(secret []byte, token string, now time.Time) (Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sign(secret, encoded)), []byte(signature)) {
+		return Claims{}, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	if now.Unix() >= c.ExpiresAt {
+		return Claims{}, ErrExpiredToken
+	}
+	return c, nil
+}
+
+func sign(secret []byte, encoded string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,52 @@
+package preview_test
+
+import (
+	"cms-headless/internal/preview"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken(t *testing.T) {
+	secret := []byte("segredo")
+	now := time.Now()
+
+	t.Run("Deve emitir e validar token de um conteúdo", func(t *testing.T) {
+		token := preview.Issue(secret, preview.Claims{ContentType: "post", ContentID: 7, ExpiresAt: now.Add(time.Minute).Unix()})
+
+		claims, err := preview.Parse(secret, token, now)
+		assert.NoError(t, err)
+		assert.True(t, claims.Allows("post", 7))
+		assert.False(t, claims.Allows("post", 8))
+		assert.False(t, claims.Allows("project", 7))
+	})
+
+	t.Run("Token sem tipo libera todos os rascunhos", func(t *testing.T) {
+		token := preview.Issue(secret, preview.Claims{ExpiresAt: now.Add(time.Minute).Unix()})
+
+		claims, err := preview.Parse(secret, token, now)
+		assert.NoError(t, err)
+		assert.True(t, claims.Allows("project", 3))
+	})
+
+	t.Run("Deve recusar token expirado, adulterado ou de outro segredo", func(t *testing.T) {
+		token := preview.Issue(secret, preview.Claims{ContentType: "post", ContentID: 7, ExpiresAt: now.Add(time.Minute).Unix()})
+
+		_, err := preview.Parse(secret, token, now.Add(2*time.Minute))
+		assert.ErrorIs(t, err, preview.ErrExpiredToken)
+
+		_, err = preview.Parse([]byte("outro"), token, now)
+		assert.ErrorIs(t, err, preview.ErrInvalidToken)
+
+		forged := preview.Issue(secret, preview.Claims{ExpiresAt: now.Add(time.Minute).Unix()})
+		_, signature, _ := strings.Cut(token, ".")
+		payload, _, _ := strings.Cut(forged, ".")
+		_, err = preview.Parse(secret, payload+"."+signature, now)
+		assert.ErrorIs(t, err, preview.ErrInvalidToken)
+
+		_, err = preview.Parse(secret, "lixo", now)
+		assert.ErrorIs(t, err, preview.ErrInvalidToken)
+	})
+}
@@ -3,14 +3,33 @@ package repositories
 import (
 	"cms-headless/internal/models"
 	"cms-headless/internal/utils"
+	"context"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type CategoryRepository interface {
+	WithContext(ctx context.Context) CategoryRepository
 	FindAll(page, pageSize int) ([]models.Category, int64, error)
+	FindPage(q utils.CursorQuery) (CursorPage[models.Category], error)
+	FindByIDs(ids []uint) ([]models.Category, error)
 	Create(category *models.Category) error
 	UpdateName(id uint, newTitle string) error
+	Update(category *models.Category) error
+	FindByID(id uint) (*models.Category, error)
+	FindByTitle(title string) (*models.Category, error)
+	FindBySlug(slug string) (*models.Category, error)
+	FindBySlugOrRedirect(slug string) (*models.Category, string, error)
+	SlugExists(slug string, excludeID uint) (bool, error)
+	Delete(id, reassignTo uint) error
+	Merge(sourceIDs []uint, targetID uint) error
+	UsageCounts(ids []uint) (map[uint]TaxonomyUsage, error)
+	Tree() ([]models.Category, error)
+	Ancestors(id uint) ([]models.Category, error)
+	SubtreeIDs(id uint) ([]uint, error)
+	Move(id uint, parentID *uint, position int) error
 }
 
 type categoryRepository struct {
@@ -21,20 +40,136 @@ func NewCategoryRepository(db *gorm.DB) CategoryRepository {
 	return &categoryRepository{db: db}
 }
 
+// Retorna uma cópia do repositório cujas consultas respeitam cancelamento e prazo do ctx
+func (r *categoryRepository) WithContext(ctx context.Context) CategoryRepository {
+	return &categoryRepository{db: r.db.WithContext(ctx)}
+}
+
 func (r *categoryRepository) FindAll(page, pageSize int) ([]models.Category, int64, error) {
 	var categories []models.Category
 	var total int64
 
-	r.db.Model(&models.Category{}).Count(&total)
+	if err := r.db.Model(&models.Category{}).Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
 	err := r.db.Scopes(utils.PaginateRepository(page, pageSize)).Order("title asc").Find(&categories).Error
 
-	return categories, total, err
+	return categories, total, mapError(err)
+}
+
+func (r *categoryRepository) FindPage(q utils.CursorQuery) (CursorPage[models.Category], error) {
+	return findPage(r.db.Model(&models.Category{}), q, "categories.created_at", "categories.id", func(t models.Category) (*time.Time, uint) {
+		return &t.CreatedAt, t.ID
+	})
 }
 
 func (r *categoryRepository) Create(category *models.Category) error {
-	return r.db.Create(category).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Sem slug, o hook BeforeCreate gera um a partir do título
+		if category.Slug != "" {
+			if err := releaseSlugHistory(tx, contentTypeCategory, category.Slug); err != nil {
+				return err
+			}
+		}
+		return mapError(tx.Create(category).Error)
+	})
 }
 
+// Renomeia gerando um slug novo; o antigo continua redirecionando
 func (r *categoryRepository) UpdateName(id uint, newTitle string) error {
-	return r.db.Model(&models.Category{}).Where("id = ?", id).Update("title", newTitle).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		repo := NewCategoryRepository(tx)
+		category, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if category.Title == newTitle {
+			return nil
+		}
+		if category.Slug, err = categoryKind.slugFor(tx, newTitle, id); err != nil {
+			return err
+		}
+		category.Title = newTitle
+		return repo.Update(category)
+	})
+}
+
+// Salva todos os campos; slug alterado vai para o histórico
+func (r *categoryRepository) Update(category *models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := categoryKind.trackSlug(tx, category.ID, category.Slug); err != nil {
+			return err
+		}
+		return mapError(tx.Save(category).Error)
+	})
+}
+
+func (r *categoryRepository) FindByIDs(ids []uint) ([]models.Category, error) {
+	var categories []models.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&categories).Error
+	return categories, mapError(err)
+}
+
+func (r *categoryRepository) FindByID(id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.First(&category, id).Error; err != nil {
+		return nil, mapError(err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) FindByTitle(title string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("LOWER(title) = LOWER(?)", title).First(&category).Error; err != nil {
+		return nil, mapError(err)
+	}
+	return &category, nil
+}
+
+// Categorias não entram no índice de busca, então não há o que reindexar
+func (r *categoryRepository) Delete(id, reassignTo uint) error {
+	if reassignTo > 0 {
+		return r.Merge([]uint{id}, reassignTo)
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := categoryKind.ensureExists(tx, []uint{id}); err != nil {
+			return err
+		}
+		return categoryKind.remove(tx, []uint{id}, 0)
+	})
+}
+
+func (r *categoryRepository) Merge(sourceIDs []uint, targetID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return categoryKind.merge(tx, sourceIDs, targetID)
+	})
+}
+
+func (r *categoryRepository) UsageCounts(ids []uint) (map[uint]TaxonomyUsage, error) {
+	return categoryKind.usage(r.db, ids)
+}
+
+func (r *categoryRepository) FindBySlug(slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, mapError(err)
+	}
+	return &category, nil
+}
+
+// Busca pelo slug atual; se o slug pertenceu a uma categoria renomeada ou fundida, devolve o slug novo
+func (r *categoryRepository) FindBySlugOrRedirect(slug string) (*models.Category, string, error) {
+	category, err := r.FindBySlug(slug)
+	if !errors.Is(err, ErrNotFound) {
+		return category, "", err
+	}
+	movedTo, err := categoryKind.redirectTarget(r.db, slug)
+	return nil, movedTo, err
+}
+
+func (r *categoryRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	return categoryKind.slugExists(r.db, slug, excludeID)
 }
@@ -64,6 +64,148 @@ func TestCategoryRepository(t *testing.T) {
 		err := repo.Create(&models.Category{Title: "Repetido"})
 
 		assert.Error(t, err, "O banco deveria barrar títulos duplicados via UniqueIndex")
-		assert.EqualError(t, err, "UNIQUE constraint failed: categories.title")
+		assert.ErrorIs(t, err, repositories.ErrDuplicateTitle)
+	})
+}
+
+func TestCategoryRepository_Errors(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewCategoryRepository(db)
+
+	t.Run("Deve retornar ErrNotFound ao renomear categoria inexistente", func(t *testing.T) {
+		assert.ErrorIs(t, repo.UpdateName(42, "Nada"), repositories.ErrNotFound)
+	})
+
+	t.Run("Deve propagar falhas do Count", func(t *testing.T) {
+		db.Migrator().DropTable(&models.Category{})
+		_, _, err := repo.FindAll(1, 10)
+		assert.Error(t, err)
+	})
+}
+
+func TestCategoryRepository_Management(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewCategoryRepository(db)
+	posts := repositories.NewPostRepository(db)
+	now := time.Now().UTC().Add(-time.Minute)
+
+	tutoriais := models.Category{Title: "Tutoriais"}
+	guias := models.Category{Title: "Guias"}
+	palestras := models.Category{Title: "Palestras"}
+	repo.Create(&tutoriais)
+	repo.Create(&guias)
+	repo.Create(&palestras)
+
+	post := models.Post{Title: "Guia", Slug: "guia", PostedAt: &now, Categories: []models.Category{guias, palestras}}
+	posts.Create(&post)
+
+	t.Run("Deve fundir e remover categorias atualizando o uso", func(t *testing.T) {
+		assert.NoError(t, repo.Merge([]uint{guias.ID}, tutoriais.ID))
+
+		usage, err := repo.UsageCounts([]uint{tutoriais.ID, palestras.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), usage[tutoriais.ID].Posts)
+
+		assert.NoError(t, repo.Delete(palestras.ID, 0))
+		found, _ := posts.FindByID(post.ID)
+		if assert.Len(t, found.Categories, 1) {
+			assert.Equal(t, "Tutoriais", found.Categories[0].Title)
+		}
+
+		category, err := repo.FindByTitle("tutoriais")
+		assert.NoError(t, err)
+		assert.Equal(t, tutoriais.ID, category.ID)
+	})
+}
+
+func TestCategoryRepository_Tree(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewCategoryRepository(db)
+	posts := repositories.NewPostRepository(db)
+	past := time.Now().UTC().Add(-time.Hour)
+
+	backend := models.Category{Title: "Backend"}
+	golang := models.Category{Title: "Go"}
+	concorrencia := models.Category{Title: "Concorrência"}
+	rust := models.Category{Title: "Rust"}
+	frontend := models.Category{Title: "Frontend"}
+	for _, c := range []*models.Category{&backend, &golang, &concorrencia, &rust, &frontend} {
+		repo.Create(c)
+	}
+	assert.NoError(t, repo.Move(golang.ID, &backend.ID, -1))
+	assert.NoError(t, repo.Move(rust.ID, &backend.ID, -1))
+	assert.NoError(t, repo.Move(concorrencia.ID, &golang.ID, 0))
+
+	titles := func(categories []models.Category) []string {
+		res := make([]string, 0, len(categories))
+		for _, c := range categories {
+			res = append(res, c.Title)
+		}
+		return res
+	}
+	children := func(parentID uint) []string {
+		var res []string
+		db.Model(&models.Category{}).Where("parent_id = ?", parentID).Order("position").Pluck("title", &res)
+		return res
+	}
+
+	t.Run("Deve ordenar as filhas pela posição", func(t *testing.T) {
+		assert.Equal(t, []string{"Go", "Rust"}, children(backend.ID))
+
+		assert.NoError(t, repo.Move(rust.ID, &backend.ID, 0))
+		assert.Equal(t, []string{"Rust", "Go"}, children(backend.ID))
+	})
+
+	t.Run("Deve montar breadcrumbs da raiz até a categoria", func(t *testing.T) {
+		ancestors, err := repo.Ancestors(concorrencia.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Backend", "Go", "Concorrência"}, titles(ancestors))
+
+		_, err = repo.Ancestors(999)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("Deve impedir ciclos", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Move(backend.ID, &concorrencia.ID, 0), repositories.ErrCategoryCycle)
+		assert.ErrorIs(t, repo.Move(golang.ID, &golang.ID, 0), repositories.ErrCategoryCycle)
+		assert.ErrorIs(t, repo.Move(golang.ID, new(uint), 0), repositories.ErrNotFound, "pai inexistente")
+
+		ids, _ := repo.SubtreeIDs(backend.ID)
+		assert.ElementsMatch(t, []uint{backend.ID, golang.ID, concorrencia.ID, rust.ID}, ids)
+	})
+
+	t.Run("Deve incluir subcategorias no filtro quando pedido", func(t *testing.T) {
+		posts.Create(&models.Post{Title: "Canais", Slug: "canais", PostedAt: &past, Categories: []models.Category{concorrencia}})
+		posts.Create(&models.Post{Title: "CSS", Slug: "css", PostedAt: &past, Categories: []models.Category{frontend}})
+		posts.Create(&models.Post{Title: "Borrow", Slug: "borrow", PostedAt: &past, Categories: []models.Category{rust, frontend}})
+
+		slugs := func(filter repositories.ContentFilter) []string {
+			found, _, err := posts.Search(1, 10, filter)
+			assert.NoError(t, err)
+			res := make([]string, 0, len(found))
+			for _, p := range found {
+				res = append(res, p.Slug)
+			}
+			return res
+		}
+
+		assert.Empty(t, slugs(repositories.ContentFilter{Categories: []uint{backend.ID}}), "sem a opção só vale a própria categoria")
+		assert.ElementsMatch(t, []string{"canais", "borrow"},
+			slugs(repositories.ContentFilter{Categories: []uint{backend.ID}, IncludeSubcategories: true}))
+		assert.Equal(t, []string{"borrow"},
+			slugs(repositories.ContentFilter{Categories: []uint{backend.ID, frontend.ID}, IncludeSubcategories: true}), "AND por subárvore")
+		assert.Equal(t, []string{"css"},
+			slugs(repositories.ContentFilter{AnyCategories: []uint{frontend.ID}, ExcludeCategories: []uint{backend.ID}, IncludeSubcategories: true}))
+	})
+
+	t.Run("Deve subir as filhas ao remover ou fundir", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(golang.ID, 0))
+		assert.Equal(t, []string{"Rust", "Concorrência"}, children(backend.ID))
+
+		assert.NoError(t, repo.Merge([]uint{backend.ID}, concorrencia.ID))
+		tree, _ := repo.Tree()
+		for _, c := range tree {
+			assert.Nil(t, c.ParentID, "%s deveria ter virado raiz", c.Title)
+		}
 	})
 }
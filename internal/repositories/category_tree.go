@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Ids das categorias informadas e de todas as descendentes. UNION (e não UNION
+// ALL) descarta repetidos, então a recursão termina mesmo com dados inconsistentes.
+const categorySubtreeSQL = "WITH RECURSIVE subtree(id) AS (" +
+	"SELECT id FROM categories WHERE id IN ? " +
+	"UNION SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id" +
+	") SELECT id FROM subtree"
+
+// Limite de níveis percorridos ao subir até a raiz
+const maxCategoryDepth = 32
+
+// Caminho da raiz até a categoria (inclusive), para breadcrumbs
+func (r *categoryRepository) Ancestors(id uint) ([]models.Category, error) {
+	if _, err := r.FindByID(id); err != nil {
+		return nil, err
+	}
+	var categories []models.Category
+	err := r.db.Raw(
+		"WITH RECURSIVE ancestors(id, parent_id, depth) AS ("+
+			"SELECT id, parent_id, 0 FROM categories WHERE id = ? "+
+			"UNION ALL SELECT categories.id, categories.parent_id, ancestors.depth + 1 "+
+			"FROM categories JOIN ancestors ON categories.id = ancestors.parent_id WHERE ancestors.depth < ?"+
+			") SELECT categories.* FROM categories JOIN ancestors ON ancestors.id = categories.id ORDER BY ancestors.depth DESC",
+		id, maxCategoryDepth,
+	).Scan(&categories).Error
+	return categories, mapError(err)
+}
+
+// Ids da categoria e de todas as suas descendentes
+func (r *categoryRepository) SubtreeIDs(id uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.Raw(categorySubtreeSQL, []uint{id}).Scan(&ids).Error
+	return ids, mapError(err)
+}
+
+// Todas as categorias em ordem de exibição; a montagem da árvore fica com quem chama
+func (r *categoryRepository) Tree() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("position asc").Order("title asc").Find(&categories).Error
+	return categories, mapError(err)
+}
+
+// Coloca a categoria sob parentID (nil = raiz) na posição indicada entre as irmãs;
+// posição negativa ou além do fim coloca por último. Mover para dentro da própria
+// subárvore devolve ErrCategoryCycle.
+func (r *categoryRepository) Move(id uint, parentID *uint, position int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		repo := &categoryRepository{db: tx}
+		category, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if parentID != nil {
+			if err := categoryKind.ensureExists(tx, []uint{*parentID}); err != nil {
+				return err
+			}
+			subtree, err := repo.SubtreeIDs(id)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(subtree, *parentID) {
+				return ErrCategoryCycle
+			}
+		}
+
+		previousParent := category.ParentID
+		if err := placeCategory(tx, id, parentID, position); err != nil {
+			return err
+		}
+		if !sameParent(previousParent, parentID) {
+			return placeCategory(tx, 0, previousParent, 0) // Fecha o buraco deixado entre as antigas irmãs
+		}
+		return nil
+	})
+}
+
+// Renumera as filhas de parentID em sequência, inserindo id (quando > 0) em position
+func placeCategory(tx *gorm.DB, id uint, parentID *uint, position int) error {
+	var siblings []uint
+	query := tx.Model(&models.Category{}).Scopes(childrenOf(parentID)).Where("id <> ?", id).Order("position asc").Order("title asc")
+	if err := query.Pluck("id", &siblings).Error; err != nil {
+		return mapError(err)
+	}
+
+	if id > 0 {
+		if position < 0 || position > len(siblings) {
+			position = len(siblings)
+		}
+		siblings = slices.Insert(siblings, position, id)
+	}
+	for i, sibling := range siblings {
+		err := tx.Model(&models.Category{}).Where("id = ?", sibling).
+			Updates(map[string]any{"parent_id": parentID, "position": i}).Error
+		if err != nil {
+			return mapError(err)
+		}
+	}
+	return nil
+}
+
+// Antes de remover categorias, as filhas sobem para o pai da removida, depois das
+// irmãs que já estavam lá. Uma por vez, para que cadeias removidas juntas
+// (A > B > C) subam até o ancestral que fica.
+func liftChildren(tx *gorm.DB, ids []uint) error {
+	for _, id := range ids {
+		var removed models.Category
+		if err := tx.Select("id", "parent_id").First(&removed, id).Error; err != nil {
+			return mapError(err)
+		}
+		var next int
+		siblings := tx.Model(&models.Category{}).Scopes(childrenOf(removed.ParentID)).Select("COALESCE(MAX(position), -1) + 1")
+		if err := siblings.Scan(&next).Error; err != nil {
+			return mapError(err)
+		}
+		err := tx.Model(&models.Category{}).Where("parent_id = ?", id).Updates(map[string]any{
+			"parent_id": removed.ParentID,
+			"position":  gorm.Expr("position + ?", next),
+		}).Error
+		if err != nil {
+			return mapError(err)
+		}
+	}
+	return nil
+}
+
+// Filhas de parentID; nil são as raízes
+func childrenOf(parentID *uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if parentID == nil {
+			return db.Where("parent_id IS NULL")
+		}
+		return db.Where("parent_id = ?", *parentID)
+	}
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Filtros de busca comuns a posts e projetos. Campos vazios não filtram.
+type ContentFilter struct {
+	Query string
+
+	Tags        []uint // Precisa ter todas (AND)
+	AnyTags     []uint // Precisa ter ao menos uma (OR)
+	ExcludeTags []uint // Não pode ter nenhuma (NOT)
+
+	Categories        []uint
+	AnyCategories     []uint
+	ExcludeCategories []uint
+	// Cada categoria vale também pelas descendentes: um post em "Concorrência"
+	// aparece em category_id=<Backend>
+	IncludeSubcategories bool
+
+	PostedFrom *time.Time // PostedAt >= PostedFrom
+	PostedTo   *time.Time // PostedAt < PostedTo
+
+	OnlyPosted bool   // Só o que está visível ao público
+	Status     string // Estado editorial; vazio não filtra
+}
+
+// Se algum critério restringe o resultado além de OnlyPosted/Status, isto é,
+// se vale ir para Search em vez de FindAll
+func (f ContentFilter) HasCriteria() bool {
+	return f.Query != "" ||
+		len(f.Tags) > 0 || len(f.AnyTags) > 0 || len(f.ExcludeTags) > 0 ||
+		len(f.Categories) > 0 || len(f.AnyCategories) > 0 || len(f.ExcludeCategories) > 0 ||
+		f.PostedFrom != nil || f.PostedTo != nil
+}
+
+// Tabelas de junção de um tipo de conteúdo, para montar os filtros por taxonomia
+type taxonomyTables struct {
+	table        string // posts / projects
+	tagJoin      string // post_tags / project_tags
+	categoryJoin string
+	fk           string // post_id / project_id
+}
+
+var (
+	postTaxonomy    = taxonomyTables{table: "posts", tagJoin: "post_tags", categoryJoin: "post_categories", fk: "post_id"}
+	projectTaxonomy = taxonomyTables{table: "projects", tagJoin: "project_tags", categoryJoin: "project_categories", fk: "project_id"}
+)
+
+// Aplica os filtros de taxonomia e data. Cada critério é uma subconsulta sobre
+// a tabela de junção, então o item aparece uma vez só, sem precisar de DISTINCT.
+func (f ContentFilter) scope(t taxonomyTables) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(
+			matchAll(t.table, t.tagJoin, t.fk, "tag_id", f.Tags),
+			matchAny(t.table, t.tagJoin, t.fk, "tag_id", f.AnyTags),
+			matchNone(t.table, t.tagJoin, t.fk, "tag_id", f.ExcludeTags),
+		).Scopes(f.categoryScopes(t)...)
+		if f.PostedFrom != nil {
+			db = db.Where(t.table+".posted_at >= ?", f.PostedFrom.UTC())
+		}
+		if f.PostedTo != nil {
+			db = db.Where(t.table+".posted_at < ?", f.PostedTo.UTC())
+		}
+		return db
+	}
+}
+
+func (f ContentFilter) categoryScopes(t taxonomyTables) []func(db *gorm.DB) *gorm.DB {
+	if !f.IncludeSubcategories {
+		return []func(db *gorm.DB) *gorm.DB{
+			matchAll(t.table, t.categoryJoin, t.fk, "category_id", f.Categories),
+			matchAny(t.table, t.categoryJoin, t.fk, "category_id", f.AnyCategories),
+			matchNone(t.table, t.categoryJoin, t.fk, "category_id", f.ExcludeCategories),
+		}
+	}
+	// AND: uma condição por categoria, cada uma satisfeita por qualquer nó da subárvore
+	scopes := []func(db *gorm.DB) *gorm.DB{
+		matchSubtree(t, f.AnyCategories, "IN"),
+		matchSubtree(t, f.ExcludeCategories, "NOT IN"),
+	}
+	for _, id := range uniqueIDs(f.Categories) {
+		scopes = append(scopes, matchSubtree(t, []uint{id}, "IN"))
+	}
+	return scopes
+}
+
+// Itens ligados (op "IN") ou não (op "NOT IN") a alguma categoria das subárvores de ids
+func matchSubtree(t taxonomyTables, ids []uint, op string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(ids) == 0 {
+			return db
+		}
+		return db.Where(
+			t.table+".id "+op+" (SELECT "+t.fk+" FROM "+t.categoryJoin+" WHERE category_id IN ("+categorySubtreeSQL+"))",
+			ids,
+		)
+	}
+}
+
+// Itens ligados a todos os ids: agrupa a junção e exige a contagem completa
+func matchAll(table, join, fk, column string, ids []uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		ids = uniqueIDs(ids)
+		if len(ids) == 0 {
+			return db
+		}
+		sub := db.Session(&gorm.Session{NewDB: true}).Table(join).Select(fk).
+			Where(column+" IN ?", ids).Group(fk).Having("COUNT(DISTINCT "+column+") = ?", len(ids))
+		return db.Where(table+".id IN (?)", sub)
+	}
+}
+
+func matchAny(table, join, fk, column string, ids []uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(ids) == 0 {
+			return db
+		}
+		sub := db.Session(&gorm.Session{NewDB: true}).Table(join).Select(fk).Where(column+" IN ?", ids)
+		return db.Where(table+".id IN (?)", sub)
+	}
+}
+
+func matchNone(table, join, fk, column string, ids []uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(ids) == 0 {
+			return db
+		}
+		sub := db.Session(&gorm.Session{NewDB: true}).Table(join).Select(fk).Where(column+" IN ?", ids)
+		return db.Where(table+".id NOT IN (?)", sub)
+	}
+}
+
+// Ids repetidos fariam o HAVING de matchAll nunca bater
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/search"
+	"cms-headless/internal/utils"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Resultado da busca unificada: exatamente um entre Post e Project é preenchido,
+// conforme ContentType
+type ContentHit struct {
+	ContentType string
+	Post        *models.Post
+	Project     *models.Project
+	Score       float64
+}
+
+// Busca textual em posts e projetos numa única lista ranqueada
+type ContentSearchRepository interface {
+	WithContext(ctx context.Context) ContentSearchRepository
+	Search(page, pageSize int, queryText string, onlyPosted bool) ([]ContentHit, int64, error)
+}
+
+type contentSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewContentSearchRepository(db *gorm.DB) ContentSearchRepository {
+	return &contentSearchRepository{db: db}
+}
+
+func (r *contentSearchRepository) WithContext(ctx context.Context) ContentSearchRepository {
+	return &contentSearchRepository{db: r.db.WithContext(ctx)}
+}
+
+type rankedContent struct {
+	ContentType string
+	ContentID   uint
+	SearchRank  float64
+}
+
+// Parte de search_documents, então o score vem do mesmo índice para os dois tipos
+// e a ordenação é comparável
+func (r *contentSearchRepository) Search(page, pageSize int, queryText string, onlyPosted bool) ([]ContentHit, int64, error) {
+	var total int64
+	if err := r.query(queryText, onlyPosted).Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	var ranked []rankedContent
+	err := r.query(queryText, onlyPosted).
+		Select("search_documents.content_type, search_documents.content_id, ? AS search_rank", search.Rank(r.db, queryText)).
+		Order("search_rank DESC").
+		Order("search_documents.content_type").Order("search_documents.content_id DESC").
+		Scopes(utils.PaginateRepository(page, pageSize)).
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	var postIDs, projectIDs []uint
+	for _, rc := range ranked {
+		if rc.ContentType == contentTypePost {
+			postIDs = append(postIDs, rc.ContentID)
+		} else {
+			projectIDs = append(projectIDs, rc.ContentID)
+		}
+	}
+	var posts []models.Post
+	if err := r.db.Preload("Tags").Preload("Categories").Where("id IN ?", postIDs).Find(&posts).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+	var projects []models.Project
+	if err := r.db.Preload("Tags").Preload("Categories").Where("id IN ?", projectIDs).Find(&projects).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	postsByID := make(map[uint]*models.Post, len(posts))
+	for i := range posts {
+		postsByID[posts[i].ID] = &posts[i]
+	}
+	projectsByID := make(map[uint]*models.Project, len(projects))
+	for i := range projects {
+		projectsByID[projects[i].ID] = &projects[i]
+	}
+
+	hits := make([]ContentHit, 0, len(ranked))
+	for _, rc := range ranked {
+		hit := ContentHit{ContentType: rc.ContentType, Score: rc.SearchRank}
+		if rc.ContentType == contentTypePost {
+			hit.Post = postsByID[rc.ContentID]
+		} else {
+			hit.Project = projectsByID[rc.ContentID]
+		}
+		if hit.Post != nil || hit.Project != nil {
+			hits = append(hits, hit)
+		}
+	}
+	return hits, total, nil
+}
+
+// Itens fora da lixeira (e visíveis, se onlyPosted) de qualquer dos dois tipos
+func (r *contentSearchRepository) query(queryText string, onlyPosted bool) *gorm.DB {
+	query := r.db.Table("search_documents").
+		Joins("LEFT JOIN posts ON search_documents.content_type = ? AND posts.id = search_documents.content_id AND posts.deleted_at IS NULL", contentTypePost).
+		Joins("LEFT JOIN projects ON search_documents.content_type = ? AND projects.id = search_documents.content_id AND projects.deleted_at IS NULL", contentTypeProject).
+		Where("posts.id IS NOT NULL OR projects.id IS NOT NULL").
+		Scopes(search.Match(queryText))
+	if onlyPosted {
+		query = query.Where(clause.Or(visibleCondition("posts"), visibleCondition("projects")))
+	}
+	return query
+}
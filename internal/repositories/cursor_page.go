@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"cms-headless/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Página de uma listagem por cursor
+type CursorPage[T any] struct {
+	Items []T
+	Next  *utils.Cursor // nil na última página
+	Total *int64        // Só quando pedido em CursorQuery.WithTotal
+}
+
+// Busca uma página de query (sem ordenação nem limite) por keyset sobre
+// (timeColumn, idColumn). Um item a mais é lido para saber se há próxima página.
+func findPage[T any](query *gorm.DB, q utils.CursorQuery, timeColumn, idColumn string, key func(T) (*time.Time, uint)) (CursorPage[T], error) {
+	var page CursorPage[T]
+	if q.WithTotal {
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return page, mapError(err)
+		}
+		page.Total = &total
+	}
+
+	_, limit := utils.NormalizePagination(1, q.Limit)
+	var items []T
+	err := query.Scopes(utils.Keyset(timeColumn, idColumn, q.After)).Limit(limit + 1).Find(&items).Error
+	if err != nil {
+		return page, mapError(err)
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+		t, id := key(items[limit-1])
+		page.Next = &utils.Cursor{Time: t, ID: id}
+	}
+	if items == nil {
+		items = []T{}
+	}
+	page.Items = items
+	return page, nil
+}
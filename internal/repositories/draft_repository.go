@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DraftRepository interface {
+	WithContext(ctx context.Context) DraftRepository
+	Find(contentType string, contentID uint) (*models.ContentDraft, error)
+	FindByContentIDs(contentType string, ids []uint) ([]models.ContentDraft, error)
+	FindBySlug(contentType, slug string) (*models.ContentDraft, error)
+	Save(draft *models.ContentDraft) error
+	Delete(contentType string, contentID uint) error
+}
+
+type draftRepository struct {
+	db *gorm.DB
+}
+
+func NewDraftRepository(db *gorm.DB) DraftRepository {
+	return &draftRepository{db: db}
+}
+
+func (r *draftRepository) WithContext(ctx context.Context) DraftRepository {
+	return &draftRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *draftRepository) Find(contentType string, contentID uint) (*models.ContentDraft, error) {
+	var draft models.ContentDraft
+	err := r.db.Where("content_type = ? AND content_id = ?", contentType, contentID).First(&draft).Error
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &draft, nil
+}
+
+// Rascunhos de uma página de conteúdos, para sobrepor numa listagem sem N consultas
+func (r *draftRepository) FindByContentIDs(contentType string, ids []uint) ([]models.ContentDraft, error) {
+	var drafts []models.ContentDraft
+	if len(ids) == 0 {
+		return drafts, nil
+	}
+	err := r.db.Where("content_type = ? AND content_id IN ?", contentType, ids).Find(&drafts).Error
+	return drafts, mapError(err)
+}
+
+// Rascunho que renomeou o conteúdo para slug (usado pelo preview)
+func (r *draftRepository) FindBySlug(contentType, slug string) (*models.ContentDraft, error) {
+	var draft models.ContentDraft
+	err := r.db.Where("content_type = ? AND slug = ?", contentType, slug).Order("id").First(&draft).Error
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &draft, nil
+}
+
+// Um rascunho por conteúdo: salvar de novo sobrescreve o anterior
+func (r *draftRepository) Save(draft *models.ContentDraft) error {
+	return mapError(r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "content_type"}, {Name: "content_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"title", "slug", "short_description", "body", "demo_url", "repo_url",
+			"tag_ids", "category_ids", "author", "updated_at",
+		}),
+	}).Create(draft).Error)
+}
+
+func (r *draftRepository) Delete(contentType string, contentID uint) error {
+	return affectedOrNotFound(r.db.Where("content_type = ? AND content_id = ?", contentType, contentID).Delete(&models.ContentDraft{}))
+}
+
+func purgeDrafts(tx *gorm.DB, contentType string, ids []uint) error {
+	return mapError(tx.Where("content_type = ? AND content_id IN ?", contentType, ids).Delete(&models.ContentDraft{}).Error)
+}
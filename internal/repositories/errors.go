@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// Erros de domínio devolvidos pelos repositórios, independentes do driver (SQLite/Postgres)
+var (
+	ErrNotFound         = errors.New("registro não encontrado")
+	ErrDuplicateSlug    = errors.New("slug já está em uso")
+	ErrDuplicateTitle   = errors.New("título já está em uso")
+	ErrInvalidReference = errors.New("referência inválida")
+	ErrRestoreConflict  = errors.New("conflito ao restaurar")
+	ErrCategoryCycle    = errors.New("categoria não pode ficar abaixo de si mesma")
+)
+
+// Códigos SQLSTATE do Postgres
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// Traduz erros do GORM/driver para os erros de domínio.
+// O erro original é mantido na cadeia para logs (errors.Is continua funcionando).
+func mapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			// Mensagem no formato "UNIQUE constraint failed: posts.slug"
+			return duplicateError(sqliteErr.Error(), err)
+		case sqlite3.ErrConstraintForeignKey:
+			return fmt.Errorf("%w: %w", ErrInvalidReference, err)
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			// ConstraintName no formato "idx_posts_slug"
+			return duplicateError(pgErr.ConstraintName, err)
+		case pgForeignKeyViolation:
+			return fmt.Errorf("%w: %w", ErrInvalidReference, err)
+		}
+	}
+
+	return err
+}
+
+func duplicateError(constraint string, err error) error {
+	switch {
+	case strings.Contains(constraint, "slug"):
+		return fmt.Errorf("%w: %w", ErrDuplicateSlug, err)
+	case strings.Contains(constraint, "title"), strings.Contains(constraint, "alias"):
+		// Aliases de tags disputam o mesmo espaço de nomes dos títulos
+		return fmt.Errorf("%w: %w", ErrDuplicateTitle, err)
+	}
+	return err
+}
+
+// Updates direcionados por ID que não afetam nenhuma linha indicam registro inexistente
+func affectedOrNotFound(tx *gorm.DB) error {
+	if tx.Error != nil {
+		return mapError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
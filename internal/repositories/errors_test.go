@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// O Postgres não roda nos testes, então validamos o mapeamento com erros construídos à mão
+func TestMapError_Postgres(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"slug duplicado", &pgconn.PgError{Code: "23505", ConstraintName: "idx_posts_slug"}, ErrDuplicateSlug},
+		{"título duplicado", &pgconn.PgError{Code: "23505", ConstraintName: "idx_projects_title"}, ErrDuplicateTitle},
+		{"FK inválida", &pgconn.PgError{Code: "23503", ConstraintName: "fk_post_tags_tag"}, ErrInvalidReference},
+		{"erro embrulhado", fmt.Errorf("tx: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_tags_title"}), ErrDuplicateTitle},
+		{"não encontrado", gorm.ErrRecordNotFound, ErrNotFound},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.ErrorIs(t, mapError(c.err), c.want)
+		})
+	}
+
+	t.Run("Deve preservar erros desconhecidos", func(t *testing.T) {
+		other := errors.New("conexão perdida")
+		assert.Equal(t, other, mapError(other))
+		assert.Nil(t, mapError(nil))
+	})
+}
@@ -2,23 +2,42 @@ package repositories
 
 import (
 	"cms-headless/internal/models"
+	"cms-headless/internal/search"
 	"cms-headless/internal/utils"
+	"context"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type PostRepository interface {
-	FindAll(page, pageSize int, onlyPosted bool) ([]models.Post, int64, error)
+	WithContext(ctx context.Context) PostRepository
+	FindAll(page, pageSize int, onlyPosted bool, status string) ([]models.Post, int64, error)
+	FindPage(q utils.CursorQuery, filter ContentFilter) (CursorPage[models.Post], error)
 	FindBySlug(slug string, onlyPosted bool) (*models.Post, error)
+	FindBySlugOrRedirect(slug string, onlyPosted bool) (*models.Post, string, error)
 	FindByID(id uint) (*models.Post, error)
+	SlugExists(slug string, excludeID uint) (bool, error)
 	Create(post *models.Post) error
 	Update(post *models.Post) error
 	Delete(id uint) error
 	SetPostedAt(id uint, t *time.Time) error
+	SetStatus(id uint, status string) error
+	SetUnpublishAt(id uint, t *time.Time) error
+	FindPublishedBetween(from, to time.Time) ([]models.Post, error)
+	FindUnpublishedBetween(from, to time.Time) ([]models.Post, error)
+	MarkPublishNotified(id uint, at time.Time) error
+	MarkUnpublishNotified(id uint, at time.Time) error
 	ReplaceTags(post *models.Post, tags []models.Tag) error
 	ReplaceCategories(post *models.Post, categories []models.Category) error
-	Search(page, pageSize int, categoryID, tagID uint, queryText string, onlyPosted bool) ([]models.Post, int64, error)
+	FindTrashed(page, pageSize int) ([]models.Post, int64, error)
+	Restore(id uint) error
+	Purge(id uint) error
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+	Search(page, pageSize int, filter ContentFilter) ([]models.Post, int64, error)
+	SearchHits(page, pageSize int, filter ContentFilter) ([]SearchHit[models.Post], int64, error)
+	SearchFacets(filter ContentFilter) (Facets, error)
 }
 
 type postRepository struct {
@@ -29,22 +48,38 @@ func NewPostRepository(db *gorm.DB) PostRepository {
 	return &postRepository{db: db}
 }
 
-func (r *postRepository) FindAll(page, pageSize int, onlyPosted bool) ([]models.Post, int64, error) {
+// Retorna uma cópia do repositório cujas consultas respeitam cancelamento e prazo do ctx
+func (r *postRepository) WithContext(ctx context.Context) PostRepository {
+	return &postRepository{db: r.db.WithContext(ctx)}
+}
+
+// status vazio não filtra pelo estado editorial
+func (r *postRepository) FindAll(page, pageSize int, onlyPosted bool, status string) ([]models.Post, int64, error) {
 	var posts []models.Post
 	var total int64
 
-	query := r.db.Model(&models.Post{})
+	query := r.db.Model(&models.Post{}).Scopes(withStatus("posts", status))
 	if onlyPosted {
-		now := time.Now().UTC()
-		query = query.Where("posted_at IS NOT NULL AND posted_at <= ?", now)
+		query = query.Scopes(visible("posts"))
 	}
 
-	query.Count(&total)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
 	err := query.Scopes(utils.PaginateRepository(page, pageSize)).
 		Preload("Tags").Preload("Categories").
 		Order("posted_at desc").Find(&posts).Error
 
-	return posts, total, err
+	return posts, total, mapError(err)
+}
+
+// Listagem por cursor sobre (posted_at, id), com os mesmos filtros de Search;
+// itens sem PostedAt (rascunhos) vêm no fim
+func (r *postRepository) FindPage(q utils.CursorQuery, filter ContentFilter) (CursorPage[models.Post], error) {
+	query := r.searchQuery(filter).Preload("Tags").Preload("Categories")
+	return findPage(query, q, "posts.posted_at", "posts.id", func(p models.Post) (*time.Time, uint) {
+		return p.PostedAt, p.ID
+	})
 }
 
 func (r *postRepository) FindBySlug(slug string, onlyPosted bool) (*models.Post, error) {
@@ -52,13 +87,13 @@ func (r *postRepository) FindBySlug(slug string, onlyPosted bool) (*models.Post,
 	query := r.db.Model(&models.Post{}).Where("slug = ?", slug)
 
 	if onlyPosted {
-		query = query.Where("posted_at IS NOT NULL AND posted_at <= ?", time.Now())
+		query = query.Scopes(visible("posts"))
 	}
 
 	// O GORM preencherá o ponteiro se encontrar o registro
 	err := query.Preload("Tags").Preload("Categories").First(&post).Error
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return post, nil
@@ -69,70 +104,282 @@ func (r *postRepository) FindByID(id uint) (*models.Post, error) {
 	err := r.db.Preload("Tags").Preload("Categories").First(&post, id).Error
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return post, nil
 }
 
 func (r *postRepository) Create(post *models.Post) error {
-	return r.db.Create(post).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := releaseSlugHistory(tx, contentTypePost, post.Slug); err != nil {
+			return err
+		}
+		if err := tx.Create(post).Error; err != nil {
+			return mapError(err)
+		}
+		return indexPost(tx, post.ID)
+	})
 }
 
 func (r *postRepository) Update(post *models.Post) error {
-	return r.db.Save(post).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Slug alterado: o antigo vai para o histórico para virar redirecionamento
+		var current models.Post
+		err := tx.Select("id", "slug").First(&current, post.ID).Error
+		if err == nil && current.Slug != post.Slug {
+			if err := recordSlugHistory(tx, contentTypePost, post.ID, current.Slug); err != nil {
+				return err
+			}
+		}
+		if err := releaseSlugHistory(tx, contentTypePost, post.Slug); err != nil {
+			return err
+		}
+		if err := tx.Save(post).Error; err != nil {
+			return mapError(err)
+		}
+		return indexPost(tx, post.ID)
+	})
 }
 
 func (r *postRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Post{}, id).Error
+	return affectedOrNotFound(r.db.Delete(&models.Post{}, id))
+}
+
+func (r *postRepository) SetStatus(id uint, status string) error {
+	return affectedOrNotFound(r.db.Model(&models.Post{}).Where("id = ?", id).Update("status", status))
 }
 
 func (r *postRepository) SetPostedAt(id uint, t *time.Time) error {
-	return r.db.Model(&models.Post{}).Where("id = ?", id).Update("posted_at", t).Error
+	return affectedOrNotFound(r.db.Model(&models.Post{}).Where("id = ?", id).Update("posted_at", t))
 }
 
 func (r *postRepository) ReplaceTags(post *models.Post, tags []models.Tag) error {
-	return r.db.Model(post).Association("Tags").Replace(tags)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(post).Association("Tags").Replace(tags); err != nil {
+			return mapError(err)
+		}
+		return indexPost(tx, post.ID)
+	})
 }
 
 func (r *postRepository) ReplaceCategories(post *models.Post, categories []models.Category) error {
-	return r.db.Model(post).Association("Categories").Replace(categories)
+	return mapError(r.db.Model(post).Association("Categories").Replace(categories))
+}
+
+func (r *postRepository) Search(page, pageSize int, filter ContentFilter) ([]models.Post, int64, error) {
+	hits, total, err := r.SearchHits(page, pageSize, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	posts := make([]models.Post, 0, len(hits))
+	for _, h := range hits {
+		posts = append(posts, h.Item)
+	}
+	return posts, total, nil
 }
 
-func (r *postRepository) Search(page, pageSize int, categoryID, tagID uint, queryText string, onlyPosted bool) ([]models.Post, int64, error) {
+// Como Search, mas com a relevância de cada item (com texto, os mais relevantes vêm primeiro)
+func (r *postRepository) SearchHits(page, pageSize int, filter ContentFilter) ([]SearchHit[models.Post], int64, error) {
+	var total int64
+	if err := r.searchQuery(filter).Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	// Primeiro só IDs e relevância da página; os posts completos vêm numa segunda consulta
+	var ranked []rankedID
+	query := r.searchQuery(filter)
+	if filter.Query != "" {
+		query = query.Select("posts.id, ? AS search_rank", search.Rank(r.db, filter.Query)).Order("search_rank DESC")
+	} else {
+		query = query.Select("posts.id, 0 AS search_rank")
+	}
+	err := query.Scopes(utils.PaginateRepository(page, pageSize)).
+		Order("posts.posted_at desc").Order("posts.id desc").
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	var posts []models.Post
+	if err := r.db.Preload("Tags").Preload("Categories").Where("id IN ?", rankedIDs(ranked)).Find(&posts).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+	return toHits(ranked, posts, func(p models.Post) uint { return p.ID }), total, nil
+}
+
+// Contagem por tag e por categoria dentro do mesmo conjunto filtrado da busca
+func (r *postRepository) SearchFacets(filter ContentFilter) (Facets, error) {
+	ids := r.searchQuery(filter).Select("posts.id")
+	return searchFacets(r.db, "post_tags", "post_categories", "post_id", ids)
+}
+
+func (r *postRepository) searchQuery(filter ContentFilter) *gorm.DB {
+	query := r.db.Model(&models.Post{}).Scopes(filter.scope(postTaxonomy))
+	if filter.Query != "" {
+		query = query.Scopes(search.Filter("posts", contentTypePost, filter.Query))
+	}
+	if filter.OnlyPosted {
+		query = query.Scopes(visible("posts"))
+	}
+	return query.Scopes(withStatus("posts", filter.Status))
+}
+
+// Lixeira: itens com soft delete, do mais recente para o mais antigo
+func (r *postRepository) FindTrashed(page, pageSize int) ([]models.Post, int64, error) {
 	var posts []models.Post
 	var total int64
 
-	query := r.db.Model(&models.Post{})
+	query := r.db.Unscoped().Model(&models.Post{}).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+	err := query.Scopes(utils.PaginateRepository(page, pageSize)).
+		Preload("Tags").Preload("Categories").
+		Order("deleted_at desc").Find(&posts).Error
+
+	return posts, total, mapError(err)
+}
+
+// Restaura da lixeira; falha com ErrRestoreConflict se o título/slug foi reutilizado nesse meio tempo
+func (r *postRepository) Restore(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var trashed models.Post
+		err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&trashed).Error
+		if err != nil {
+			return mapError(err)
+		}
+		if err := checkRestoreConflict(tx, &models.Post{}, trashed.Title, trashed.Slug); err != nil {
+			return err
+		}
+		return mapError(tx.Unscoped().Model(&models.Post{}).Where("id = ?", id).Update("deleted_at", nil).Error)
+	})
+}
+
+// Remove definitivamente um item que já está na lixeira (junto com as linhas de junção)
+func (r *postRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Unscoped().Model(&models.Post{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return mapError(err)
+		}
+		if len(ids) == 0 {
+			return ErrNotFound
+		}
+		return purgePosts(tx, ids)
+	})
+}
+
+// Usado pelo job de retenção: expurga tudo que está na lixeira desde antes de cutoff
+func (r *postRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Unscoped().Model(&models.Post{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return mapError(err)
+		}
+		purged = int64(len(ids))
+		return purgePosts(tx, ids)
+	})
+	return purged, err
+}
 
-	if categoryID > 0 {
-		query = query.Joins("JOIN post_categories ON post_categories.post_id = posts.id").
-			Where("post_categories.category_id = ?", categoryID)
+func purgePosts(tx *gorm.DB, ids []uint) error {
+	if err := tx.Exec("DELETE FROM post_tags WHERE post_id IN ?", ids).Error; err != nil {
+		return mapError(err)
+	}
+	if err := tx.Exec("DELETE FROM post_categories WHERE post_id IN ?", ids).Error; err != nil {
+		return mapError(err)
+	}
+	if err := purgeSlugHistory(tx, contentTypePost, ids); err != nil {
+		return err
 	}
-	if tagID > 0 {
-		query = query.Joins("JOIN post_tags ON post_tags.post_id = posts.id").
-			Where("post_tags.tag_id = ?", tagID)
+	if err := purgeRevisions(tx, contentTypePost, ids); err != nil {
+		return err
 	}
+	if err := purgeDrafts(tx, contentTypePost, ids); err != nil {
+		return err
+	}
+	if err := purgeStateChanges(tx, contentTypePost, ids); err != nil {
+		return err
+	}
+	if err := purgeSearchDocuments(tx, contentTypePost, ids); err != nil {
+		return err
+	}
+	return mapError(tx.Unscoped().Delete(&models.Post{}, ids).Error)
+}
 
-	if queryText != "" {
-		// Importante: especificar posts.title para evitar ambiguidade com tags.title
-		query = query.Where("posts.title LIKE ? OR posts.short_description LIKE ?", "%"+queryText+"%", "%"+queryText+"%")
+// Verifica se o slug já pertence a outro item ativo (excludeID ignora o próprio item no update)
+func (r *postRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Post{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, mapError(err)
+}
+
+// Busca pelo slug atual; se o slug pertenceu a um item renomeado, devolve o slug novo
+// (segundo retorno) para o front responder com 301.
+func (r *postRepository) FindBySlugOrRedirect(slug string, onlyPosted bool) (*models.Post, string, error) {
+	post, err := r.FindBySlug(slug, onlyPosted)
+	if !errors.Is(err, ErrNotFound) {
+		return post, "", err
+	}
+
+	id, err := findSlugHistory(r.db, contentTypePost, slug)
+	if err != nil {
+		return nil, "", err
 	}
 
+	query := r.db.Model(&models.Post{}).Where("id = ?", id)
 	if onlyPosted {
-		query = query.Where("posts.posted_at IS NOT NULL AND posts.posted_at <= ?", time.Now().UTC())
+		query = query.Scopes(visible("posts"))
+	}
+	var current models.Post
+	if err := query.Select("id", "slug").First(&current).Error; err != nil {
+		return nil, "", mapError(err)
 	}
+	return nil, current.Slug, nil
+}
 
-	// Contagem distinta para não contar o mesmo post múltiplas vezes devido aos joins
-	query.Distinct("posts.id").Count(&total)
+func (r *postRepository) SetUnpublishAt(id uint, t *time.Time) error {
+	return affectedOrNotFound(r.db.Model(&models.Post{}).Where("id = ?", id).Update("unpublish_at", t))
+}
 
-	// No Find, selecionamos apenas as colunas de posts para o Scan correto
-	err := query.Select("posts.*").
-		Scopes(utils.PaginateRepository(page, pageSize)).
-		Preload("Tags").
-		Preload("Categories").
-		Order("posts.posted_at desc").
-		Find(&posts).Error
+// Itens cujo PostedAt caiu no intervalo (from, to] e que ainda estavam no ar em "to"
+// (não arquivados). Só entram os que ainda não tiveram o evento emitido para o
+// PostedAt atual: publicações imediatas já o emitiram no serviço.
+func (r *postRepository) FindPublishedBetween(from, to time.Time) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Where("posted_at > ? AND posted_at <= ?", from, to).
+		Where("published_notified_at IS NULL OR published_notified_at < posted_at").
+		Where("unpublish_at IS NULL OR unpublish_at > ?", to).
+		Where("status <> ?", models.StatusArchived).
+		Order("posted_at asc").Find(&posts).Error
+	return posts, mapError(err)
+}
+
+// Itens publicados cujo UnpublishAt caiu no intervalo (from, to]; arquivados ficam de fora
+func (r *postRepository) FindUnpublishedBetween(from, to time.Time) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Where("unpublish_at > ? AND unpublish_at <= ?", from, to).
+		Where("unpublished_notified_at IS NULL OR unpublished_notified_at < unpublish_at").
+		Where("posted_at IS NOT NULL AND posted_at < unpublish_at").
+		Where("status <> ?", models.StatusArchived).
+		Order("unpublish_at asc").Find(&posts).Error
+	return posts, mapError(err)
+}
+
+// Registra que o evento do PostedAt/UnpublishAt atual já saiu. Não mexe em
+// updated_at: é controle interno, não edição do conteúdo.
+func (r *postRepository) MarkPublishNotified(id uint, at time.Time) error {
+	return affectedOrNotFound(r.db.Model(&models.Post{}).Where("id = ?", id).UpdateColumn("published_notified_at", at))
+}
 
-	return posts, total, err
+func (r *postRepository) MarkUnpublishNotified(id uint, at time.Time) error {
+	return affectedOrNotFound(r.db.Model(&models.Post{}).Where("id = ?", id).UpdateColumn("unpublished_notified_at", at))
 }
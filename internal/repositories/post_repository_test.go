@@ -3,6 +3,9 @@ package repositories_test
 import (
 	"cms-headless/internal/models"
 	"cms-headless/internal/repositories"
+	"cms-headless/internal/utils"
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -55,7 +58,7 @@ func TestPostRepository(t *testing.T) {
 
 		// 1. Não deve achar como publicado
 		found, err := repo.FindBySlug(slugRascunho, true)
-		assert.Error(t, err) // gorm.ErrRecordNotFound
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
 		assert.Nil(t, found)
 
 		// 2. Deve achar na busca administrativa (onlyPosted = false)
@@ -145,7 +148,7 @@ func TestPostRepository(t *testing.T) {
 		// FindByID não deve encontrar
 		found, err := repo.FindByID(p.ID)
 		assert.Error(t, err)
-		assert.EqualError(t, err, "record not found")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
 		assert.Nil(t, found)
 
 		// Verificar se continua no banco via Unscoped (Prova do Soft Delete)
@@ -175,13 +178,13 @@ func TestPostRepository_Search(t *testing.T) {
 	db.Create(&p2)
 
 	t.Run("Deve filtrar por Categoria e Tag simultaneamente", func(t *testing.T) {
-		// Slugs únicos são obrigatórios
+		// Slugs únicos são obrigatórios; via repositório para alimentar o índice de busca
 		p1 := models.Post{Title: "Mastering Go", Slug: "mastering-go", Tags: []models.Tag{t1}, Categories: []models.Category{c1}, PostedAt: &now}
 		p2 := models.Post{Title: "Learning JS", Slug: "learning-js", Tags: []models.Tag{t2}, PostedAt: &now}
-		db.Create(&p1)
-		db.Create(&p2)
+		repo.Create(&p1)
+		repo.Create(&p2)
 
-		res, total, err := repo.Search(1, 10, c1.ID, t1.ID, "", true)
+		res, total, err := repo.Search(1, 10, repositories.ContentFilter{Categories: []uint{c1.ID}, Tags: []uint{t1.ID}, OnlyPosted: true})
 
 		assert.NoError(t, err)
 		assert.Equal(t, int64(1), total)
@@ -192,7 +195,7 @@ func TestPostRepository_Search(t *testing.T) {
 	})
 
 	t.Run("Deve filtrar por texto no título", func(t *testing.T) {
-		res, total, err := repo.Search(1, 10, 0, 0, "Learning", true)
+		res, total, err := repo.Search(1, 10, repositories.ContentFilter{Query: "Learning", OnlyPosted: true})
 
 		assert.NoError(t, err)
 		assert.Equal(t, int64(1), total)
@@ -200,7 +203,7 @@ func TestPostRepository_Search(t *testing.T) {
 	})
 
 	t.Run("Não deve retornar nada para busca sem resultados", func(t *testing.T) {
-		res, total, err := repo.Search(1, 10, 999, 0, "", true)
+		res, total, err := repo.Search(1, 10, repositories.ContentFilter{Categories: []uint{999}, OnlyPosted: true})
 
 		assert.NoError(t, err)
 		assert.Equal(t, int64(0), total)
@@ -208,6 +211,170 @@ func TestPostRepository_Search(t *testing.T) {
 	})
 }
 
+func TestPostRepository_ContentFilter(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	jan := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	mar := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	golang := models.Tag{Title: "Go"}
+	postgres := models.Tag{Title: "Postgres"}
+	serie := models.Tag{Title: "Série rascunho"}
+	tutoriais := models.Category{Title: "Tutoriais"}
+	palestras := models.Category{Title: "Palestras"}
+	db.Create(&golang)
+	db.Create(&postgres)
+	db.Create(&serie)
+	db.Create(&tutoriais)
+	db.Create(&palestras)
+
+	repo.Create(&models.Post{Title: "Go com Postgres", Slug: "go-pg", PostedAt: &jan, Tags: []models.Tag{golang, postgres}, Categories: []models.Category{tutoriais}})
+	repo.Create(&models.Post{Title: "Só Go", Slug: "so-go", PostedAt: &mar, Tags: []models.Tag{golang}, Categories: []models.Category{palestras}})
+	repo.Create(&models.Post{Title: "Go em série", Slug: "serie", PostedAt: &jun, Tags: []models.Tag{golang, postgres, serie}})
+
+	slugs := func(posts []models.Post) []string {
+		var s []string
+		for _, p := range posts {
+			s = append(s, p.Slug)
+		}
+		return s
+	}
+
+	t.Run("Deve exigir todas as tags (AND)", func(t *testing.T) {
+		res, total, err := repo.Search(1, 10, repositories.ContentFilter{Tags: []uint{golang.ID, postgres.ID, golang.ID}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total, "ids repetidos não atrapalham")
+		assert.Equal(t, []string{"serie", "go-pg"}, slugs(res))
+	})
+
+	t.Run("Deve aceitar qualquer uma das categorias (OR)", func(t *testing.T) {
+		res, total, _ := repo.Search(1, 10, repositories.ContentFilter{AnyCategories: []uint{tutoriais.ID, palestras.ID}})
+
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []string{"so-go", "go-pg"}, slugs(res))
+	})
+
+	t.Run("Deve excluir itens com a tag (NOT)", func(t *testing.T) {
+		res, total, _ := repo.Search(1, 10, repositories.ContentFilter{Tags: []uint{postgres.ID}, ExcludeTags: []uint{serie.ID}})
+
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []string{"go-pg"}, slugs(res))
+	})
+
+	t.Run("Deve filtrar pelo intervalo de PostedAt", func(t *testing.T) {
+		from := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		to := jun
+
+		res, total, _ := repo.Search(1, 10, repositories.ContentFilter{PostedFrom: &from, PostedTo: &to})
+
+		assert.Equal(t, int64(1), total, "limite superior exclusivo")
+		assert.Equal(t, []string{"so-go"}, slugs(res))
+	})
+
+	t.Run("Facetas respeitam o filtro combinado", func(t *testing.T) {
+		facets, err := repo.SearchFacets(repositories.ContentFilter{Tags: []uint{golang.ID}, ExcludeTags: []uint{postgres.ID}})
+
+		assert.NoError(t, err)
+		assert.Len(t, facets.Tags, 1)
+		if assert.Len(t, facets.Categories, 1) {
+			assert.Equal(t, palestras.ID, facets.Categories[0].ID)
+		}
+	})
+}
+
+func TestPostRepository_FindPage(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Dois pares com o mesmo PostedAt para exercitar o desempate por id, e um rascunho sem data
+	for i, day := range []int{1, 2, 2, 3, 3} {
+		at := base.AddDate(0, 0, day)
+		repo.Create(&models.Post{Title: fmt.Sprintf("Post %d", i), Slug: fmt.Sprintf("post-%d", i), PostedAt: &at})
+	}
+	repo.Create(&models.Post{Title: "Sem data", Slug: "sem-data"})
+
+	collect := func(filter repositories.ContentFilter, limit int) ([]string, int) {
+		var slugs []string
+		pages := 0
+		q := utils.CursorQuery{Limit: limit}
+		for {
+			page, err := repo.FindPage(q, filter)
+			assert.NoError(t, err)
+			pages++
+			for _, p := range page.Items {
+				slugs = append(slugs, p.Slug)
+			}
+			if page.Next == nil || pages > 10 {
+				return slugs, pages
+			}
+			q.After = page.Next
+		}
+	}
+
+	t.Run("Deve percorrer tudo sem repetir nem pular, rascunhos no fim", func(t *testing.T) {
+		slugs, pages := collect(repositories.ContentFilter{}, 2)
+
+		assert.Equal(t, []string{"post-4", "post-3", "post-2", "post-1", "post-0", "sem-data"}, slugs)
+		assert.Equal(t, 3, pages)
+	})
+
+	t.Run("Deve respeitar OnlyPosted e os demais filtros", func(t *testing.T) {
+		slugs, _ := collect(repositories.ContentFilter{OnlyPosted: true}, 4)
+		assert.Equal(t, []string{"post-4", "post-3", "post-2", "post-1", "post-0"}, slugs)
+	})
+
+	t.Run("Publicar no meio da rolagem não desloca as páginas seguintes", func(t *testing.T) {
+		first, err := repo.FindPage(utils.CursorQuery{Limit: 2}, repositories.ContentFilter{OnlyPosted: true})
+		assert.NoError(t, err)
+
+		novo := base.AddDate(0, 0, 10)
+		repo.Create(&models.Post{Title: "Novo", Slug: "novo", PostedAt: &novo})
+
+		next, _ := repo.FindPage(utils.CursorQuery{Limit: 2, After: first.Next}, repositories.ContentFilter{OnlyPosted: true})
+		if assert.Len(t, next.Items, 2) {
+			assert.Equal(t, "post-2", next.Items[0].Slug)
+		}
+	})
+
+	t.Run("Total só quando pedido", func(t *testing.T) {
+		page, _ := repo.FindPage(utils.CursorQuery{Limit: 2}, repositories.ContentFilter{})
+		assert.Nil(t, page.Total)
+
+		page, _ = repo.FindPage(utils.CursorQuery{Limit: 2, WithTotal: true}, repositories.ContentFilter{})
+		if assert.NotNil(t, page.Total) {
+			assert.Equal(t, int64(7), *page.Total)
+		}
+	})
+}
+
+func TestPostRepository_QueryTimeout(t *testing.T) {
+	db := SetupTestDB()
+	// Registrado como em utils.InitDB
+	assert.NoError(t, db.Use(utils.QueryTimeout{Timeout: 2 * time.Second}))
+	repo := repositories.NewPostRepository(db)
+	past := time.Now().UTC().Add(-time.Hour)
+	repo.Create(&models.Post{Title: "Publicado", Slug: "publicado", PostedAt: &past})
+
+	t.Run("Deve contar e listar na mesma cadeia sem herdar o prazo já cancelado", func(t *testing.T) {
+		res, total, err := repo.FindAll(1, 10, false, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("Deve manter o cancelamento vindo do chamador", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := repo.WithContext(ctx).FindAll(1, 10, false, "")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
 func TestPostRepository_FindAll(t *testing.T) {
 	db := SetupTestDB()
 	repo := repositories.NewPostRepository(db)
@@ -235,7 +402,7 @@ func TestPostRepository_FindAll(t *testing.T) {
 	}
 
 	t.Run("Deve listar apenas postados e respeitar ordem decrescente de data", func(t *testing.T) {
-		res, total, err := repo.FindAll(1, 10, true)
+		res, total, err := repo.FindAll(1, 10, true, "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, int64(2), total) // Antigo e Recente
@@ -254,9 +421,376 @@ func TestPostRepository_FindAll(t *testing.T) {
 		// Limpar posts postados para este sub-teste
 		db.Where("posted_at IS NOT NULL").Delete(&models.Post{})
 
-		res, total, err := repo.FindAll(1, 10, true)
+		res, total, err := repo.FindAll(1, 10, true, "")
 		assert.NoError(t, err)
 		assert.Equal(t, int64(0), total)
 		assert.Empty(t, res)
 	})
 }
+
+func TestPostRepository_Status(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	past := time.Now().UTC().Add(-time.Hour)
+
+	db.Create(&models.Post{Title: "Rascunho", Slug: "rascunho", Body: "go"})
+	db.Create(&models.Post{Title: "Em Revisão", Slug: "em-revisao", Body: "go", Status: models.StatusInReview})
+	arquivado := models.Post{Title: "Arquivado", Slug: "arquivado", Body: "go", PostedAt: &past, Status: models.StatusPublished}
+	db.Create(&arquivado)
+
+	t.Run("Deve filtrar a listagem e a busca pelo estado", func(t *testing.T) {
+		res, total, err := repo.FindAll(1, 10, false, models.StatusInReview)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Em Revisão", res[0].Title)
+
+		res, total, err = repo.Search(1, 10, repositories.ContentFilter{Status: models.StatusDraft})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Rascunho", res[0].Title)
+	})
+
+	t.Run("Conteúdo arquivado sai da API pública", func(t *testing.T) {
+		assert.NoError(t, repo.SetStatus(arquivado.ID, models.StatusArchived))
+
+		_, err := repo.FindBySlug("arquivado", true)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+		_, total, _ := repo.FindAll(1, 10, true, "")
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("Deve retornar ErrNotFound ao mudar estado de post inexistente", func(t *testing.T) {
+		assert.ErrorIs(t, repo.SetStatus(999, models.StatusApproved), repositories.ErrNotFound)
+	})
+}
+
+func TestPostRepository_UnpublishAt(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	now := time.Now().UTC()
+	past := now.Add(-time.Hour)
+	soon := now.Add(time.Hour)
+	ended := now.Add(-30 * time.Minute)
+
+	expired := models.Post{Title: "Retirado", Slug: "retirado", PostedAt: &past}
+	expiring := models.Post{Title: "Expirando", Slug: "expirando", PostedAt: &past}
+	db.Create(&expired)
+	db.Create(&expiring)
+
+	t.Run("Deve esconder do público posts com retirada já vencida", func(t *testing.T) {
+		assert.NoError(t, repo.SetUnpublishAt(expired.ID, &ended))
+		assert.NoError(t, repo.SetUnpublishAt(expiring.ID, &soon))
+
+		res, total, err := repo.FindAll(1, 10, true, "")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "expirando", res[0].Slug)
+		}
+
+		_, err = repo.FindBySlug("retirado", true)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+		_, err = repo.FindBySlug("retirado", false)
+		assert.NoError(t, err, "a busca administrativa continua enxergando o post")
+	})
+
+	t.Run("Deve listar retiradas dentro do intervalo até serem anunciadas", func(t *testing.T) {
+		res, err := repo.FindUnpublishedBetween(now.Add(-2*time.Hour), now)
+		assert.NoError(t, err)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, expired.ID, res[0].ID)
+		}
+
+		assert.NoError(t, repo.MarkUnpublishNotified(expired.ID, now))
+		res, err = repo.FindUnpublishedBetween(now.Add(-2*time.Hour), now)
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Deve retornar ErrNotFound ao agendar retirada de post inexistente", func(t *testing.T) {
+		assert.ErrorIs(t, repo.SetUnpublishAt(9999, &soon), repositories.ErrNotFound)
+	})
+}
+
+func TestPostRepository_WithContext(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	db.Create(&models.Post{Title: "Contexto", Slug: "contexto"})
+
+	t.Run("Deve buscar normalmente com contexto ativo", func(t *testing.T) {
+		post, err := repo.WithContext(context.Background()).FindBySlug("contexto", false)
+		assert.NoError(t, err)
+		assert.Equal(t, "Contexto", post.Title)
+	})
+
+	t.Run("Deve abortar a busca quando o contexto for cancelado", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		res, total, err := repo.WithContext(ctx).Search(1, 10, repositories.ContentFilter{Query: "Contexto"})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, res)
+		assert.Zero(t, total)
+	})
+
+	t.Run("Não deve alterar o repositório original", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		repo.WithContext(ctx)
+
+		_, err := repo.FindBySlug("contexto", false)
+		assert.NoError(t, err)
+	})
+}
+
+func TestPostRepository_Errors(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	db.Create(&models.Post{Title: "Original", Slug: "original"})
+
+	t.Run("Deve retornar ErrDuplicateSlug para slug repetido", func(t *testing.T) {
+		err := repo.Create(&models.Post{Title: "Outro título", Slug: "original"})
+		assert.ErrorIs(t, err, repositories.ErrDuplicateSlug)
+	})
+
+	t.Run("Deve retornar ErrDuplicateTitle para título repetido", func(t *testing.T) {
+		err := repo.Create(&models.Post{Title: "Original", Slug: "outro-slug"})
+		assert.ErrorIs(t, err, repositories.ErrDuplicateTitle)
+	})
+
+	t.Run("Deve retornar ErrNotFound ao alterar ou remover ID inexistente", func(t *testing.T) {
+		assert.ErrorIs(t, repo.SetPostedAt(999, nil), repositories.ErrNotFound)
+		assert.ErrorIs(t, repo.Delete(999), repositories.ErrNotFound)
+	})
+
+	t.Run("Deve propagar falhas do Count em vez de ignorá-las", func(t *testing.T) {
+		db.Migrator().DropTable(&models.Post{})
+		defer db.AutoMigrate(&models.Post{})
+
+		res, total, err := repo.FindAll(1, 10, false, "")
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		assert.Zero(t, total)
+	})
+}
+
+func TestPostRepository_Trash(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+
+	tag := models.Tag{Title: "Go"}
+	db.Create(&tag)
+	p := &models.Post{Title: "Lixo", Slug: "lixo", Tags: []models.Tag{tag}}
+	db.Create(p)
+	db.Create(&models.Post{Title: "Ativo", Slug: "ativo"})
+
+	t.Run("Deve listar apenas itens na lixeira", func(t *testing.T) {
+		repo.Delete(p.ID)
+
+		res, total, err := repo.FindTrashed(1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, "Lixo", res[0].Title)
+			assert.True(t, res[0].DeletedAt.Valid)
+			assert.NotEmpty(t, res[0].Tags)
+		}
+	})
+
+	t.Run("Deve restaurar item da lixeira", func(t *testing.T) {
+		err := repo.Restore(p.ID)
+		assert.NoError(t, err)
+
+		found, err := repo.FindByID(p.ID)
+		assert.NoError(t, err)
+		assert.False(t, found.DeletedAt.Valid)
+	})
+
+	t.Run("Não deve restaurar nem expurgar item fora da lixeira", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Restore(p.ID), repositories.ErrNotFound)
+		assert.ErrorIs(t, repo.Purge(p.ID), repositories.ErrNotFound)
+	})
+
+	t.Run("Deve expurgar definitivamente com as tags associadas", func(t *testing.T) {
+		repo.Delete(p.ID)
+		err := repo.Purge(p.ID)
+		assert.NoError(t, err)
+
+		var count int64
+		db.Unscoped().Model(&models.Post{}).Where("id = ?", p.ID).Count(&count)
+		assert.Zero(t, count)
+		db.Table("post_tags").Where("post_id = ?", p.ID).Count(&count)
+		assert.Zero(t, count)
+
+		// A tag em si não é removida
+		db.Model(&models.Tag{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestPostRepository_ReuseDeletedSlug(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+
+	original := &models.Post{Title: "My Post", Slug: "my-post"}
+	assert.NoError(t, repo.Create(original))
+
+	t.Run("Deve permitir recriar título e slug de um item na lixeira", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(original.ID))
+
+		again := &models.Post{Title: "My Post", Slug: "my-post"}
+		assert.NoError(t, repo.Create(again))
+	})
+
+	t.Run("Deve continuar barrando duplicatas entre itens ativos", func(t *testing.T) {
+		err := repo.Create(&models.Post{Title: "Outro", Slug: "my-post"})
+		assert.ErrorIs(t, err, repositories.ErrDuplicateSlug)
+	})
+
+	t.Run("Deve falhar com conflito ao restaurar item cujo slug foi reutilizado", func(t *testing.T) {
+		err := repo.Restore(original.ID)
+
+		assert.ErrorIs(t, err, repositories.ErrRestoreConflict)
+		assert.ErrorIs(t, err, repositories.ErrDuplicateSlug)
+
+		// O item continua na lixeira, sem alterações
+		var check models.Post
+		db.Unscoped().First(&check, original.ID)
+		assert.True(t, check.DeletedAt.Valid)
+	})
+
+	t.Run("Deve detectar conflito de título mesmo com slug livre", func(t *testing.T) {
+		db.Unscoped().Model(&models.Post{}).Where("id = ?", original.ID).Update("slug", "my-post-antigo")
+
+		err := repo.Restore(original.ID)
+		assert.ErrorIs(t, err, repositories.ErrRestoreConflict)
+		assert.ErrorIs(t, err, repositories.ErrDuplicateTitle)
+	})
+}
+
+func TestPostRepository_SlugHistory(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	past := time.Now().UTC().Add(-time.Hour)
+
+	post := &models.Post{Title: "Original", Slug: "original", PostedAt: &past}
+	repo.Create(post)
+
+	t.Run("Deve registrar o slug antigo ao renomear", func(t *testing.T) {
+		post.Title, post.Slug = "Renomeado", "renomeado"
+		assert.NoError(t, repo.Update(post))
+
+		post.Title, post.Slug = "Final", "final"
+		assert.NoError(t, repo.Update(post))
+
+		var history []models.SlugHistory
+		db.Order("slug").Find(&history)
+		if assert.Len(t, history, 2) {
+			assert.Equal(t, "original", history[0].Slug)
+			assert.Equal(t, "renomeado", history[1].Slug)
+			assert.Equal(t, post.ID, history[0].ContentID)
+		}
+	})
+
+	t.Run("Slugs antigos devem resolver para o slug atual", func(t *testing.T) {
+		for _, old := range []string{"original", "renomeado"} {
+			found, movedTo, err := repo.FindBySlugOrRedirect(old, true)
+			assert.NoError(t, err)
+			assert.Nil(t, found)
+			assert.Equal(t, "final", movedTo)
+		}
+	})
+
+	t.Run("Slug atual deve retornar o conteúdo sem redirecionamento", func(t *testing.T) {
+		found, movedTo, err := repo.FindBySlugOrRedirect("final", true)
+		assert.NoError(t, err)
+		assert.Empty(t, movedTo)
+		assert.Equal(t, "Final", found.Title)
+	})
+
+	t.Run("Novo item que reutiliza um slug antigo tem prioridade", func(t *testing.T) {
+		repo.Create(&models.Post{Title: "Novo Original", Slug: "original", PostedAt: &past})
+
+		found, movedTo, err := repo.FindBySlugOrRedirect("original", true)
+		assert.NoError(t, err)
+		assert.Empty(t, movedTo)
+		assert.Equal(t, "Novo Original", found.Title)
+	})
+
+	t.Run("Não deve revelar redirecionamento para item não publicado", func(t *testing.T) {
+		repo.SetPostedAt(post.ID, nil)
+
+		_, _, err := repo.FindBySlugOrRedirect("renomeado", true)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+		_, movedTo, err := repo.FindBySlugOrRedirect("renomeado", false)
+		assert.NoError(t, err)
+		assert.Equal(t, "final", movedTo)
+	})
+
+	t.Run("Slug desconhecido deve retornar ErrNotFound", func(t *testing.T) {
+		_, _, err := repo.FindBySlugOrRedirect("nunca-existiu", false)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestPostRepository_FullTextSearch(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewPostRepository(db)
+	tags := repositories.NewTagRepository(db)
+	now := time.Now().UTC()
+
+	tag := models.Tag{Title: "Kubernetes"}
+	db.Create(&tag)
+
+	noTitulo := models.Post{Title: "Concorrência em Go", Slug: "concorrencia-em-go", Body: "<p>canais</p>", PostedAt: &now}
+	noCorpo := models.Post{Title: "Diário de bordo", Slug: "diario", Body: "<p>Hoje estudei</p><p>concorrência</p>", PostedAt: &now}
+	comTag := models.Post{Title: "Deploy", Slug: "deploy", Body: "<p>cluster</p>", Tags: []models.Tag{tag}, PostedAt: &now}
+	repo.Create(&noCorpo)
+	repo.Create(&noTitulo)
+	repo.Create(&comTag)
+
+	t.Run("Deve buscar no corpo sem HTML e ordenar por relevância", func(t *testing.T) {
+		res, total, err := repo.Search(1, 10, repositories.ContentFilter{Query: "concorrência", OnlyPosted: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		if assert.Len(t, res, 2) {
+			assert.Equal(t, "Concorrência em Go", res[0].Title, "título pesa mais que o corpo")
+			assert.Equal(t, "Diário de bordo", res[1].Title)
+		}
+
+		_, total, _ = repo.Search(1, 10, repositories.ContentFilter{Query: "estudei concorrência", OnlyPosted: true})
+		assert.Equal(t, int64(1), total, "todos os termos precisam aparecer")
+	})
+
+	t.Run("Deve buscar pelo nome da tag e acompanhar renomeações", func(t *testing.T) {
+		res, total, _ := repo.Search(1, 10, repositories.ContentFilter{Query: "kubernetes", OnlyPosted: true})
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Deploy", res[0].Title)
+
+		assert.NoError(t, tags.UpdateName(tag.ID, "Nomad"))
+		_, total, _ = repo.Search(1, 10, repositories.ContentFilter{Query: "kubernetes", OnlyPosted: true})
+		assert.Equal(t, int64(0), total)
+		_, total, _ = repo.Search(1, 10, repositories.ContentFilter{Query: "nomad", OnlyPosted: true})
+		assert.Equal(t, int64(1), total)
+	})
+
+	t.Run("Deve reindexar no update e remover no expurgo", func(t *testing.T) {
+		noCorpo.Body = "<p>sobre testes</p>"
+		assert.NoError(t, repo.Update(&noCorpo))
+		_, total, _ := repo.Search(1, 10, repositories.ContentFilter{Query: "estudei", OnlyPosted: true})
+		assert.Equal(t, int64(0), total)
+
+		repo.Delete(noCorpo.ID)
+		_, total, _ = repo.Search(1, 10, repositories.ContentFilter{Query: "testes", OnlyPosted: true})
+		assert.Equal(t, int64(0), total, "itens na lixeira não aparecem")
+
+		assert.NoError(t, repo.Purge(noCorpo.ID))
+		var docs int64
+		db.Model(&models.SearchDocument{}).Where("content_id = ?", noCorpo.ID).Count(&docs)
+		assert.Equal(t, int64(0), docs)
+	})
+}
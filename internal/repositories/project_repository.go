@@ -2,22 +2,42 @@ package repositories
 
 import (
 	"cms-headless/internal/models"
+	"cms-headless/internal/search"
 	"cms-headless/internal/utils"
+	"context"
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type ProjectRepository interface {
-	FindAll(page, pageSize int, onlyPosted bool) ([]models.Project, int64, error)
+	WithContext(ctx context.Context) ProjectRepository
+	FindAll(page, pageSize int, onlyPosted bool, status string) ([]models.Project, int64, error)
+	FindPage(q utils.CursorQuery, filter ContentFilter) (CursorPage[models.Project], error)
 	FindBySlug(slug string, onlyPosted bool) (*models.Project, error)
+	FindBySlugOrRedirect(slug string, onlyPosted bool) (*models.Project, string, error)
 	FindByID(id uint) (*models.Project, error)
+	SlugExists(slug string, excludeID uint) (bool, error)
 	Create(project *models.Project) error
 	Update(project *models.Project) error
 	Delete(id uint) error
 	SetPostedAt(id uint, t *time.Time) error
+	SetStatus(id uint, status string) error
+	SetUnpublishAt(id uint, t *time.Time) error
+	FindPublishedBetween(from, to time.Time) ([]models.Project, error)
+	FindUnpublishedBetween(from, to time.Time) ([]models.Project, error)
+	MarkPublishNotified(id uint, at time.Time) error
+	MarkUnpublishNotified(id uint, at time.Time) error
 	ReplaceTags(project *models.Project, tags []models.Tag) error
 	ReplaceCategories(project *models.Project, categories []models.Category) error
+	FindTrashed(page, pageSize int) ([]models.Project, int64, error)
+	Restore(id uint) error
+	Purge(id uint) error
+	PurgeDeletedBefore(cutoff time.Time) (int64, error)
+	Search(page, pageSize int, filter ContentFilter) ([]models.Project, int64, error)
+	SearchHits(page, pageSize int, filter ContentFilter) ([]SearchHit[models.Project], int64, error)
+	SearchFacets(filter ContentFilter) (Facets, error)
 }
 
 type projectRepository struct {
@@ -28,17 +48,24 @@ func NewProjectRepository(db *gorm.DB) ProjectRepository {
 	return &projectRepository{db: db}
 }
 
-func (r *projectRepository) FindAll(page, pageSize int, onlyPosted bool) ([]models.Project, int64, error) {
+// Retorna uma cópia do repositório cujas consultas respeitam cancelamento e prazo do ctx
+func (r *projectRepository) WithContext(ctx context.Context) ProjectRepository {
+	return &projectRepository{db: r.db.WithContext(ctx)}
+}
+
+// status vazio não filtra pelo estado editorial
+func (r *projectRepository) FindAll(page, pageSize int, onlyPosted bool, status string) ([]models.Project, int64, error) {
 	var projects []models.Project
 	var total int64
 
-	query := r.db.Model(&models.Project{})
+	query := r.db.Model(&models.Project{}).Scopes(withStatus("projects", status))
 	if onlyPosted {
-		now := time.Now().UTC()
-		query = query.Where("posted_at IS NOT NULL AND posted_at <= ?", now)
+		query = query.Scopes(visible("projects"))
 	}
 
-	query.Count(&total)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
 
 	// Usando o utils renomeado
 	err := query.Scopes(utils.PaginateRepository(page, pageSize)).
@@ -47,7 +74,16 @@ func (r *projectRepository) FindAll(page, pageSize int, onlyPosted bool) ([]mode
 		Order("created_at desc").
 		Find(&projects).Error
 
-	return projects, total, err
+	return projects, total, mapError(err)
+}
+
+// Listagem por cursor sobre (posted_at, id), com os mesmos filtros de Search;
+// itens sem PostedAt (rascunhos) vêm no fim
+func (r *projectRepository) FindPage(q utils.CursorQuery, filter ContentFilter) (CursorPage[models.Project], error) {
+	query := r.searchQuery(filter).Preload("Tags").Preload("Categories")
+	return findPage(query, q, "projects.posted_at", "projects.id", func(p models.Project) (*time.Time, uint) {
+		return p.PostedAt, p.ID
+	})
 }
 
 func (r *projectRepository) FindBySlug(slug string, onlyPosted bool) (*models.Project, error) {
@@ -55,15 +91,13 @@ func (r *projectRepository) FindBySlug(slug string, onlyPosted bool) (*models.Pr
 	query := r.db.Where("slug = ?", slug)
 
 	if onlyPosted {
-		// Truncamos para segundos para garantir compatibilidade total com SQLite/Postgres
-		now := time.Now().UTC()
-		query = query.Where("posted_at IS NOT NULL AND posted_at <= ?", now)
+		query = query.Scopes(visible("projects"))
 	}
 
 	err := query.Preload("Tags").Preload("Categories").First(&project).Error
 
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &project, nil
@@ -73,36 +107,287 @@ func (r *projectRepository) FindByID(id uint) (*models.Project, error) {
 	var project models.Project
 	err := r.db.Preload("Tags").Preload("Categories").First(&project, id).Error
 	if err != nil {
-		return nil, err
+		return nil, mapError(err)
 	}
 
 	return &project, nil
 }
 
 func (r *projectRepository) Update(project *models.Project) error {
-	return r.db.Save(project).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Slug alterado: o antigo vai para o histórico para virar redirecionamento
+		var current models.Project
+		err := tx.Select("id", "slug").First(&current, project.ID).Error
+		if err == nil && current.Slug != project.Slug {
+			if err := recordSlugHistory(tx, contentTypeProject, project.ID, current.Slug); err != nil {
+				return err
+			}
+		}
+		if err := releaseSlugHistory(tx, contentTypeProject, project.Slug); err != nil {
+			return err
+		}
+		if err := tx.Save(project).Error; err != nil {
+			return mapError(err)
+		}
+		return indexProject(tx, project.ID)
+	})
 }
 
 func (r *projectRepository) ReplaceTags(project *models.Project, tags []models.Tag) error {
-	return r.db.Model(project).Association("Tags").Replace(tags)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(project).Association("Tags").Replace(tags); err != nil {
+			return mapError(err)
+		}
+		return indexProject(tx, project.ID)
+	})
 }
 
 func (r *projectRepository) ReplaceCategories(project *models.Project, categories []models.Category) error {
-	return r.db.Model(project).Association("Categories").Replace(categories)
+	return mapError(r.db.Model(project).Association("Categories").Replace(categories))
 }
 
 func (r *projectRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Project{}, id).Error
+	return affectedOrNotFound(r.db.Delete(&models.Project{}, id))
+}
+
+func (r *projectRepository) SetStatus(id uint, status string) error {
+	return affectedOrNotFound(r.db.Model(&models.Project{}).Where("id = ?", id).Update("status", status))
 }
 
 func (r *projectRepository) SetPostedAt(id uint, t *time.Time) error {
 	// Se t for nil, o GORM define como NULL no banco (remove a postagem)
-	return r.db.Model(&models.Project{}).Where("id = ?", id).Update("posted_at", t).Error
+	return affectedOrNotFound(r.db.Model(&models.Project{}).Where("id = ?", id).Update("posted_at", t))
 }
 
 func (r *projectRepository) Create(project *models.Project) error {
 	// O GORM por padrão tentará criar associações se elas não existirem.
 	// Se o Service já garantiu que IDs de Tags/Categorias são válidos,
 	// o Save/Create fará o insert no projects e nas tabelas de junção.
-	return r.db.Create(project).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := releaseSlugHistory(tx, contentTypeProject, project.Slug); err != nil {
+			return err
+		}
+		if err := tx.Create(project).Error; err != nil {
+			return mapError(err)
+		}
+		return indexProject(tx, project.ID)
+	})
+}
+
+func (r *projectRepository) Search(page, pageSize int, filter ContentFilter) ([]models.Project, int64, error) {
+	hits, total, err := r.SearchHits(page, pageSize, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	projects := make([]models.Project, 0, len(hits))
+	for _, h := range hits {
+		projects = append(projects, h.Item)
+	}
+	return projects, total, nil
+}
+
+// Como Search, mas com a relevância de cada item (com texto, os mais relevantes vêm primeiro)
+func (r *projectRepository) SearchHits(page, pageSize int, filter ContentFilter) ([]SearchHit[models.Project], int64, error) {
+	var total int64
+	// Contagem distinta para não contar o mesmo projeto múltiplas vezes devido aos joins
+	if err := r.searchQuery(filter).Distinct("projects.id").Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	// Primeiro só IDs e relevância da página; os projetos completos vêm numa segunda consulta
+	var ranked []rankedID
+	query := r.searchQuery(filter)
+	if filter.Query != "" {
+		query = query.Select("projects.id, ? AS search_rank", search.Rank(r.db, filter.Query)).Order("search_rank DESC")
+	} else {
+		query = query.Select("projects.id, 0 AS search_rank")
+	}
+	err := query.Scopes(utils.PaginateRepository(page, pageSize)).
+		Order("projects.posted_at desc").Order("projects.id desc").
+		Scan(&ranked).Error
+	if err != nil {
+		return nil, 0, mapError(err)
+	}
+
+	var projects []models.Project
+	if err := r.db.Preload("Tags").Preload("Categories").Where("id IN ?", rankedIDs(ranked)).Find(&projects).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+	return toHits(ranked, projects, func(p models.Project) uint { return p.ID }), total, nil
+}
+
+// Contagem por tag e por categoria dentro do mesmo conjunto filtrado da busca
+func (r *projectRepository) SearchFacets(filter ContentFilter) (Facets, error) {
+	ids := r.searchQuery(filter).Select("projects.id")
+	return searchFacets(r.db, "project_tags", "project_categories", "project_id", ids)
+}
+
+func (r *projectRepository) searchQuery(filter ContentFilter) *gorm.DB {
+	query := r.db.Model(&models.Project{}).Scopes(filter.scope(projectTaxonomy))
+	if filter.Query != "" {
+		query = query.Scopes(search.Filter("projects", contentTypeProject, filter.Query))
+	}
+	if filter.OnlyPosted {
+		query = query.Scopes(visible("projects"))
+	}
+	return query.Scopes(withStatus("projects", filter.Status))
+}
+
+// Lixeira: itens com soft delete, do mais recente para o mais antigo
+func (r *projectRepository) FindTrashed(page, pageSize int) ([]models.Project, int64, error) {
+	var projects []models.Project
+	var total int64
+
+	query := r.db.Unscoped().Model(&models.Project{}).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+	err := query.Scopes(utils.PaginateRepository(page, pageSize)).
+		Preload("Tags").Preload("Categories").
+		Order("deleted_at desc").Find(&projects).Error
+
+	return projects, total, mapError(err)
+}
+
+// Restaura da lixeira; falha com ErrRestoreConflict se o título/slug foi reutilizado nesse meio tempo
+func (r *projectRepository) Restore(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var trashed models.Project
+		err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&trashed).Error
+		if err != nil {
+			return mapError(err)
+		}
+		if err := checkRestoreConflict(tx, &models.Project{}, trashed.Title, trashed.Slug); err != nil {
+			return err
+		}
+		return mapError(tx.Unscoped().Model(&models.Project{}).Where("id = ?", id).Update("deleted_at", nil).Error)
+	})
+}
+
+// Remove definitivamente um item que já está na lixeira (junto com as linhas de junção)
+func (r *projectRepository) Purge(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Unscoped().Model(&models.Project{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return mapError(err)
+		}
+		if len(ids) == 0 {
+			return ErrNotFound
+		}
+		return purgeProjects(tx, ids)
+	})
+}
+
+// Usado pelo job de retenção: expurga tudo que está na lixeira desde antes de cutoff
+func (r *projectRepository) PurgeDeletedBefore(cutoff time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Unscoped().Model(&models.Project{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return mapError(err)
+		}
+		purged = int64(len(ids))
+		return purgeProjects(tx, ids)
+	})
+	return purged, err
+}
+
+func purgeProjects(tx *gorm.DB, ids []uint) error {
+	if err := tx.Exec("DELETE FROM project_tags WHERE project_id IN ?", ids).Error; err != nil {
+		return mapError(err)
+	}
+	if err := tx.Exec("DELETE FROM project_categories WHERE project_id IN ?", ids).Error; err != nil {
+		return mapError(err)
+	}
+	if err := purgeSlugHistory(tx, contentTypeProject, ids); err != nil {
+		return err
+	}
+	if err := purgeRevisions(tx, contentTypeProject, ids); err != nil {
+		return err
+	}
+	if err := purgeDrafts(tx, contentTypeProject, ids); err != nil {
+		return err
+	}
+	if err := purgeStateChanges(tx, contentTypeProject, ids); err != nil {
+		return err
+	}
+	if err := purgeSearchDocuments(tx, contentTypeProject, ids); err != nil {
+		return err
+	}
+	return mapError(tx.Unscoped().Delete(&models.Project{}, ids).Error)
+}
+
+// Verifica se o slug já pertence a outro item ativo (excludeID ignora o próprio item no update)
+func (r *projectRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Project{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, mapError(err)
+}
+
+// Busca pelo slug atual; se o slug pertenceu a um item renomeado, devolve o slug novo
+// (segundo retorno) para o front responder com 301.
+func (r *projectRepository) FindBySlugOrRedirect(slug string, onlyPosted bool) (*models.Project, string, error) {
+	project, err := r.FindBySlug(slug, onlyPosted)
+	if !errors.Is(err, ErrNotFound) {
+		return project, "", err
+	}
+
+	id, err := findSlugHistory(r.db, contentTypeProject, slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query := r.db.Model(&models.Project{}).Where("id = ?", id)
+	if onlyPosted {
+		query = query.Scopes(visible("projects"))
+	}
+	var current models.Project
+	if err := query.Select("id", "slug").First(&current).Error; err != nil {
+		return nil, "", mapError(err)
+	}
+	return nil, current.Slug, nil
+}
+
+func (r *projectRepository) SetUnpublishAt(id uint, t *time.Time) error {
+	return affectedOrNotFound(r.db.Model(&models.Project{}).Where("id = ?", id).Update("unpublish_at", t))
+}
+
+// Itens cujo PostedAt caiu no intervalo (from, to] e que ainda estavam no ar em "to"
+// (não arquivados). Só entram os que ainda não tiveram o evento emitido para o
+// PostedAt atual: publicações imediatas já o emitiram no serviço.
+func (r *projectRepository) FindPublishedBetween(from, to time.Time) ([]models.Project, error) {
+	var projects []models.Project
+	err := r.db.Where("posted_at > ? AND posted_at <= ?", from, to).
+		Where("published_notified_at IS NULL OR published_notified_at < posted_at").
+		Where("unpublish_at IS NULL OR unpublish_at > ?", to).
+		Where("status <> ?", models.StatusArchived).
+		Order("posted_at asc").Find(&projects).Error
+	return projects, mapError(err)
+}
+
+// Itens publicados cujo UnpublishAt caiu no intervalo (from, to]; arquivados ficam de fora
+func (r *projectRepository) FindUnpublishedBetween(from, to time.Time) ([]models.Project, error) {
+	var projects []models.Project
+	err := r.db.Where("unpublish_at > ? AND unpublish_at <= ?", from, to).
+		Where("unpublished_notified_at IS NULL OR unpublished_notified_at < unpublish_at").
+		Where("posted_at IS NOT NULL AND posted_at < unpublish_at").
+		Where("status <> ?", models.StatusArchived).
+		Order("unpublish_at asc").Find(&projects).Error
+	return projects, mapError(err)
+}
+
+// Registra que o evento do PostedAt/UnpublishAt atual já saiu. Não mexe em
+// updated_at: é controle interno, não edição do conteúdo.
+func (r *projectRepository) MarkPublishNotified(id uint, at time.Time) error {
+	return affectedOrNotFound(r.db.Model(&models.Project{}).Where("id = ?", id).UpdateColumn("published_notified_at", at))
+}
+
+func (r *projectRepository) MarkUnpublishNotified(id uint, at time.Time) error {
+	return affectedOrNotFound(r.db.Model(&models.Project{}).Where("id = ?", id).UpdateColumn("unpublished_notified_at", at))
 }
@@ -45,7 +45,7 @@ func TestProjectRepository(t *testing.T) {
 		project, err := repo.FindBySlug("slug-inexistente", false)
 
 		assert.Error(t, err)
-		assert.EqualError(t, err, "record not found")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
 		assert.Nil(t, project) // Aqui validamos se o ponteiro é realmente nulo
 	})
 
@@ -55,8 +55,7 @@ func TestProjectRepository(t *testing.T) {
 
 		project, err := repo.FindBySlug("rascunho", true)
 
-		assert.Error(t, err) // Deve dar RecordNotFound
-		assert.EqualError(t, err, "record not found")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
 		assert.Nil(t, project)
 	})
 
@@ -89,7 +88,7 @@ func TestProjectRepository(t *testing.T) {
 		// FindByID não deve encontrar
 		found, err := repo.FindByID(p.ID)
 		assert.Error(t, err)
-		assert.EqualError(t, err, "record not found")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
 		assert.Nil(t, found)
 
 		// Verificar se continua no banco via Unscoped (Prova do Soft Delete)
@@ -201,7 +200,7 @@ func TestProjectRepository_FindAll(t *testing.T) {
 
 	t.Run("Modo Público (onlyPosted=true): Deve ignorar rascunhos e datas futuras", func(t *testing.T) {
 		// Apenas 'Passado' e 'Agora' devem aparecer
-		res, total, err := repo.FindAll(1, 10, true)
+		res, total, err := repo.FindAll(1, 10, true, "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, int64(2), total, "Total deve ser 2 (passado e agora)")
@@ -215,7 +214,7 @@ func TestProjectRepository_FindAll(t *testing.T) {
 	})
 
 	t.Run("Modo Admin (onlyPosted=false): Deve listar absolutamente tudo", func(t *testing.T) {
-		res, total, err := repo.FindAll(1, 10, false)
+		res, total, err := repo.FindAll(1, 10, false, "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, int64(4), total, "Total deve ser 4 no modo admin")
@@ -224,10 +223,90 @@ func TestProjectRepository_FindAll(t *testing.T) {
 
 	t.Run("Paginação: Deve respeitar o limite e reportar o total correto", func(t *testing.T) {
 		// Pedindo página 1 com apenas 1 item (modo admin)
-		res, total, err := repo.FindAll(1, 1, false)
+		res, total, err := repo.FindAll(1, 1, false, "")
 
 		assert.NoError(t, err)
 		assert.Equal(t, int64(4), total, "O total continua sendo 4 independente do limite da página")
 		assert.Len(t, res, 1)
 	})
 }
+
+func TestProjectRepository_Search(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewProjectRepository(db)
+	now := time.Now().UTC()
+
+	golang := models.Tag{Title: "Go"}
+	web := models.Category{Title: "Web"}
+	db.Create(&golang)
+	db.Create(&web)
+
+	repo.Create(&models.Project{Title: "API de pagamentos", Slug: "api", Body: "<p>gateway em go</p>", PostedAt: &now, Tags: []models.Tag{golang}, Categories: []models.Category{web}})
+	repo.Create(&models.Project{Title: "Site pessoal", Slug: "site", Body: "<p>gateway estático</p>", PostedAt: &now, Categories: []models.Category{web}})
+	repo.Create(&models.Project{Title: "Gateway interno", Slug: "interno", Body: "rascunho"})
+
+	t.Run("Deve combinar texto, tag e categoria", func(t *testing.T) {
+		res, total, err := repo.Search(1, 10, repositories.ContentFilter{Query: "gateway", OnlyPosted: true})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, res, 2)
+
+		res, total, _ = repo.Search(1, 10, repositories.ContentFilter{Query: "gateway", Categories: []uint{web.ID}, Tags: []uint{golang.ID}, OnlyPosted: true})
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "api", res[0].Slug)
+		assert.NotEmpty(t, res[0].Tags, "tags carregadas")
+
+		_, total, _ = repo.Search(1, 10, repositories.ContentFilter{Query: "gateway", Status: models.StatusDraft})
+		assert.Equal(t, int64(3), total, "admin enxerga rascunhos")
+	})
+
+	t.Run("Deve contar facetas do resultado", func(t *testing.T) {
+		facets, err := repo.SearchFacets(repositories.ContentFilter{Query: "gateway", OnlyPosted: true})
+
+		assert.NoError(t, err)
+		if assert.Len(t, facets.Categories, 1) {
+			assert.Equal(t, repositories.FacetCount{ID: web.ID, Title: "Web", Count: 2}, facets.Categories[0])
+		}
+		assert.Len(t, facets.Tags, 1)
+	})
+}
+
+func TestContentSearchRepository(t *testing.T) {
+	db := SetupTestDB()
+	posts := repositories.NewPostRepository(db)
+	projects := repositories.NewProjectRepository(db)
+	contents := repositories.NewContentSearchRepository(db)
+	now := time.Now().UTC()
+
+	posts.Create(&models.Post{Title: "Observabilidade na prática", Slug: "obs", Body: "métricas", PostedAt: &now})
+	projects.Create(&models.Project{Title: "Painel", Slug: "painel", Body: "observabilidade de filas", PostedAt: &now})
+	posts.Create(&models.Post{Title: "Observabilidade (rascunho)", Slug: "rascunho", Body: "x"})
+	apagado := models.Project{Title: "Observabilidade antiga", Slug: "antiga", Body: "x", PostedAt: &now}
+	projects.Create(&apagado)
+	projects.Delete(apagado.ID)
+
+	t.Run("Deve misturar posts e projetos numa lista ranqueada", func(t *testing.T) {
+		hits, total, err := contents.Search(1, 10, "observabilidade", true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total, "sem rascunhos nem itens na lixeira")
+		if assert.Len(t, hits, 2) {
+			assert.Equal(t, "post", hits[0].ContentType, "título pesa mais que o corpo")
+			assert.Equal(t, "obs", hits[0].Post.Slug)
+			assert.Equal(t, "project", hits[1].ContentType)
+			assert.Equal(t, "painel", hits[1].Project.Slug)
+			assert.GreaterOrEqual(t, hits[0].Score, hits[1].Score)
+		}
+	})
+
+	t.Run("Deve paginar sobre a lista unificada", func(t *testing.T) {
+		hits, total, _ := contents.Search(2, 1, "observabilidade", true)
+		assert.Equal(t, int64(2), total)
+		if assert.Len(t, hits, 1) {
+			assert.Equal(t, "project", hits[0].ContentType)
+		}
+
+		_, total, _ = contents.Search(1, 10, "observabilidade", false)
+		assert.Equal(t, int64(3), total)
+	})
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Os índices únicos valem só para itens ativos, então um slug/título de um item na lixeira
+// pode ter sido reutilizado. Verificamos antes de restaurar para devolver um erro claro.
+func checkRestoreConflict(tx *gorm.DB, model any, title, slug string) error {
+	var count int64
+	if err := tx.Model(model).Where("slug = ?", slug).Count(&count).Error; err != nil {
+		return mapError(err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %w (%q)", ErrRestoreConflict, ErrDuplicateSlug, slug)
+	}
+
+	if err := tx.Model(model).Where("title = ?", title).Count(&count).Error; err != nil {
+		return mapError(err)
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %w (%q)", ErrRestoreConflict, ErrDuplicateTitle, title)
+	}
+	return nil
+}
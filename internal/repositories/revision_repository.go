@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/utils"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type RevisionRepository interface {
+	WithContext(ctx context.Context) RevisionRepository
+	Create(rev *models.Revision) error
+	FindByContent(contentType string, contentID uint, page, pageSize int) ([]models.Revision, int64, error)
+	FindByNumber(contentType string, contentID uint, number int) (*models.Revision, error)
+}
+
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+func (r *revisionRepository) WithContext(ctx context.Context) RevisionRepository {
+	return &revisionRepository{db: r.db.WithContext(ctx)}
+}
+
+// Numera a revisão em sequência para o conteúdo. Deve rodar na transação da alteração:
+// duas gravações concorrentes com o mesmo número esbarram no índice único.
+func (r *revisionRepository) Create(rev *models.Revision) error {
+	var last int
+	err := r.db.Model(&models.Revision{}).
+		Where("content_type = ? AND content_id = ?", rev.ContentType, rev.ContentID).
+		Select("COALESCE(MAX(number), 0)").Scan(&last).Error
+	if err != nil {
+		return mapError(err)
+	}
+	rev.Number = last + 1
+	return mapError(r.db.Create(rev).Error)
+}
+
+// Mais recentes primeiro
+func (r *revisionRepository) FindByContent(contentType string, contentID uint, page, pageSize int) ([]models.Revision, int64, error) {
+	var revisions []models.Revision
+	var total int64
+
+	query := r.db.Model(&models.Revision{}).Where("content_type = ? AND content_id = ?", contentType, contentID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
+	err := query.Scopes(utils.PaginateRepository(page, pageSize)).Order("number desc").Find(&revisions).Error
+	return revisions, total, mapError(err)
+}
+
+func (r *revisionRepository) FindByNumber(contentType string, contentID uint, number int) (*models.Revision, error) {
+	var rev models.Revision
+	err := r.db.Where("content_type = ? AND content_id = ? AND number = ?", contentType, contentID, number).First(&rev).Error
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &rev, nil
+}
+
+func purgeRevisions(tx *gorm.DB, contentType string, ids []uint) error {
+	return mapError(tx.Where("content_type = ? AND content_id IN ?", contentType, ids).Delete(&models.Revision{}).Error)
+}
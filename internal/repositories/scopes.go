@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Conteúdo visível ao público: com data de publicação já alcançada,
+// sem data de retirada (UnpublishAt) vencida e não arquivado.
+func visible(table string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(visibleCondition(table))
+	}
+}
+
+// A condição de visible como expressão, para combinar com OR entre tabelas
+func visibleCondition(table string) clause.Expr {
+	// Truncamos para UTC para garantir compatibilidade total com SQLite/Postgres
+	now := time.Now().UTC()
+	return clause.Expr{
+		SQL:  table + ".posted_at IS NOT NULL AND " + table + ".posted_at <= ? AND (" + table + ".unpublish_at IS NULL OR " + table + ".unpublish_at > ?) AND " + table + ".status <> ?",
+		Vars: []any{now, now, models.StatusArchived},
+	}
+}
+
+// Filtro opcional pelo estado editorial (vazio não filtra)
+func withStatus(table, status string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if status == "" {
+			return db
+		}
+		return db.Where(table+".status = ?", status)
+	}
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/search"
+	"cms-headless/internal/validators"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reescreve o documento de busca do post a partir do estado atual (itens na
+// lixeira continuam indexados; o join com posts já os exclui da busca)
+func indexPost(tx *gorm.DB, id uint) error {
+	var post models.Post
+	if err := tx.Unscoped().Preload("Tags").First(&post, id).Error; err != nil {
+		return mapError(err)
+	}
+	return saveSearchDocument(tx, models.SearchDocument{
+		ContentType:      contentTypePost,
+		ContentID:        post.ID,
+		Title:            post.Title,
+		ShortDescription: post.ShortDescription,
+		Body:             validators.StripHTML(post.Body),
+		Tags:             tagTitles(post.Tags),
+	})
+}
+
+func indexProject(tx *gorm.DB, id uint) error {
+	var project models.Project
+	if err := tx.Unscoped().Preload("Tags").First(&project, id).Error; err != nil {
+		return mapError(err)
+	}
+	return saveSearchDocument(tx, models.SearchDocument{
+		ContentType:      contentTypeProject,
+		ContentID:        project.ID,
+		Title:            project.Title,
+		ShortDescription: project.ShortDescription,
+		Body:             validators.StripHTML(project.Body),
+		Tags:             tagTitles(project.Tags),
+	})
+}
+
+func saveSearchDocument(tx *gorm.DB, doc models.SearchDocument) error {
+	if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&doc).Error; err != nil {
+		return mapError(err)
+	}
+	return saveSearchTerms(tx, doc)
+}
+
+// Substitui os termos do documento pelos do texto atual
+func saveSearchTerms(tx *gorm.DB, doc models.SearchDocument) error {
+	if err := tx.Where("content_type = ? AND content_id = ?", doc.ContentType, doc.ContentID).
+		Delete(&models.SearchTerm{}).Error; err != nil {
+		return mapError(err)
+	}
+	seen := make(map[string]bool)
+	var terms []models.SearchTerm
+	for _, t := range search.Tokens(strings.Join([]string{doc.Title, doc.ShortDescription, doc.Body, doc.Tags}, " ")) {
+		if seen[t] || search.IsStopword(t) {
+			continue
+		}
+		seen[t] = true
+		terms = append(terms, models.SearchTerm{ContentType: doc.ContentType, ContentID: doc.ContentID, Term: t})
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return mapError(tx.CreateInBatches(&terms, 500).Error)
+}
+
+func tagTitles(tags []models.Tag) string {
+	titles := make([]string, 0, len(tags))
+	for _, t := range tags {
+		titles = append(titles, t.Title)
+	}
+	return strings.Join(titles, " ")
+}
+
+// O título da tag faz parte do documento de todos os itens associados
+func reindexTag(tx *gorm.DB, tagID uint) error {
+	postIDs, projectIDs, err := tagKind.contentIDs(tx, []uint{tagID})
+	if err != nil {
+		return err
+	}
+	return reindexContent(tx, postIDs, projectIDs)
+}
+
+func reindexContent(tx *gorm.DB, postIDs, projectIDs []uint) error {
+	for _, id := range postIDs {
+		if err := indexPost(tx, id); err != nil {
+			return err
+		}
+	}
+	for _, id := range projectIDs {
+		if err := indexProject(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func purgeSearchDocuments(tx *gorm.DB, contentType string, ids []uint) error {
+	if err := tx.Where("content_type = ? AND content_id IN ?", contentType, ids).Delete(&models.SearchTerm{}).Error; err != nil {
+		return mapError(err)
+	}
+	return mapError(tx.Where("content_type = ? AND content_id IN ?", contentType, ids).Delete(&models.SearchDocument{}).Error)
+}
+
+// Indexa todo o conteúdo quando o índice está vazio (bancos anteriores à busca
+// textual ou à tabela de termos). Retorna quantos itens foram indexados.
+func EnsureSearchIndex(db *gorm.DB) (int, error) {
+	var indexed, terms int64
+	if err := db.Model(&models.SearchDocument{}).Count(&indexed).Error; err != nil {
+		return 0, mapError(err)
+	}
+	if err := db.Model(&models.SearchTerm{}).Count(&terms).Error; err != nil || indexed > 0 && terms > 0 {
+		return 0, mapError(err)
+	}
+
+	var postIDs, projectIDs []uint
+	if err := db.Unscoped().Model(&models.Post{}).Pluck("id", &postIDs).Error; err != nil {
+		return 0, mapError(err)
+	}
+	if err := db.Unscoped().Model(&models.Project{}).Pluck("id", &projectIDs).Error; err != nil {
+		return 0, mapError(err)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range postIDs {
+			if err := indexPost(tx, id); err != nil {
+				return err
+			}
+		}
+		for _, id := range projectIDs {
+			if err := indexProject(tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(postIDs) + len(projectIDs), nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+)
+
+// Item encontrado pela busca com a relevância calculada pelo backend de busca
+// (0 quando não há texto). A escala depende do backend; só a ordem é comparável.
+type SearchHit[T any] struct {
+	Item  T
+	Score float64
+}
+
+// Quantos resultados da busca atual têm cada tag/categoria
+type FacetCount struct {
+	ID    uint
+	Title string
+	Count int64
+}
+
+type Facets struct {
+	Tags       []FacetCount
+	Categories []FacetCount
+}
+
+type rankedID struct {
+	ID         uint
+	SearchRank float64
+}
+
+func rankedIDs(ranked []rankedID) []uint {
+	ids := make([]uint, 0, len(ranked))
+	for _, r := range ranked {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// Remonta os itens carregados na ordem (e com a relevância) da consulta ranqueada
+func toHits[T any](ranked []rankedID, items []T, id func(T) uint) []SearchHit[T] {
+	byID := make(map[uint]T, len(items))
+	for _, item := range items {
+		byID[id(item)] = item
+	}
+	hits := make([]SearchHit[T], 0, len(ranked))
+	for _, r := range ranked {
+		if item, ok := byID[r.ID]; ok {
+			hits = append(hits, SearchHit[T]{Item: item, Score: r.SearchRank})
+		}
+	}
+	return hits
+}
+
+// ids é a subconsulta com os IDs do conjunto filtrado; ordena por contagem e depois título
+func searchFacets(db *gorm.DB, tagJoin, categoryJoin, fk string, ids *gorm.DB) (Facets, error) {
+	var facets Facets
+	err := db.Table(tagJoin).
+		Select("tags.id, tags.title, COUNT(DISTINCT "+tagJoin+"."+fk+") AS count").
+		Joins("JOIN tags ON tags.id = "+tagJoin+".tag_id").
+		Where(tagJoin+"."+fk+" IN (?)", ids).
+		Group("tags.id, tags.title").Order("count DESC, tags.title ASC").
+		Scan(&facets.Tags).Error
+	if err != nil {
+		return Facets{}, mapError(err)
+	}
+	err = db.Table(categoryJoin).
+		Select("categories.id, categories.title, COUNT(DISTINCT "+categoryJoin+"."+fk+") AS count").
+		Joins("JOIN categories ON categories.id = "+categoryJoin+".category_id").
+		Where(categoryJoin+"."+fk+" IN (?)", ids).
+		Group("categories.id, categories.title").Order("count DESC, categories.title ASC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return Facets{}, mapError(err)
+	}
+	return facets, nil
+}
@@ -2,6 +2,7 @@ package repositories_test
 
 import (
 	"cms-headless/internal/models"
+	"cms-headless/internal/search"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -13,5 +14,13 @@ func SetupTestDB() *gorm.DB {
 	db.AutoMigrate(&models.Post{})
 	db.AutoMigrate(&models.Project{})
 	db.AutoMigrate(&models.Tag{})
+	db.AutoMigrate(&models.TagAlias{})
+	db.AutoMigrate(&models.SlugHistory{})
+	db.AutoMigrate(&models.Revision{})
+	db.AutoMigrate(&models.ContentDraft{})
+	db.AutoMigrate(&models.StateChange{})
+	db.AutoMigrate(&models.SearchDocument{})
+	db.AutoMigrate(&models.SearchTerm{})
+	search.Setup(db)
 	return db
 }
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	contentTypePost     = "post"
+	contentTypeProject  = "project"
+	contentTypeTag      = "tag"
+	contentTypeCategory = "category"
+)
+
+// Guarda o slug antigo apontando para o item. Se o mesmo slug antigo já existia
+// (de outro item), o registro passa a apontar para o item atual.
+func recordSlugHistory(tx *gorm.DB, contentType string, contentID uint, oldSlug string) error {
+	entry := models.SlugHistory{ContentType: contentType, ContentID: contentID, Slug: oldSlug, CreatedAt: time.Now().UTC()}
+	return mapError(tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "content_type"}, {Name: "slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content_id", "created_at"}),
+	}).Create(&entry).Error)
+}
+
+// Um slug em uso por um item ativo tem prioridade sobre qualquer redirecionamento
+func releaseSlugHistory(tx *gorm.DB, contentType, slug string) error {
+	return mapError(tx.Where("content_type = ? AND slug = ?", contentType, slug).Delete(&models.SlugHistory{}).Error)
+}
+
+// ID do item que usava o slug antes de ser renomeado
+func findSlugHistory(db *gorm.DB, contentType, slug string) (uint, error) {
+	var entry models.SlugHistory
+	err := db.Where("content_type = ? AND slug = ?", contentType, slug).First(&entry).Error
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return entry.ContentID, nil
+}
+
+func purgeSlugHistory(tx *gorm.DB, contentType string, ids []uint) error {
+	return mapError(tx.Where("content_type = ? AND content_id IN ?", contentType, ids).Delete(&models.SlugHistory{}).Error)
+}
+
+// Slugs de itens fundidos/reatribuídos passam a redirecionar para o destino,
+// inclusive os que já estavam no histórico deles
+func redirectSlugHistory(tx *gorm.DB, contentType string, ids []uint, targetID uint, slugs []string) error {
+	err := tx.Model(&models.SlugHistory{}).Where("content_type = ? AND content_id IN ?", contentType, ids).
+		Update("content_id", targetID).Error
+	if err != nil {
+		return mapError(err)
+	}
+	for _, slug := range slugs {
+		if err := recordSlugHistory(tx, contentType, targetID, slug); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type StateChangeRepository interface {
+	WithContext(ctx context.Context) StateChangeRepository
+	Create(change *models.StateChange) error
+	FindByContent(contentType string, contentID uint) ([]models.StateChange, error)
+}
+
+type stateChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewStateChangeRepository(db *gorm.DB) StateChangeRepository {
+	return &stateChangeRepository{db: db}
+}
+
+func (r *stateChangeRepository) WithContext(ctx context.Context) StateChangeRepository {
+	return &stateChangeRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *stateChangeRepository) Create(change *models.StateChange) error {
+	return mapError(r.db.Create(change).Error)
+}
+
+// Histórico completo em ordem cronológica
+func (r *stateChangeRepository) FindByContent(contentType string, contentID uint) ([]models.StateChange, error) {
+	var changes []models.StateChange
+	err := r.db.Where("content_type = ? AND content_id = ?", contentType, contentID).Order("id asc").Find(&changes).Error
+	return changes, mapError(err)
+}
+
+func purgeStateChanges(tx *gorm.DB, contentType string, ids []uint) error {
+	return mapError(tx.Where("content_type = ? AND content_id IN ?", contentType, ids).Delete(&models.StateChange{}).Error)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/validators"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Tag pelo título normalizado ou, se não houver, pelo alias ("golang" -> "Go")
+func (r *tagRepository) Resolve(name string) (*models.Tag, error) {
+	tag, err := r.FindByTitle(name)
+	if !errors.Is(err, ErrNotFound) {
+		return tag, err
+	}
+	var resolved models.Tag
+	err = r.db.Joins("JOIN tag_aliases ON tag_aliases.tag_id = tags.id").
+		Where("tag_aliases.normalized_alias = ?", validators.NormalizeTitle(name)).
+		First(&resolved).Error
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &resolved, nil
+}
+
+// Tags canônicas para os nomes, na ordem recebida e sem repetição; nomes que não
+// casam com título nem alias viram tags novas. Nomes em branco são ignorados.
+func (r *tagRepository) FindOrCreate(names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		repo := &tagRepository{db: tx}
+		seen := make(map[uint]bool, len(names))
+		for _, name := range names {
+			if validators.NormalizeTitle(name) == "" {
+				continue
+			}
+			tag, err := repo.Resolve(name)
+			if errors.Is(err, ErrNotFound) {
+				tag = &models.Tag{Title: name}
+				err = repo.Create(tag)
+			}
+			if err != nil {
+				return err
+			}
+			if !seen[tag.ID] {
+				seen[tag.ID] = true
+				tags = append(tags, *tag)
+			}
+		}
+		return nil
+	})
+	return tags, err
+}
+
+func (r *tagRepository) Aliases(tagID uint) ([]models.TagAlias, error) {
+	if err := tagKind.ensureExists(r.db, []uint{tagID}); err != nil {
+		return nil, err
+	}
+	aliases := []models.TagAlias{}
+	err := r.db.Where("tag_id = ?", tagID).Order("alias asc").Find(&aliases).Error
+	return aliases, mapError(err)
+}
+
+// Alias igual ao título de alguma tag, ou já usado, devolve ErrDuplicateTitle
+func (r *tagRepository) AddAlias(tagID uint, alias string) (*models.TagAlias, error) {
+	created := &models.TagAlias{TagID: tagID, Alias: alias}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tagKind.ensureExists(tx, []uint{tagID}); err != nil {
+			return err
+		}
+		var count int64
+		err := tx.Model(&models.Tag{}).Where("normalized_title = ?", validators.NormalizeTitle(alias)).Count(&count).Error
+		if err != nil {
+			return mapError(err)
+		}
+		if count > 0 {
+			return ErrDuplicateTitle
+		}
+		return mapError(tx.Create(created).Error)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *tagRepository) DeleteAlias(tagID, aliasID uint) error {
+	return affectedOrNotFound(r.db.Where("id = ? AND tag_id = ?", aliasID, tagID).Delete(&models.TagAlias{}))
+}
+
+// Título de tag não pode coincidir com alias de outra tag (ErrDuplicateTitle). Se o
+// alias é da própria tag, ele deixa de ser necessário e é removido.
+func claimAliasedTitle(tx *gorm.DB, tagID uint, title string) error {
+	var alias models.TagAlias
+	err := tx.Where("normalized_alias = ?", validators.NormalizeTitle(title)).Limit(1).Find(&alias).Error
+	if err != nil {
+		return mapError(err)
+	}
+	switch {
+	case alias.ID == 0:
+		return nil
+	case alias.TagID != tagID:
+		return ErrDuplicateTitle
+	}
+	return mapError(tx.Delete(&alias).Error)
+}
@@ -3,14 +3,37 @@ package repositories
 import (
 	"cms-headless/internal/models"
 	"cms-headless/internal/utils"
+	"cms-headless/internal/validators"
+	"context"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type TagRepository interface {
+	WithContext(ctx context.Context) TagRepository
 	FindAll(page, pageSize int) ([]models.Tag, int64, error)
+	FindPage(q utils.CursorQuery) (CursorPage[models.Tag], error)
+	FindByIDs(ids []uint) ([]models.Tag, error)
 	Create(tag *models.Tag) error
 	UpdateName(id uint, newTitle string) error
+	Update(tag *models.Tag) error
+	FindByID(id uint) (*models.Tag, error)
+	FindByTitle(title string) (*models.Tag, error)
+	FindBySlug(slug string) (*models.Tag, error)
+	FindBySlugOrRedirect(slug string) (*models.Tag, string, error)
+	SlugExists(slug string, excludeID uint) (bool, error)
+	Delete(id, reassignTo uint) error
+	Merge(sourceIDs []uint, targetID uint) error
+	UsageCounts(ids []uint) (map[uint]TaxonomyUsage, error)
+	Resolve(name string) (*models.Tag, error)
+	FindOrCreate(names []string) ([]models.Tag, error)
+	Aliases(tagID uint) ([]models.TagAlias, error)
+	AddAlias(tagID uint, alias string) (*models.TagAlias, error)
+	DeleteAlias(tagID, aliasID uint) error
+	Autocomplete(q string, limit int) ([]TagMatch, error)
+	Suggest(text string, exclude []uint, limit int) (TagSuggestions, error)
 }
 
 type tagRepository struct {
@@ -21,24 +44,180 @@ func NewTagRepository(db *gorm.DB) TagRepository {
 	return &tagRepository{db: db}
 }
 
+// Retorna uma cópia do repositório cujas consultas respeitam cancelamento e prazo do ctx
+func (r *tagRepository) WithContext(ctx context.Context) TagRepository {
+	return &tagRepository{db: r.db.WithContext(ctx)}
+}
+
 // Listagem de tags
 func (r *tagRepository) FindAll(page, pageSize int) ([]models.Tag, int64, error) {
 	var tags []models.Tag
 	var total int64
 
-	r.db.Model(&models.Tag{}).Count(&total)
+	if err := r.db.Model(&models.Tag{}).Count(&total).Error; err != nil {
+		return nil, 0, mapError(err)
+	}
 	err := r.db.Scopes(utils.PaginateRepository(page, pageSize)).Order("title asc").Find(&tags).Error
 
-	return tags, total, err
+	return tags, total, mapError(err)
+}
+
+// Listagem por cursor sobre (created_at, id), das mais recentes para as mais antigas
+func (r *tagRepository) FindPage(q utils.CursorQuery) (CursorPage[models.Tag], error) {
+	return findPage(r.db.Model(&models.Tag{}), q, "tags.created_at", "tags.id", func(t models.Tag) (*time.Time, uint) {
+		return &t.CreatedAt, t.ID
+	})
 }
 
 // Criar uma nova tag
 func (r *tagRepository) Create(tag *models.Tag) error {
-	return r.db.Create(tag).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := claimAliasedTitle(tx, 0, tag.Title); err != nil {
+			return err
+		}
+		// Sem slug, o hook BeforeCreate gera um a partir do título
+		if tag.Slug != "" {
+			if err := releaseSlugHistory(tx, contentTypeTag, tag.Slug); err != nil {
+				return err
+			}
+		}
+		return mapError(tx.Create(tag).Error)
+	})
 }
 
-// Renomear uma tag
+// Renomeia gerando um slug novo; o antigo continua redirecionando (reindexa a busca dos itens associados)
 func (r *tagRepository) UpdateName(id uint, newTitle string) error {
-	// Usamos o Model para garantir que o GORM saiba qual tabela e ID usar
-	return r.db.Model(&models.Tag{}).Where("id = ?", id).Update("title", newTitle).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		repo := NewTagRepository(tx)
+		tag, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if tag.Title == newTitle {
+			return nil
+		}
+		if tag.Slug, err = tagKind.slugFor(tx, newTitle, id); err != nil {
+			return err
+		}
+		tag.Title = newTitle
+		return repo.Update(tag)
+	})
+}
+
+// Salva todos os campos; slug alterado vai para o histórico
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tagKind.trackSlug(tx, tag.ID, tag.Slug); err != nil {
+			return err
+		}
+		if err := claimAliasedTitle(tx, tag.ID, tag.Title); err != nil {
+			return err
+		}
+		if err := tx.Save(tag).Error; err != nil {
+			return mapError(err)
+		}
+		return reindexTag(tx, tag.ID)
+	})
+}
+
+// Busca várias tags de uma vez (IDs inexistentes são simplesmente ignorados)
+func (r *tagRepository) FindByIDs(ids []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&tags).Error
+	return tags, mapError(err)
+}
+
+func (r *tagRepository) FindByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.First(&tag, id).Error; err != nil {
+		return nil, mapError(err)
+	}
+	return &tag, nil
+}
+
+// Busca pelo título sem diferenciar caixa, acentos e espaços extras (não considera aliases)
+func (r *tagRepository) FindByTitle(title string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Where("normalized_title = ?", validators.NormalizeTitle(title)).First(&tag).Error; err != nil {
+		return nil, mapError(err)
+	}
+	return &tag, nil
+}
+
+// Remove a tag; com reassignTo > 0 os itens associados passam para essa tag e o
+// título vira alias dela, senão apenas perdem a associação
+func (r *tagRepository) Delete(id, reassignTo uint) error {
+	if reassignTo > 0 {
+		return r.Merge([]uint{id}, reassignTo)
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tagKind.ensureExists(tx, []uint{id}); err != nil {
+			return err
+		}
+		postIDs, projectIDs, err := tagKind.contentIDs(tx, []uint{id})
+		if err != nil {
+			return err
+		}
+		if err := tagKind.remove(tx, []uint{id}, 0); err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.TagAlias{}).Error; err != nil {
+			return mapError(err)
+		}
+		return reindexContent(tx, postIDs, projectIDs)
+	})
+}
+
+// Funde as tags de origem na de destino numa única transação: as junções
+// post_tags/project_tags são reescritas e as origens removidas. Os títulos das
+// origens e os aliases delas viram aliases do destino.
+func (r *tagRepository) Merge(sourceIDs []uint, targetID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var titles []string
+		if err := tx.Model(&models.Tag{}).Where("id IN ?", sourceIDs).Pluck("title", &titles).Error; err != nil {
+			return mapError(err)
+		}
+		if err := tagKind.merge(tx, sourceIDs, targetID); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TagAlias{}).Where("tag_id IN ?", sourceIDs).Update("tag_id", targetID).Error; err != nil {
+			return mapError(err)
+		}
+		for _, title := range titles {
+			if err := tx.Create(&models.TagAlias{TagID: targetID, Alias: title}).Error; err != nil {
+				return mapError(err)
+			}
+		}
+		return reindexTag(tx, targetID)
+	})
+}
+
+// Quantos posts e projetos publicados usam cada tag
+func (r *tagRepository) UsageCounts(ids []uint) (map[uint]TaxonomyUsage, error) {
+	return tagKind.usage(r.db, ids)
+}
+
+func (r *tagRepository) FindBySlug(slug string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.Where("slug = ?", slug).First(&tag).Error; err != nil {
+		return nil, mapError(err)
+	}
+	return &tag, nil
+}
+
+// Busca pelo slug atual; se o slug pertenceu a uma tag renomeada ou fundida, devolve o slug novo
+func (r *tagRepository) FindBySlugOrRedirect(slug string) (*models.Tag, string, error) {
+	tag, err := r.FindBySlug(slug)
+	if !errors.Is(err, ErrNotFound) {
+		return tag, "", err
+	}
+	movedTo, err := tagKind.redirectTarget(r.db, slug)
+	return nil, movedTo, err
+}
+
+func (r *tagRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	return tagKind.slugExists(r.db, slug, excludeID)
 }
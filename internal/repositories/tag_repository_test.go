@@ -3,6 +3,8 @@ package repositories_test
 import (
 	"cms-headless/internal/models"
 	"cms-headless/internal/repositories"
+	"cms-headless/internal/search"
+	"cms-headless/internal/utils"
 	"testing"
 	"time"
 
@@ -62,7 +64,413 @@ func TestTagRepository(t *testing.T) {
 		assert.NoError(t, err)
 
 		err = repo.Create(&models.Tag{Title: "Duplicate"})
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "UNIQUE constraint failed")
+		assert.ErrorIs(t, err, repositories.ErrDuplicateTitle)
+		assert.Contains(t, err.Error(), "UNIQUE constraint failed", "o erro original do driver deve ser preservado")
+	})
+}
+
+func TestTagRepository_FindPage(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewTagRepository(db)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	db.Create(&models.Tag{Title: "Antiga", CreatedAt: now.Add(-time.Hour)})
+	db.Create(&models.Tag{Title: "Empate A", CreatedAt: now})
+	db.Create(&models.Tag{Title: "Empate B", CreatedAt: now})
+
+	t.Run("Deve listar das mais recentes para as mais antigas por cursor", func(t *testing.T) {
+		page, err := repo.FindPage(utils.CursorQuery{Limit: 2, WithTotal: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), *page.Total)
+		if assert.Len(t, page.Items, 2) {
+			assert.Equal(t, "Empate B", page.Items[0].Title)
+			assert.Equal(t, "Empate A", page.Items[1].Title)
+		}
+
+		last, err := repo.FindPage(utils.CursorQuery{Limit: 2, After: page.Next})
+		assert.NoError(t, err)
+		assert.Nil(t, last.Next)
+		if assert.Len(t, last.Items, 1) {
+			assert.Equal(t, "Antiga", last.Items[0].Title)
+		}
+	})
+}
+
+func TestTagRepository_Management(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewTagRepository(db)
+	posts := repositories.NewPostRepository(db)
+	projects := repositories.NewProjectRepository(db)
+	now := time.Now().UTC().Add(-time.Minute)
+
+	golang := models.Tag{Title: "Go"}
+	golangAlt := models.Tag{Title: "Golang"}
+	velha := models.Tag{Title: "Velha"}
+	sobra := models.Tag{Title: "Sobra"}
+	for _, tag := range []*models.Tag{&golang, &golangAlt, &velha, &sobra} {
+		repo.Create(tag)
+	}
+
+	ambas := models.Post{Title: "Com as duas", Slug: "ambas", PostedAt: &now, Tags: []models.Tag{golang, golangAlt}}
+	soAlt := models.Post{Title: "Só a variante", Slug: "so-alt", PostedAt: &now, Tags: []models.Tag{golangAlt, velha}}
+	rascunho := models.Post{Title: "Rascunho", Slug: "rascunho", Tags: []models.Tag{golang}}
+	posts.Create(&ambas)
+	posts.Create(&soAlt)
+	posts.Create(&rascunho)
+	projects.Create(&models.Project{Title: "Projeto", Slug: "projeto", PostedAt: &now, Tags: []models.Tag{golangAlt}})
+	db.Create(&models.ContentDraft{ContentType: "post", ContentID: ambas.ID, Title: "x", Slug: "ambas", TagIDs: []uint{golangAlt.ID, velha.ID}})
+
+	tagIDs := func(postID uint) []uint {
+		var ids []uint
+		db.Table("post_tags").Where("post_id = ?", postID).Order("tag_id").Pluck("tag_id", &ids)
+		return ids
+	}
+
+	t.Run("Deve buscar por ID e por título sem diferenciar caixa", func(t *testing.T) {
+		tag, err := repo.FindByID(golang.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Go", tag.Title)
+
+		tag, err = repo.FindByTitle("GOLANG")
+		assert.NoError(t, err)
+		assert.Equal(t, golangAlt.ID, tag.ID)
+
+		_, err = repo.FindByTitle("Rust")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+		_, err = repo.FindByID(999)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("Deve contar só o uso publicado", func(t *testing.T) {
+		usage, err := repo.UsageCounts([]uint{golang.ID, golangAlt.ID, sobra.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, repositories.TaxonomyUsage{Posts: 1}, usage[golang.ID], "rascunho não conta")
+		assert.Equal(t, repositories.TaxonomyUsage{Posts: 2, Projects: 1}, usage[golangAlt.ID])
+		assert.Equal(t, repositories.TaxonomyUsage{}, usage[sobra.ID])
+	})
+
+	t.Run("Deve fundir reescrevendo as junções sem duplicar", func(t *testing.T) {
+		err := repo.Merge([]uint{golangAlt.ID}, golang.ID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{golang.ID}, tagIDs(ambas.ID))
+		assert.Equal(t, []uint{golang.ID, velha.ID}, tagIDs(soAlt.ID))
+		_, err = repo.FindByID(golangAlt.ID)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+		usage, _ := repo.UsageCounts([]uint{golang.ID})
+		assert.Equal(t, repositories.TaxonomyUsage{Posts: 2, Projects: 1}, usage[golang.ID])
+
+		var draft models.ContentDraft
+		db.First(&draft)
+		assert.ElementsMatch(t, []uint{velha.ID, golang.ID}, draft.TagIDs, "rascunhos seguem a fusão")
+
+		res, total, _ := posts.Search(1, 10, repositories.ContentFilter{Query: "golang"})
+		assert.Zero(t, total, "o título antigo sai do índice de busca: %v", res)
+	})
+
+	t.Run("Deve rejeitar destino entre as origens ou inexistente", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Merge([]uint{golang.ID}, golang.ID), repositories.ErrInvalidReference)
+		assert.ErrorIs(t, repo.Merge([]uint{velha.ID}, 999), repositories.ErrNotFound)
+		assert.Equal(t, []uint{golang.ID, velha.ID}, tagIDs(soAlt.ID), "nada muda quando falha")
+	})
+
+	t.Run("Deve remover reatribuindo ou só desassociando", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(velha.ID, sobra.ID))
+		assert.Equal(t, []uint{golang.ID, sobra.ID}, tagIDs(soAlt.ID))
+
+		assert.NoError(t, repo.Delete(sobra.ID, 0))
+		assert.Equal(t, []uint{golang.ID}, tagIDs(soAlt.ID))
+
+		var draft models.ContentDraft
+		db.First(&draft)
+		assert.Equal(t, []uint{golang.ID}, draft.TagIDs)
+
+		assert.ErrorIs(t, repo.Delete(sobra.ID, 0), repositories.ErrNotFound)
+	})
+}
+
+func TestTagRepository_Slugs(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewTagRepository(db)
+
+	goTag := models.Tag{Title: "Go"}
+	repo.Create(&goTag)
+
+	t.Run("Deve gerar slug único a partir do título", func(t *testing.T) {
+		outra := models.Tag{Title: "GO!"}
+		assert.NoError(t, repo.Create(&outra))
+
+		assert.Equal(t, "go", goTag.Slug)
+		assert.Equal(t, "go-2", outra.Slug)
+	})
+
+	t.Run("Deve rejeitar slug manual repetido", func(t *testing.T) {
+		err := repo.Create(&models.Tag{Title: "Golang", Slug: "go"})
+		assert.ErrorIs(t, err, repositories.ErrDuplicateSlug)
+	})
+
+	t.Run("Deve manter o slug antigo como redirecionamento ao renomear", func(t *testing.T) {
+		assert.NoError(t, repo.UpdateName(goTag.ID, "Golang"))
+
+		tag, movedTo, err := repo.FindBySlugOrRedirect("go")
+		assert.NoError(t, err)
+		assert.Nil(t, tag)
+		assert.Equal(t, "golang", movedTo)
+
+		tag, movedTo, err = repo.FindBySlugOrRedirect("golang")
+		assert.NoError(t, err)
+		assert.Empty(t, movedTo)
+		assert.Equal(t, goTag.ID, tag.ID)
+	})
+
+	t.Run("Deve redirecionar slugs de tags fundidas para o destino", func(t *testing.T) {
+		rust := models.Tag{Title: "Rust"}
+		ferrugem := models.Tag{Title: "Ferrugem"}
+		repo.Create(&rust)
+		repo.Create(&ferrugem)
+
+		assert.NoError(t, repo.Merge([]uint{ferrugem.ID}, rust.ID))
+
+		_, movedTo, err := repo.FindBySlugOrRedirect("ferrugem")
+		assert.NoError(t, err)
+		assert.Equal(t, "rust", movedTo)
+	})
+
+	t.Run("Deve retornar ErrNotFound para slug desconhecido", func(t *testing.T) {
+		_, _, err := repo.FindBySlugOrRedirect("nada")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestTagRepository_Aliases(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewTagRepository(db)
+
+	goTag := models.Tag{Title: "Go"}
+	repo.Create(&goTag)
+
+	t.Run("Deve tratar títulos iguais a menos de caixa, acentos e espaços como duplicados", func(t *testing.T) {
+		assert.ErrorIs(t, repo.Create(&models.Tag{Title: "  go "}), repositories.ErrDuplicateTitle)
+
+		acento := models.Tag{Title: "Programação"}
+		assert.NoError(t, repo.Create(&acento))
+		assert.ErrorIs(t, repo.Create(&models.Tag{Title: "programacao"}), repositories.ErrDuplicateTitle)
+
+		found, err := repo.FindByTitle("PROGRAMAÇÃO")
+		assert.NoError(t, err)
+		assert.Equal(t, acento.ID, found.ID)
+	})
+
+	t.Run("Deve resolver alias para a tag canônica", func(t *testing.T) {
+		_, err := repo.AddAlias(goTag.ID, "Golang")
+		assert.NoError(t, err)
+
+		tag, err := repo.Resolve("golang")
+		assert.NoError(t, err)
+		assert.Equal(t, goTag.ID, tag.ID)
+
+		_, err = repo.Resolve("rust")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("Deve recusar alias igual a título ou a outro alias", func(t *testing.T) {
+		_, err := repo.AddAlias(goTag.ID, "programação")
+		assert.ErrorIs(t, err, repositories.ErrDuplicateTitle)
+
+		_, err = repo.AddAlias(goTag.ID, "GOLANG")
+		assert.ErrorIs(t, err, repositories.ErrDuplicateTitle)
+
+		_, err = repo.AddAlias(999, "Outro")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("Deve recusar título igual a alias de outra tag", func(t *testing.T) {
+		err := repo.Create(&models.Tag{Title: "golang"})
+		assert.ErrorIs(t, err, repositories.ErrDuplicateTitle)
+	})
+
+	t.Run("Deve liberar o alias quando a própria tag assume o nome", func(t *testing.T) {
+		assert.NoError(t, repo.UpdateName(goTag.ID, "Golang"))
+
+		aliases, err := repo.Aliases(goTag.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, aliases)
+	})
+
+	t.Run("Deve encontrar ou criar tags sem repetir as canônicas", func(t *testing.T) {
+		tags, err := repo.FindOrCreate([]string{"golang", " GOLANG", "Rust", "", "rust"})
+		assert.NoError(t, err)
+		if assert.Len(t, tags, 2) {
+			assert.Equal(t, goTag.ID, tags[0].ID)
+			assert.Equal(t, "Rust", tags[1].Title)
+			assert.NotZero(t, tags[1].ID)
+		}
+	})
+
+	t.Run("Deve transformar os títulos das tags fundidas em aliases", func(t *testing.T) {
+		ferrugem := models.Tag{Title: "Ferrugem"}
+		repo.Create(&ferrugem)
+		rust, _ := repo.FindByTitle("rust")
+		repo.AddAlias(ferrugem.ID, "Oxidação")
+
+		assert.NoError(t, repo.Merge([]uint{ferrugem.ID}, rust.ID))
+
+		aliases, err := repo.Aliases(rust.ID)
+		assert.NoError(t, err)
+		var names []string
+		for _, a := range aliases {
+			names = append(names, a.Alias)
+		}
+		assert.ElementsMatch(t, []string{"Ferrugem", "Oxidação"}, names)
+
+		tag, err := repo.Resolve("ferrugem")
+		assert.NoError(t, err)
+		assert.Equal(t, rust.ID, tag.ID)
+	})
+
+	t.Run("Deve remover alias apenas da tag dona", func(t *testing.T) {
+		rust, _ := repo.FindByTitle("rust")
+		aliases, _ := repo.Aliases(rust.ID)
+
+		assert.ErrorIs(t, repo.DeleteAlias(goTag.ID, aliases[0].ID), repositories.ErrNotFound)
+		assert.NoError(t, repo.DeleteAlias(rust.ID, aliases[0].ID))
+	})
+}
+
+func TestTagRepository_Autocomplete(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewTagRepository(db)
+	past := time.Now().UTC().Add(-time.Hour)
+
+	golang := models.Tag{Title: "Go"}
+	goroutines := models.Tag{Title: "Goroutines"}
+	google := models.Tag{Title: "Google Cloud"}
+	cloud := models.Tag{Title: "Cloud Native"}
+	kubernetes := models.Tag{Title: "Kubernetes"}
+	for _, tag := range []*models.Tag{&golang, &goroutines, &google, &cloud, &kubernetes} {
+		repo.Create(tag)
+	}
+	repo.AddAlias(kubernetes.ID, "K8s")
+	posts := repositories.NewPostRepository(db)
+	posts.Create(&models.Post{Title: "A", Slug: "a", PostedAt: &past, Tags: []models.Tag{goroutines}})
+	posts.Create(&models.Post{Title: "B", Slug: "b", PostedAt: &past, Tags: []models.Tag{goroutines, golang}})
+
+	titles := func(matches []repositories.TagMatch) []string {
+		var res []string
+		for _, m := range matches {
+			res = append(res, m.Tag.Title)
+		}
+		return res
+	}
+
+	t.Run("Deve listar por prefixo das mais usadas para as menos", func(t *testing.T) {
+		matches, err := repo.Autocomplete("GO", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Goroutines", "Go", "Google Cloud"}, titles(matches))
+		assert.Equal(t, int64(2), matches[0].Usage.Posts)
+	})
+
+	t.Run("Deve trazer depois do prefixo as palavras internas e os erros de digitação", func(t *testing.T) {
+		matches, err := repo.Autocomplete("clou", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Cloud Native", "Google Cloud"}, titles(matches))
+
+		matches, err = repo.Autocomplete("kubernets", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Kubernetes"}, titles(matches))
+	})
+
+	t.Run("Deve casar por alias informando qual", func(t *testing.T) {
+		matches, err := repo.Autocomplete("k8", 10)
+		assert.NoError(t, err)
+		if assert.Len(t, matches, 1) {
+			assert.Equal(t, kubernetes.ID, matches[0].Tag.ID)
+			assert.Equal(t, "K8s", matches[0].Alias)
+		}
+	})
+
+	t.Run("Deve respeitar o limite e não aceitar erros em textos curtos", func(t *testing.T) {
+		matches, err := repo.Autocomplete("go", 1)
+		assert.NoError(t, err)
+		assert.Len(t, matches, 1)
+
+		matches, err = repo.Autocomplete("xo", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, matches)
+		assert.NotNil(t, matches)
+	})
+}
+
+func TestTagRepository_Suggest(t *testing.T) {
+	db := SetupTestDB()
+	repo := repositories.NewTagRepository(db)
+	posts := repositories.NewPostRepository(db)
+
+	golang := models.Tag{Title: "Go"}
+	concorrencia := models.Tag{Title: "Concorrência"}
+	bancos := models.Tag{Title: "Banco de Dados"}
+	rust := models.Tag{Title: "Rust"}
+	for _, tag := range []*models.Tag{&golang, &concorrencia, &bancos, &rust} {
+		repo.Create(tag)
+	}
+	repo.AddAlias(golang.ID, "Golang")
+
+	// Acervo: "go" aparece em todos os documentos, "canais" só num
+	posts.Create(&models.Post{Title: "Go básico", Slug: "go-basico", Body: "Variáveis em Go"})
+	posts.Create(&models.Post{Title: "Go web", Slug: "go-web", Body: "Servidores HTTP em Go"})
+	text := "Concorrência em Go com canais. Canais sincronizam goroutines; em Golang o banco de dados também é simples."
+	posts.Create(&models.Post{Title: "Canais", Slug: "canais", Body: text})
+
+	t.Run("Deve extrair palavras-chave por TF-IDF", func(t *testing.T) {
+		res, err := repo.Suggest(text, nil, 3)
+		assert.NoError(t, err)
+		if assert.Len(t, res.Keywords, 3) {
+			assert.Equal(t, "canais", res.Keywords[0].Term, "frequente no texto e rara no acervo")
+		}
+		for _, k := range res.Keywords {
+			assert.NotEqual(t, "em", k.Term, "stopwords ficam de fora")
+		}
+	})
+
+	t.Run("Deve propor tags cujo título ou alias aparece no texto", func(t *testing.T) {
+		res, err := repo.Suggest(text, nil, 10)
+		assert.NoError(t, err)
+
+		found := map[string]string{}
+		for _, s := range res.Tags {
+			found[s.Tag.Title] = s.Keyword
+			assert.Greater(t, s.Score, 0.0)
+		}
+		assert.Equal(t, map[string]string{"Go": found["Go"], "Concorrência": "Concorrência", "Banco de Dados": "Banco de Dados"}, found)
+		assert.Contains(t, []string{"Go", "Golang"}, found["Go"])
+	})
+
+	t.Run("Deve deixar de fora as tags já atribuídas", func(t *testing.T) {
+		res, err := repo.Suggest(text, []uint{golang.ID, bancos.ID}, 10)
+		assert.NoError(t, err)
+		if assert.Len(t, res.Tags, 1) {
+			assert.Equal(t, concorrencia.ID, res.Tags[0].Tag.ID)
+		}
+	})
+
+	t.Run("Deve devolver listas vazias para texto sem palavras", func(t *testing.T) {
+		res, err := repo.Suggest(" ... ", nil, 10)
+		assert.NoError(t, err)
+		assert.NotNil(t, res.Keywords)
+		assert.Empty(t, res.Tags)
+	})
+
+	t.Run("Deve contar a frequência no acervo pelo termo exato", func(t *testing.T) {
+		// "goroutines" começa com "go", mas não conta como documento com "go"
+		posts.Create(&models.Post{Title: "Goroutines", Slug: "goroutines", Body: "Agendador de goroutines"})
+
+		res, err := repo.Suggest("go", nil, 1)
+		assert.NoError(t, err)
+		if assert.Len(t, res.Keywords, 1) {
+			assert.InDelta(t, search.TFIDF(1, 1, 3, 4), res.Keywords[0].Score, 1e-9)
+		}
 	})
 }
@@ -0,0 +1,326 @@
+package repositories
+
+import (
+	"cmp"
+	"cms-headless/internal/models"
+	"cms-headless/internal/search"
+	"cms-headless/internal/validators"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Quão bem um nome casa com o que foi digitado no autocomplete; menor é melhor
+const (
+	matchPrefix = iota // O nome começa pelo texto
+	matchWord          // Outra palavra do nome começa pelo texto
+	matchFuzzy         // Alguma palavra começa a poucos erros de digitação do texto
+	noMatch
+)
+
+// Quantos termos mais frequentes do texto viram palavras-chave candidatas (e
+// filtram as tags consideradas nas sugestões)
+const keywordCandidates = 30
+
+// Tag encontrada pelo autocomplete. Alias é o nome alternativo que casou quando
+// ele casou melhor que o título.
+type TagMatch struct {
+	Tag   models.Tag
+	Alias string
+	Usage TaxonomyUsage
+}
+
+// Termo do texto com seu peso TF-IDF
+type Keyword struct {
+	Term  string
+	Score float64
+}
+
+// Tag cujo título ou alias (Keyword) aparece no texto, com o peso TF-IDF dele
+type TagSuggestion struct {
+	Tag     models.Tag
+	Keyword string
+	Score   float64
+}
+
+type TagSuggestions struct {
+	Keywords []Keyword
+	Tags     []TagSuggestion
+}
+
+// Tags cujo título ou alias começa pelo texto (ou por uma versão dele com erros
+// de digitação), das que casam melhor para as piores e, dentro de cada grupo,
+// das mais usadas para as menos. Os nomes são comparados em memória: o volume de
+// tags é pequeno e a distância de edição não tem equivalente portável em SQL.
+func (r *tagRepository) Autocomplete(q string, limit int) ([]TagMatch, error) {
+	query := validators.NormalizeTitle(q)
+	if query == "" {
+		return []TagMatch{}, nil
+	}
+
+	var titles []struct {
+		ID              uint
+		NormalizedTitle string
+	}
+	if err := r.db.Model(&models.Tag{}).Select("id", "normalized_title").Scan(&titles).Error; err != nil {
+		return nil, mapError(err)
+	}
+	var aliases []models.TagAlias
+	if err := r.db.Select("tag_id", "alias", "normalized_alias").Find(&aliases).Error; err != nil {
+		return nil, mapError(err)
+	}
+
+	best := make(map[uint]int)
+	matchedAlias := make(map[uint]string)
+	for _, t := range titles {
+		if m := matchName(t.NormalizedTitle, query); m < noMatch {
+			best[t.ID] = m
+		}
+	}
+	for _, a := range aliases {
+		m := matchName(a.NormalizedAlias, query)
+		if current, ok := best[a.TagID]; m < noMatch && (!ok || m < current) {
+			best[a.TagID] = m
+			matchedAlias[a.TagID] = a.Alias
+		}
+	}
+	if len(best) == 0 {
+		return []TagMatch{}, nil
+	}
+
+	ids := slices.Collect(maps.Keys(best))
+	tags, err := r.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := tagKind.usage(r.db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]TagMatch, 0, len(tags))
+	for _, t := range tags {
+		matches = append(matches, TagMatch{Tag: t, Alias: matchedAlias[t.ID], Usage: usage[t.ID]})
+	}
+	slices.SortFunc(matches, func(a, b TagMatch) int {
+		return cmp.Or(
+			cmp.Compare(best[a.Tag.ID], best[b.Tag.ID]),
+			cmp.Compare(b.Usage.Posts+b.Usage.Projects, a.Usage.Posts+a.Usage.Projects),
+			strings.Compare(a.Tag.Title, b.Tag.Title),
+		)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// name e query já normalizados (validators.NormalizeTitle)
+func matchName(name, query string) int {
+	if strings.HasPrefix(name, query) {
+		return matchPrefix
+	}
+	if strings.Contains(name, " "+query) {
+		return matchWord
+	}
+	typos := allowedTypos(query)
+	if typos == 0 {
+		return noMatch
+	}
+	size := len([]rune(query))
+	for _, word := range strings.Fields(name) {
+		prefix := []rune(word)
+		if len(prefix) > size {
+			prefix = prefix[:size]
+		}
+		if search.EditDistance(query, string(prefix)) <= typos {
+			return matchFuzzy
+		}
+	}
+	return noMatch
+}
+
+// Textos curtos demais casariam com quase tudo se aceitassem erros
+func allowedTypos(query string) int {
+	switch n := len([]rune(query)); {
+	case n < 3:
+		return 0
+	case n < 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Palavras-chave de text por TF-IDF contra os documentos de busca (posts e
+// projetos) e as tags cujo título ou alias aparece no texto, pelo mesmo peso.
+// As tags de exclude (as que o item já tem) ficam de fora. Só são consideradas
+// as tags cujo nome contém um dos termos candidatos, e a frequência no acervo
+// de todos os termos sai de uma única consulta agrupada.
+func (r *tagRepository) Suggest(text string, exclude []uint, limit int) (TagSuggestions, error) {
+	res := TagSuggestions{Keywords: []Keyword{}, Tags: []TagSuggestion{}}
+	tokens := search.Tokens(text)
+	if len(tokens) == 0 {
+		return res, nil
+	}
+
+	counts := make(map[string]int)
+	for _, t := range tokens {
+		if !search.IsStopword(t) {
+			counts[t]++
+		}
+	}
+	terms := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+	if len(terms) > keywordCandidates {
+		terms = terms[:keywordCandidates]
+	}
+	if len(terms) == 0 {
+		return res, nil
+	}
+
+	tags, names, err := r.namesContaining(terms, exclude)
+	if err != nil {
+		return res, err
+	}
+	// Nome da tag que aparece no texto, já em tokens, com o número de ocorrências
+	type phraseMatch struct {
+		name   string
+		phrase []string
+		count  int
+	}
+	matches := make(map[uint][]phraseMatch)
+	lookup := slices.Clone(terms)
+	for _, tag := range tags {
+		for _, name := range append([]string{tag.Title}, names[tag.ID]...) {
+			phrase := search.Tokens(name)
+			if len(phrase) == 0 || len(phrase) == 1 && search.IsStopword(phrase[0]) {
+				continue
+			}
+			if n := countPhrase(tokens, phrase); n > 0 {
+				matches[tag.ID] = append(matches[tag.ID], phraseMatch{name, phrase, n})
+				lookup = append(lookup, phrase...)
+			}
+		}
+	}
+
+	var docs int64
+	if err := r.db.Model(&models.SearchDocument{}).Count(&docs).Error; err != nil {
+		return res, mapError(err)
+	}
+	docFreq, err := r.documentFrequencies(lookup)
+	if err != nil {
+		return res, err
+	}
+
+	for _, term := range terms {
+		res.Keywords = append(res.Keywords, Keyword{Term: term, Score: search.TFIDF(counts[term], len(tokens), docFreq[term], docs)})
+	}
+	slices.SortStableFunc(res.Keywords, func(a, b Keyword) int { return cmp.Compare(b.Score, a.Score) })
+	if limit > 0 && len(res.Keywords) > limit {
+		res.Keywords = res.Keywords[:limit]
+	}
+
+	for _, tag := range tags {
+		var best *TagSuggestion
+		for _, m := range matches[tag.ID] {
+			score := search.TFIDF(m.count, len(tokens), phraseFrequency(m.phrase, docFreq), docs)
+			if best == nil || score > best.Score {
+				best = &TagSuggestion{Tag: tag, Keyword: m.name, Score: score}
+			}
+		}
+		if best != nil {
+			res.Tags = append(res.Tags, *best)
+		}
+	}
+	slices.SortStableFunc(res.Tags, func(a, b TagSuggestion) int { return cmp.Compare(b.Score, a.Score) })
+	if limit > 0 && len(res.Tags) > limit {
+		res.Tags = res.Tags[:limit]
+	}
+	return res, nil
+}
+
+// Tags (fora de exclude) cujo título ou algum alias normalizado contém um dos
+// termos, com os aliases de cada uma. É só um filtro: quem decide se o nome
+// aparece no texto é countPhrase.
+func (r *tagRepository) namesContaining(terms []string, exclude []uint) ([]models.Tag, map[uint][]string, error) {
+	patterns := make([]any, len(terms))
+	for i, term := range terms {
+		patterns[i] = "%" + term + "%"
+	}
+	anyTerm := func(column string) string {
+		return "(" + strings.Repeat(column+" LIKE ? OR ", len(terms)-1) + column + " LIKE ?)"
+	}
+
+	var ids []uint
+	if err := r.db.Model(&models.Tag{}).Where(anyTerm("normalized_title"), patterns...).Pluck("id", &ids).Error; err != nil {
+		return nil, nil, mapError(err)
+	}
+	var aliasIDs []uint
+	if err := r.db.Model(&models.TagAlias{}).Where(anyTerm("normalized_alias"), patterns...).Distinct().Pluck("tag_id", &aliasIDs).Error; err != nil {
+		return nil, nil, mapError(err)
+	}
+	ids = slices.DeleteFunc(append(ids, aliasIDs...), func(id uint) bool { return slices.Contains(exclude, id) })
+	if len(ids) == 0 {
+		return nil, nil, nil
+	}
+
+	var tags []models.Tag
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&tags).Error; err != nil {
+		return nil, nil, mapError(err)
+	}
+	var all []models.TagAlias
+	if err := r.db.Where("tag_id IN ?", ids).Order("id").Find(&all).Error; err != nil {
+		return nil, nil, mapError(err)
+	}
+	names := make(map[uint][]string, len(tags))
+	for _, a := range all {
+		names[a.TagID] = append(names[a.TagID], a.Alias)
+	}
+	return tags, names, nil
+}
+
+// Em quantos documentos de busca cada termo aparece, por igualdade exata
+func (r *tagRepository) documentFrequencies(terms []string) (map[string]int64, error) {
+	var rows []struct {
+		Term string
+		N    int64
+	}
+	err := r.db.Model(&models.SearchTerm{}).Select("term, COUNT(*) AS n").
+		Where("term IN ?", terms).Group("term").Scan(&rows).Error
+	if err != nil {
+		return nil, mapError(err)
+	}
+	freq := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		freq[row.Term] = row.N
+	}
+	return freq, nil
+}
+
+// Frequência de um nome de várias palavras: a da palavra mais rara, que limita
+// em quantos documentos ele pode aparecer. Stopwords não são indexadas e ficam de fora.
+func phraseFrequency(phrase []string, docFreq map[string]int64) int64 {
+	freq := int64(-1)
+	for _, word := range phrase {
+		if search.IsStopword(word) {
+			continue
+		}
+		if n := docFreq[word]; freq < 0 || n < freq {
+			freq = n
+		}
+	}
+	return max(freq, 0)
+}
+
+// Ocorrências da sequência phrase em tokens
+func countPhrase(tokens, phrase []string) int {
+	n := 0
+	for i := 0; i+len(phrase) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(phrase)], phrase) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/validators"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Quantos posts e projetos publicados usam uma tag/categoria
+type TaxonomyUsage struct {
+	Posts    int64
+	Projects int64
+}
+
+// Tabela de junção de uma taxonomia com um tipo de conteúdo
+type taxonomyJoin struct {
+	table        string // post_tags, project_categories...
+	fk           string // post_id / project_id
+	contentTable string // posts / projects
+}
+
+// Onde cada taxonomia aparece: tabela própria, coluna nas junções e junções
+type taxonomyKind struct {
+	table       string // tags / categories
+	contentType string // Tipo no histórico de slugs
+	column      string // tag_id / category_id
+	joins       [2]taxonomyJoin
+	tree        bool // Categorias: filhas das removidas sobem um nível
+	// Campo correspondente em ContentDraft, para não deixar rascunhos apontando para ids removidos
+	draftIDs func(d *models.ContentDraft) *[]uint
+}
+
+var (
+	tagKind = taxonomyKind{
+		table:       "tags",
+		contentType: contentTypeTag,
+		column:      "tag_id",
+		joins: [2]taxonomyJoin{
+			{table: "post_tags", fk: "post_id", contentTable: "posts"},
+			{table: "project_tags", fk: "project_id", contentTable: "projects"},
+		},
+		draftIDs: func(d *models.ContentDraft) *[]uint { return &d.TagIDs },
+	}
+	categoryKind = taxonomyKind{
+		table:       "categories",
+		contentType: contentTypeCategory,
+		column:      "category_id",
+		tree:        true,
+		joins: [2]taxonomyJoin{
+			{table: "post_categories", fk: "post_id", contentTable: "posts"},
+			{table: "project_categories", fk: "project_id", contentTable: "projects"},
+		},
+		draftIDs: func(d *models.ContentDraft) *[]uint { return &d.CategoryIDs },
+	}
+)
+
+// Antes de salvar: slug alterado vai para o histórico (vira redirecionamento) e o
+// slug novo deixa de redirecionar para qualquer outro item
+func (k taxonomyKind) trackSlug(tx *gorm.DB, id uint, slug string) error {
+	var current []string
+	if err := tx.Table(k.table).Where("id = ?", id).Pluck("slug", &current).Error; err != nil {
+		return mapError(err)
+	}
+	if len(current) == 0 {
+		return ErrNotFound // Evita que o Save insira um item novo
+	}
+	if current[0] != "" && current[0] != slug {
+		if err := recordSlugHistory(tx, k.contentType, id, current[0]); err != nil {
+			return err
+		}
+	}
+	return releaseSlugHistory(tx, k.contentType, slug)
+}
+
+// Slug único gerado do título, ignorando o próprio item
+func (k taxonomyKind) slugFor(tx *gorm.DB, title string, id uint) (string, error) {
+	return validators.UniqueSlug(validators.GenerateSlug(title), func(slug string) (bool, error) {
+		return k.slugExists(tx, slug, id)
+	})
+}
+
+func (k taxonomyKind) slugExists(db *gorm.DB, slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := db.Table(k.table).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	return count > 0, mapError(err)
+}
+
+// ID do item que usava o slug, para redirecionar; ErrNotFound se nunca existiu
+func (k taxonomyKind) redirectTarget(db *gorm.DB, slug string) (string, error) {
+	id, err := findSlugHistory(db, k.contentType, slug)
+	if err != nil {
+		return "", err
+	}
+	var current []string
+	if err := db.Table(k.table).Where("id = ?", id).Pluck("slug", &current).Error; err != nil {
+		return "", mapError(err)
+	}
+	if len(current) == 0 {
+		return "", ErrNotFound
+	}
+	return current[0], nil
+}
+
+// Garante que todos os ids existem; caso contrário ErrNotFound (inclusive para id 0)
+func (k taxonomyKind) ensureExists(tx *gorm.DB, ids []uint) error {
+	if slices.Contains(ids, 0) {
+		return ErrNotFound
+	}
+	ids = uniqueIDs(ids)
+	var found int64
+	if err := tx.Table(k.table).Where("id IN ?", ids).Count(&found).Error; err != nil {
+		return mapError(err)
+	}
+	if found != int64(len(ids)) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Move as associações de sourceIDs para targetID (sem duplicar itens que já
+// tinham o destino) e remove as origens. Deve rodar dentro de uma transação.
+func (k taxonomyKind) merge(tx *gorm.DB, sourceIDs []uint, targetID uint) error {
+	// Destino entre as origens seria removido junto com elas
+	if targetID == 0 || len(sourceIDs) == 0 || slices.Contains(sourceIDs, targetID) {
+		return ErrInvalidReference
+	}
+	if err := k.ensureExists(tx, append(slices.Clone(sourceIDs), targetID)); err != nil {
+		return err
+	}
+	for _, j := range k.joins {
+		err := tx.Exec(
+			"INSERT INTO "+j.table+" ("+j.fk+", "+k.column+") SELECT DISTINCT "+j.fk+", ? FROM "+j.table+
+				" WHERE "+k.column+" IN ? AND "+j.fk+" NOT IN (SELECT "+j.fk+" FROM "+j.table+" WHERE "+k.column+" = ?)",
+			targetID, sourceIDs, targetID,
+		).Error
+		if err != nil {
+			return mapError(err)
+		}
+	}
+	return k.remove(tx, sourceIDs, targetID)
+}
+
+// Apaga as linhas de junção e os registros de ids; nos rascunhos e no histórico
+// de slugs, as referências viram replacement (0 apenas remove)
+func (k taxonomyKind) remove(tx *gorm.DB, ids []uint, replacement uint) error {
+	for _, j := range k.joins {
+		if err := tx.Exec("DELETE FROM "+j.table+" WHERE "+k.column+" IN ?", ids).Error; err != nil {
+			return mapError(err)
+		}
+	}
+	if err := k.rewriteDrafts(tx, ids, replacement); err != nil {
+		return err
+	}
+	if err := k.redirectSlugs(tx, ids, replacement); err != nil {
+		return err
+	}
+	if k.tree {
+		if err := liftChildren(tx, ids); err != nil {
+			return err
+		}
+	}
+	return mapError(tx.Exec("DELETE FROM "+k.table+" WHERE id IN ?", ids).Error)
+}
+
+// Com replacement, os slugs removidos redirecionam para ele; sem, o histórico some
+func (k taxonomyKind) redirectSlugs(tx *gorm.DB, ids []uint, replacement uint) error {
+	if replacement == 0 {
+		return purgeSlugHistory(tx, k.contentType, ids)
+	}
+	var slugs []string
+	if err := tx.Table(k.table).Where("id IN ?", ids).Pluck("slug", &slugs).Error; err != nil {
+		return mapError(err)
+	}
+	return redirectSlugHistory(tx, k.contentType, ids, replacement, slugs)
+}
+
+// Os ids ficam serializados em JSON, então a troca é feita em Go
+func (k taxonomyKind) rewriteDrafts(tx *gorm.DB, ids []uint, replacement uint) error {
+	var drafts []models.ContentDraft
+	if err := tx.Find(&drafts).Error; err != nil {
+		return mapError(err)
+	}
+	for i := range drafts {
+		field := k.draftIDs(&drafts[i])
+		rewritten := slices.DeleteFunc(slices.Clone(*field), func(id uint) bool { return slices.Contains(ids, id) })
+		if len(rewritten) == len(*field) {
+			continue
+		}
+		if replacement > 0 && !slices.Contains(rewritten, replacement) {
+			rewritten = append(rewritten, replacement)
+		}
+		*field = rewritten
+		if err := tx.Save(&drafts[i]).Error; err != nil {
+			return mapError(err)
+		}
+	}
+	return nil
+}
+
+// Contagem de uso por id, considerando só conteúdo visível ao público. Ids sem
+// uso ficam com zero.
+func (k taxonomyKind) usage(db *gorm.DB, ids []uint) (map[uint]TaxonomyUsage, error) {
+	usage := make(map[uint]TaxonomyUsage, len(ids))
+	for _, id := range ids {
+		usage[id] = TaxonomyUsage{}
+	}
+	if len(ids) == 0 {
+		return usage, nil
+	}
+
+	for i, j := range k.joins {
+		var rows []struct {
+			ID    uint
+			Count int64
+		}
+		err := db.Table(j.table).
+			Select(j.table+"."+k.column+" AS id, COUNT(*) AS count").
+			Joins("JOIN "+j.contentTable+" ON "+j.contentTable+".id = "+j.table+"."+j.fk+" AND "+j.contentTable+".deleted_at IS NULL").
+			Where(j.table+"."+k.column+" IN ?", ids).
+			Where(visibleCondition(j.contentTable)).
+			Group(j.table + "." + k.column).
+			Scan(&rows).Error
+		if err != nil {
+			return nil, mapError(err)
+		}
+		for _, row := range rows {
+			u := usage[row.ID]
+			if i == 0 {
+				u.Posts = row.Count
+			} else {
+				u.Projects = row.Count
+			}
+			usage[row.ID] = u
+		}
+	}
+	return usage, nil
+}
+
+// Ids de posts e projetos ligados a alguma das taxonomias
+func (k taxonomyKind) contentIDs(tx *gorm.DB, ids []uint) (postIDs, projectIDs []uint, err error) {
+	if err := tx.Table(k.joins[0].table).Where(k.column+" IN ?", ids).Distinct().Pluck(k.joins[0].fk, &postIDs).Error; err != nil {
+		return nil, nil, mapError(err)
+	}
+	if err := tx.Table(k.joins[1].table).Where(k.column+" IN ?", ids).Distinct().Pluck(k.joins[1].fk, &projectIDs).Error; err != nil {
+		return nil, nil, mapError(err)
+	}
+	return postIDs, projectIDs, nil
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	WithContext(ctx context.Context) WebhookRepository
+	FindSubscriptions() ([]models.WebhookSubscription, error)
+	CreateSubscription(sub *models.WebhookSubscription) error
+	DeleteSubscription(id uint) error
+	Enqueue(e events.Event) error
+	FindDue(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	MarkDelivered(id uint, at time.Time) error
+	MarkRetry(id uint, attempts int, next time.Time, lastErr string) error
+	MarkFailed(id uint, attempts int, at time.Time, lastErr string) error
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) WithContext(ctx context.Context) WebhookRepository {
+	return &webhookRepository{db: r.db.WithContext(ctx)}
+}
+
+func (r *webhookRepository) FindSubscriptions() ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.Order("id asc").Find(&subs).Error
+	return subs, mapError(err)
+}
+
+func (r *webhookRepository) CreateSubscription(sub *models.WebhookSubscription) error {
+	return mapError(r.db.Create(sub).Error)
+}
+
+// Remove a assinatura junto com todas as entregas dela, inclusive as já feitas,
+// que a referenciam por chave estrangeira
+func (r *webhookRepository) DeleteSubscription(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return mapError(err)
+		}
+		return affectedOrNotFound(tx.Delete(&models.WebhookSubscription{}, id))
+	})
+}
+
+// Grava uma entrega por assinatura interessada. Chamado com o *gorm.DB da transação
+// da mudança: se ela sofrer rollback, nenhum webhook é disparado.
+func (r *webhookRepository) Enqueue(e events.Event) error {
+	var subs []models.WebhookSubscription
+	if err := r.db.Where("active = ?", true).Find(&subs).Error; err != nil {
+		return mapError(err)
+	}
+
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, sub := range subs {
+		if !subscribes(sub, e.Type) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventType:      string(e.Type),
+			Payload:        string(payload),
+			NextAttemptAt:  e.OccurredAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return mapError(r.db.Create(&deliveries).Error)
+}
+
+// Entregas pendentes cuja próxima tentativa já venceu, das mais antigas para as mais novas
+func (r *webhookRepository) FindDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Preload("Subscription").
+		Where("delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now).
+		Order("next_attempt_at asc, id asc").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, mapError(err)
+}
+
+func (r *webhookRepository) MarkDelivered(id uint, at time.Time) error {
+	return affectedOrNotFound(r.db.Model(&models.WebhookDelivery{}).Where("id = ?", id).
+		Updates(map[string]any{"delivered_at": at, "attempts": gorm.Expr("attempts + 1"), "last_error": ""}))
+}
+
+func (r *webhookRepository) MarkRetry(id uint, attempts int, next time.Time, lastErr string) error {
+	return affectedOrNotFound(r.db.Model(&models.WebhookDelivery{}).Where("id = ?", id).
+		Updates(map[string]any{"attempts": attempts, "next_attempt_at": next, "last_error": lastErr}))
+}
+
+func (r *webhookRepository) MarkFailed(id uint, attempts int, at time.Time, lastErr string) error {
+	return affectedOrNotFound(r.db.Model(&models.WebhookDelivery{}).Where("id = ?", id).
+		Updates(map[string]any{"attempts": attempts, "failed_at": at, "last_error": lastErr}))
+}
+
+// Lista vazia de eventos significa "todos"
+func subscribes(sub models.WebhookSubscription, t events.Type) bool {
+	if strings.TrimSpace(sub.Events) == "" {
+		return true
+	}
+	return slices.Contains(strings.Split(sub.Events, ","), string(t))
+}
@@ -0,0 +1,151 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Marcação em volta dos termos encontrados; o restante do texto sai escapado
+const (
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+	ellipsis  = "…"
+)
+
+// Trecho de texto puro: palavra (letras/dígitos) ou separador
+type segment struct {
+	text   string
+	isWord bool
+}
+
+// Texto escapado para HTML com as palavras que começam por algum termo de q
+// entre <mark>. Acentos e caixa são ignorados, como no índice.
+func Highlight(text, q string) string {
+	return render(split(text), normalizedTerms(q))
+}
+
+// Se alguma palavra de text começa por um termo de q
+func Matches(text, q string) bool {
+	terms := normalizedTerms(q)
+	for _, s := range split(text) {
+		if s.isWord && matches(s.text, terms) {
+			return true
+		}
+	}
+	return false
+}
+
+// Janela de até maxRunes runas em volta da primeira ocorrência, com reticências
+// nas pontas cortadas. Sem ocorrência (ex.: casou por radical no Postgres), usa o início.
+func Snippet(text, q string, maxRunes int) string {
+	segments := split(text)
+	terms := normalizedTerms(q)
+
+	first := 0
+	for i, s := range segments {
+		if s.isWord && matches(s.text, terms) {
+			first = i
+			break
+		}
+	}
+
+	// Recua até um terço da janela para dar contexto antes da ocorrência
+	start, size := first, 0
+	for start > 0 && size+runeLen(segments[start-1].text) <= maxRunes/3 {
+		start--
+		size += runeLen(segments[start].text)
+	}
+	for start < first && !segments[start].isWord {
+		start++
+	}
+
+	end, size := start, 0
+	for end < len(segments) && size+runeLen(segments[end].text) <= maxRunes {
+		size += runeLen(segments[end].text)
+		end++
+	}
+	if end == start && end < len(segments) {
+		end++ // Palavra maior que a janela inteira
+	}
+	for end > start+1 && !segments[end-1].isWord {
+		end--
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	b.WriteString(render(segments[start:end], terms))
+	if end < len(segments) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+func render(segments []segment, terms []string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		if s.isWord && matches(s.text, terms) {
+			b.WriteString(markOpen + html.EscapeString(s.text) + markClose)
+			continue
+		}
+		b.WriteString(html.EscapeString(s.text))
+	}
+	return b.String()
+}
+
+func split(text string) []segment {
+	var segments []segment
+	var current []rune
+	inWord := false
+	for _, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if len(current) > 0 && word != inWord {
+			segments = append(segments, segment{text: string(current), isWord: inWord})
+			current = current[:0]
+		}
+		current = append(current, r)
+		inWord = word
+	}
+	if len(current) > 0 {
+		segments = append(segments, segment{text: string(current), isWord: inWord})
+	}
+	return segments
+}
+
+func normalizedTerms(q string) []string {
+	parts := terms(q)
+	for i, t := range parts {
+		parts[i] = fold(t)
+	}
+	return parts
+}
+
+// Busca por prefixo, como no FTS5
+func matches(word string, terms []string) bool {
+	folded := fold(word)
+	for _, t := range terms {
+		if strings.HasPrefix(folded, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Minúsculas e sem acentos
+func fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,42 @@
+package search_test
+
+import (
+	"cms-headless/internal/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlight(t *testing.T) {
+	t.Run("Deve marcar palavras por prefixo ignorando acentos e caixa", func(t *testing.T) {
+		got := search.Highlight("Concorrência em Go com canais", "concorrencia CANA")
+		assert.Equal(t, "<mark>Concorrência</mark> em Go com <mark>canais</mark>", got)
+	})
+
+	t.Run("Deve escapar o HTML do texto", func(t *testing.T) {
+		got := search.Highlight("<script>go</script>", "go")
+		assert.Equal(t, "&lt;script&gt;<mark>go</mark>&lt;/script&gt;", got)
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	text := "Primeiro parágrafo introdutório bem longo sobre outras coisas. Depois falamos de goroutines e canais. Fim do texto."
+
+	t.Run("Deve recortar em volta da primeira ocorrência", func(t *testing.T) {
+		got := search.Snippet(text, "goroutines", 40)
+
+		assert.Contains(t, got, "<mark>goroutines</mark>")
+		assert.True(t, len([]rune(got)) < len([]rune(text)))
+		assert.Equal(t, "…", string([]rune(got)[:1]))
+		assert.Equal(t, "…", string([]rune(got)[len([]rune(got))-1:]))
+	})
+
+	t.Run("Sem ocorrência usa o início do texto", func(t *testing.T) {
+		got := search.Snippet(text, "inexistente", 20)
+		assert.Equal(t, "Primeiro parágrafo…", got)
+	})
+
+	t.Run("Texto curto sai inteiro", func(t *testing.T) {
+		assert.Equal(t, "sobre <mark>Go</mark>", search.Snippet("sobre Go", "go", 160))
+	})
+}
@@ -0,0 +1,76 @@
+package search
+
+import (
+	"math"
+)
+
+// Palavras comuns demais em português e inglês para servir de palavra-chave
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range []string{
+		// Português, já sem acentos como em Tokens
+		"a", "ao", "aos", "as", "ate", "com", "como", "da", "das", "de", "dela", "dele", "do", "dos",
+		"e", "ela", "ele", "em", "entre", "era", "essa", "esse", "esta", "este", "eu", "foi", "ha",
+		"isso", "isto", "ja", "la", "mais", "mas", "me", "mesmo", "muito", "na", "nas", "nao", "no",
+		"nos", "num", "numa", "o", "os", "ou", "para", "pela", "pelas", "pelo", "pelos", "por",
+		"pra", "qual", "quando", "que", "se", "sem", "ser", "seu", "sua", "sao", "tambem", "tem",
+		"um", "uma", "umas", "uns", "vai", "voce",
+		// Inglês
+		"an", "and", "are", "as", "at", "be", "but", "by", "for", "from", "has", "have", "how", "if",
+		"in", "into", "is", "it", "its", "not", "of", "on", "or", "that", "the", "their", "this",
+		"to", "was", "we", "what", "when", "which", "will", "with", "you", "your",
+	} {
+		stopwords[w] = true
+	}
+}
+
+// Palavras de text em minúsculas e sem acentos, na ordem em que aparecem
+func Tokens(text string) []string {
+	var tokens []string
+	for _, s := range split(text) {
+		if s.isWord {
+			tokens = append(tokens, fold(s.text))
+		}
+	}
+	return tokens
+}
+
+// Se o token não serve como palavra-chave: stopword ou uma letra só
+func IsStopword(token string) bool {
+	return stopwords[token] || runeLen(token) < 2
+}
+
+// Peso de um termo que aparece count vezes num texto de total palavras e em
+// docFreq dos docs documentos do acervo. O idf é suavizado: termo ausente do
+// acervo não divide por zero e termo presente em todos ainda pesa um pouco.
+func TFIDF(count, total int, docFreq, docs int64) float64 {
+	if count == 0 || total == 0 {
+		return 0
+	}
+	tf := float64(count) / float64(total)
+	idf := math.Log(float64(docs+1)/float64(docFreq+1)) + 1
+	return tf * idf
+}
+
+// Distância de Levenshtein entre a e b, contada em runas
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
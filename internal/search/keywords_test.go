@@ -0,0 +1,47 @@
+package search_test
+
+import (
+	"cms-headless/internal/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokens(t *testing.T) {
+	t.Run("Deve separar palavras em minúsculas e sem acentos", func(t *testing.T) {
+		got := search.Tokens("Concorrência em <b>Go</b>: canais, goroutines!")
+		assert.Equal(t, []string{"concorrencia", "em", "b", "go", "b", "canais", "goroutines"}, got)
+	})
+
+	t.Run("Deve ignorar stopwords e letras soltas como palavra-chave", func(t *testing.T) {
+		assert.True(t, search.IsStopword("nao"))
+		assert.True(t, search.IsStopword("the"))
+		assert.True(t, search.IsStopword("x"))
+		assert.False(t, search.IsStopword("go"))
+	})
+}
+
+func TestTFIDF(t *testing.T) {
+	t.Run("Deve pesar mais termos raros no acervo", func(t *testing.T) {
+		rare := search.TFIDF(2, 100, 1, 50)
+		common := search.TFIDF(2, 100, 50, 50)
+		assert.Greater(t, rare, common)
+		assert.Greater(t, common, 0.0, "termo presente em todos os documentos ainda pesa")
+	})
+
+	t.Run("Deve pesar mais termos frequentes no texto", func(t *testing.T) {
+		assert.Greater(t, search.TFIDF(5, 100, 3, 50), search.TFIDF(1, 100, 3, 50))
+		assert.Zero(t, search.TFIDF(0, 100, 3, 50))
+		assert.Zero(t, search.TFIDF(1, 0, 3, 50))
+	})
+}
+
+func TestEditDistance(t *testing.T) {
+	t.Run("Deve contar inserções, remoções e trocas por runa", func(t *testing.T) {
+		assert.Equal(t, 0, search.EditDistance("go", "go"))
+		assert.Equal(t, 1, search.EditDistance("kubernets", "kubernetes"))
+		assert.Equal(t, 2, search.EditDistance("golnag", "golang"))
+		assert.Equal(t, 1, search.EditDistance("ação", "acão"))
+		assert.Equal(t, 3, search.EditDistance("", "abc"))
+	})
+}
@@ -0,0 +1,131 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Estratégias de busca, escolhidas em Setup conforme o banco
+const (
+	BackendPostgres = "postgres" // tsvector + GIN, configurações portuguese e english
+	BackendFTS5     = "fts5"     // Tabela virtual FTS5 do SQLite
+	BackendLike     = "like"     // SQLite compilado sem FTS5: LIKE sobre search_documents
+)
+
+const pluginName = "search"
+
+// Guarda no *gorm.DB o backend detectado; sessões e transações derivadas o herdam
+type plugin struct {
+	backend string
+}
+
+func (p *plugin) Name() string                { return pluginName }
+func (p *plugin) Initialize(_ *gorm.DB) error { return nil }
+
+// Cria as estruturas de busca sobre search_documents (que já deve existir) e
+// registra o backend no db. Pode ser chamado a cada inicialização.
+func Setup(db *gorm.DB) (string, error) {
+	var (
+		backend string
+		err     error
+	)
+	switch db.Dialector.Name() {
+	case "postgres":
+		backend, err = BackendPostgres, setupPostgres(db)
+	case "sqlite":
+		backend, err = setupSQLite(db)
+	default:
+		backend = BackendLike
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if p, ok := db.Config.Plugins[pluginName].(*plugin); ok {
+		p.backend = backend
+		return backend, nil
+	}
+	return backend, db.Use(&plugin{backend: backend})
+}
+
+// Backend registrado por Setup (LIKE se Setup não foi chamado)
+func Backend(db *gorm.DB) string {
+	if p, ok := db.Config.Plugins[pluginName].(*plugin); ok {
+		return p.backend
+	}
+	return BackendLike
+}
+
+// Restringe a consulta de table aos itens de contentType que casam com q
+func Filter(table, contentType, q string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN search_documents ON search_documents.content_type = ? AND search_documents.content_id = "+table+".id", contentType).
+			Scopes(Match(q))
+	}
+}
+
+// Condição de busca para consultas que já incluem search_documents (ex.: busca
+// unificada, que parte dela)
+func Match(q string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch Backend(db) {
+		case BackendPostgres:
+			return db.Where("search_documents.document @@ "+tsQuery, q, q)
+		case BackendFTS5:
+			match := ftsQuery(q)
+			if match == "" {
+				return db.Where("1 = 0")
+			}
+			return db.Joins("JOIN search_documents_fts ON search_documents_fts.rowid = search_documents.rowid").
+				Where("search_documents_fts MATCH ?", match)
+		default:
+			for _, term := range terms(q) {
+				pattern := "%" + term + "%"
+				db = db.Where(
+					"(search_documents.title LIKE ? OR search_documents.short_description LIKE ? OR search_documents.body LIKE ? OR search_documents.tags LIKE ?)",
+					pattern, pattern, pattern, pattern,
+				)
+			}
+			return db
+		}
+	}
+}
+
+// Expressão de relevância para o SELECT de uma consulta com Filter; maior é
+// melhor em todos os backends
+func Rank(db *gorm.DB, q string) clause.Expr {
+	switch Backend(db) {
+	case BackendPostgres:
+		return clause.Expr{SQL: "ts_rank(search_documents.document, " + tsQuery + ")", Vars: []any{q, q}}
+	case BackendFTS5:
+		// bm25 é menor para documentos mais relevantes; pesos: título, descrição, corpo, tags
+		return clause.Expr{SQL: "-bm25(search_documents_fts, 10.0, 4.0, 1.0, 6.0)"}
+	default:
+		pattern := "%" + strings.TrimSpace(q) + "%"
+		return clause.Expr{
+			SQL: "CASE WHEN search_documents.title LIKE ? THEN 3 WHEN search_documents.tags LIKE ? THEN 2 " +
+				"WHEN search_documents.short_description LIKE ? THEN 1 ELSE 0 END",
+			Vars: []any{pattern, pattern, pattern},
+		}
+	}
+}
+
+// Termos da busca do usuário, sem pontuação
+func terms(q string) []string {
+	return strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Cada termo entre aspas (a sintaxe do FTS5 não vaza para o usuário) e com
+// busca por prefixo; termos separados por espaço são combinados com AND
+func ftsQuery(q string) string {
+	parts := terms(q)
+	for i, t := range parts {
+		parts[i] = `"` + t + `"*`
+	}
+	return strings.Join(parts, " ")
+}
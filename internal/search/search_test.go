@@ -0,0 +1,46 @@
+package search_test
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSetup(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	db.AutoMigrate(&models.Post{}, &models.SearchDocument{})
+
+	t.Run("Deve detectar o backend do SQLite e registrá-lo no db", func(t *testing.T) {
+		backend, err := search.Setup(db)
+
+		assert.NoError(t, err)
+		assert.Contains(t, []string{search.BackendFTS5, search.BackendLike}, backend)
+		assert.Equal(t, backend, search.Backend(db))
+		assert.Equal(t, backend, search.Backend(db.Session(&gorm.Session{})), "sessões derivadas herdam o backend")
+		t.Logf("backend: %s", backend)
+	})
+
+	t.Run("Deve ser idempotente", func(t *testing.T) {
+		_, err := search.Setup(db)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Pontuação da busca não vira sintaxe do banco", func(t *testing.T) {
+		now := db.NowFunc()
+		post := models.Post{Title: "Go", Slug: "go", PostedAt: &now}
+		db.Create(&post)
+		db.Create(&models.SearchDocument{ContentType: "post", ContentID: post.ID, Title: "Go e SQL"})
+
+		var count int64
+		err := db.Model(&models.Post{}).Scopes(search.Filter("posts", "post", `"sql*`)).Count(&count).Error
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		err = db.Model(&models.Post{}).Scopes(search.Filter("posts", "post", `"*-`)).Count(&count).Error
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package search
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Consulta aplicada nas duas configurações de idioma; websearch_to_tsquery aceita
+// a sintaxe usual de buscadores ("frase exata", -exclusão, OR)
+const tsQuery = "(websearch_to_tsquery('portuguese', ?) || websearch_to_tsquery('english', ?))"
+
+// Coluna gerada: o Postgres recalcula o vetor a cada escrita em search_documents.
+// Pesos: título (A), tags e descrição (B), corpo (C).
+var postgresStatements = []string{
+	`ALTER TABLE search_documents ADD COLUMN IF NOT EXISTS document tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('portuguese', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('portuguese', coalesce(tags, '') || ' ' || coalesce(short_description, '')), 'B') ||
+		setweight(to_tsvector('english', coalesce(tags, '') || ' ' || coalesce(short_description, '')), 'B') ||
+		setweight(to_tsvector('portuguese', coalesce(body, '')), 'C') ||
+		setweight(to_tsvector('english', coalesce(body, '')), 'C')
+	) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_search_documents_document ON search_documents USING GIN (document)`,
+}
+
+func setupPostgres(db *gorm.DB) error {
+	for _, stmt := range postgresStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tabela FTS5 de conteúdo externo: o texto fica só em search_documents e os
+// triggers mantêm o índice invertido em sincronia
+const ftsTable = `CREATE VIRTUAL TABLE search_documents_fts USING fts5(
+	title, short_description, body, tags,
+	content='search_documents', tokenize='unicode61 remove_diacritics 2'
+)`
+
+var ftsTriggers = []string{
+	`CREATE TRIGGER IF NOT EXISTS search_documents_ai AFTER INSERT ON search_documents BEGIN
+		INSERT INTO search_documents_fts(rowid, title, short_description, body, tags)
+		VALUES (new.rowid, new.title, new.short_description, new.body, new.tags);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS search_documents_ad AFTER DELETE ON search_documents BEGIN
+		INSERT INTO search_documents_fts(search_documents_fts, rowid, title, short_description, body, tags)
+		VALUES ('delete', old.rowid, old.title, old.short_description, old.body, old.tags);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS search_documents_au AFTER UPDATE ON search_documents BEGIN
+		INSERT INTO search_documents_fts(search_documents_fts, rowid, title, short_description, body, tags)
+		VALUES ('delete', old.rowid, old.title, old.short_description, old.body, old.tags);
+		INSERT INTO search_documents_fts(rowid, title, short_description, body, tags)
+		VALUES (new.rowid, new.title, new.short_description, new.body, new.tags);
+	END`,
+}
+
+// O mattn/go-sqlite3 só inclui FTS5 com a build tag sqlite_fts5; sem ela caímos no LIKE
+func setupSQLite(db *gorm.DB) (string, error) {
+	if !db.Migrator().HasTable("search_documents_fts") {
+		// Sem log: a falha é esperada quando o FTS5 não está disponível
+		probe := db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)})
+		if err := probe.Exec(ftsTable).Error; err != nil {
+			if strings.Contains(err.Error(), "no such module") {
+				return BackendLike, nil
+			}
+			return "", err
+		}
+		// Indexa o que já existia em search_documents antes da tabela virtual
+		if err := db.Exec("INSERT INTO search_documents_fts(search_documents_fts) VALUES ('rebuild')").Error; err != nil {
+			return "", err
+		}
+	}
+	for _, stmt := range ftsTriggers {
+		if err := db.Exec(stmt).Error; err != nil {
+			return "", err
+		}
+	}
+	return BackendFTS5, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Publicar/descartar sem nenhuma edição pendente
+var ErrNoPendingChanges = errors.New("não há alterações pendentes")
+
+// Conteúdo no ar não é alterado direto: as edições vão para o rascunho até serem publicadas.
+// Depois de criado, o rascunho é usado até ser publicado ou descartado, mesmo que o item saia do ar.
+func editsGoToDraft(status string, postedAt, unpublishAt *time.Time, draft *models.ContentDraft) bool {
+	return draft != nil || isVisible(status, postedAt, unpublishAt, time.Now().UTC())
+}
+
+// Rascunho do conteúdo, ou nil se não houver
+func findDraft(tx *gorm.DB, contentType string, id uint) (*models.ContentDraft, error) {
+	draft, err := repositories.NewDraftRepository(tx).Find(contentType, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, nil
+	}
+	return draft, err
+}
+
+func saveDraft(ctx context.Context, tx *gorm.DB, draft *models.ContentDraft) error {
+	if p, ok := auth.FromContext(ctx); ok {
+		draft.Author = p.Name
+	}
+	return repositories.NewDraftRepository(tx).Save(draft)
+}
+
+// Tags/categorias do rascunho; as apagadas desde então são ignoradas
+func loadDraftTaxonomies(tx *gorm.DB, tagIDs, categoryIDs []uint) ([]models.Tag, []models.Category, error) {
+	tags, err := repositories.NewTagRepository(tx).FindByIDs(tagIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	categories, err := repositories.NewCategoryRepository(tx).FindByIDs(categoryIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tags, categories, nil
+}
+
+// Sobrepõe o rascunho à versão no ar, formando a cópia de trabalho vista no admin
+func overlayPostDraft(tx *gorm.DB, post *models.Post, draft *models.ContentDraft) error {
+	tags, categories, err := loadDraftTaxonomies(tx, draft.TagIDs, draft.CategoryIDs)
+	if err != nil {
+		return err
+	}
+	post.Title = draft.Title
+	post.Slug = draft.Slug
+	post.ShortDescription = draft.ShortDescription
+	post.Body = draft.Body
+	post.Tags, post.Categories = tags, categories
+	post.HasDraft = true
+	return nil
+}
+
+func overlayProjectDraft(tx *gorm.DB, project *models.Project, draft *models.ContentDraft) error {
+	tags, categories, err := loadDraftTaxonomies(tx, draft.TagIDs, draft.CategoryIDs)
+	if err != nil {
+		return err
+	}
+	project.Title = draft.Title
+	project.Slug = draft.Slug
+	project.ShortDescription = draft.ShortDescription
+	project.Body = draft.Body
+	project.DemoURL = draft.DemoURL
+	project.RepoURL = draft.RepoURL
+	project.Tags, project.Categories = tags, categories
+	project.HasDraft = true
+	return nil
+}
+
+func postDraft(post *models.Post) *models.ContentDraft {
+	rev := postRevision(post)
+	return draftFromRevision(&rev)
+}
+
+func projectDraft(project *models.Project) *models.ContentDraft {
+	rev := projectRevision(project)
+	return draftFromRevision(&rev)
+}
+
+// O rascunho guarda exatamente os campos versionados nas revisões
+func draftFromRevision(rev *models.Revision) *models.ContentDraft {
+	return &models.ContentDraft{
+		ContentType:      rev.ContentType,
+		ContentID:        rev.ContentID,
+		Title:            rev.Title,
+		Slug:             rev.Slug,
+		ShortDescription: rev.ShortDescription,
+		Body:             rev.Body,
+		DemoURL:          rev.DemoURL,
+		RepoURL:          rev.RepoURL,
+		TagIDs:           rev.TagIDs,
+		CategoryIDs:      rev.CategoryIDs,
+	}
+}
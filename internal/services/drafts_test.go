@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrafts(t *testing.T) {
+	db := SetupTestDB()
+	ctx := context.Background()
+	posts := services.NewPostService(db)
+	projects := services.NewProjectService(db)
+	public := repositories.NewPostRepository(db)
+	past := time.Now().UTC().Add(-time.Hour)
+
+	tag := models.Tag{Title: "Go"}
+	db.Create(&tag)
+	db.Create(&models.WebhookSubscription{URL: "https://site.dev/revalidate", Secret: "s", Active: true})
+
+	post, _ := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "No Ar", Body: "versão publicada"})
+	approve(ctx, posts.Transition, post.ID)
+	posts.SetPostedAt(ctx, post.ID, &past)
+
+	t.Run("Rascunho de post não publicado é gravado direto", func(t *testing.T) {
+		draft, _ := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "Ideia", Body: "v1"})
+		updated, err := posts.Update(ctx, draft.ID, dtos.ContentInput{Type: "post", Title: "Ideia", Body: "v2"})
+
+		assert.NoError(t, err)
+		assert.False(t, updated.HasDraft)
+		live, _ := public.FindByID(draft.ID)
+		assert.Equal(t, "v2", live.Body)
+	})
+
+	t.Run("Edição de post publicado não altera a versão no ar", func(t *testing.T) {
+		db.Where("1 = 1").Delete(&models.WebhookDelivery{})
+		updated, err := posts.Update(ctx, post.ID, dtos.ContentInput{Type: "post", Title: "No Ar Revisado", Body: "meio pronto", TagIDs: []uint{tag.ID}})
+
+		assert.NoError(t, err)
+		assert.True(t, updated.HasDraft)
+		assert.Equal(t, "meio pronto", updated.Body)
+		assert.Equal(t, "no-ar-revisado", updated.Slug)
+		assert.Len(t, updated.Tags, 1)
+
+		live, err := public.FindBySlug("no-ar", true)
+		assert.NoError(t, err)
+		assert.Equal(t, "versão publicada", live.Body)
+		assert.Empty(t, live.Tags)
+
+		got, _ := posts.Get(ctx, post.ID)
+		assert.Equal(t, "meio pronto", got.Body, "admin lê o rascunho")
+
+		list, _, _ := posts.List(ctx, 1, 10, "")
+		for _, p := range list {
+			if p.ID == post.ID {
+				assert.True(t, p.HasDraft)
+			}
+		}
+		assert.Empty(t, outboxEvents(db), "rascunho não dispara webhooks")
+	})
+
+	t.Run("Publicar alterações promove o rascunho", func(t *testing.T) {
+		published, err := posts.PublishChanges(ctx, post.ID)
+
+		assert.NoError(t, err)
+		assert.False(t, published.HasDraft)
+
+		live, err := public.FindBySlug("no-ar-revisado", true)
+		assert.NoError(t, err)
+		assert.Equal(t, "meio pronto", live.Body)
+		assert.Len(t, live.Tags, 1)
+
+		got := outboxEvents(db)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, events.ContentUpdated, got[0].Type)
+			assert.Equal(t, "no-ar", got[0].PreviousSlug)
+		}
+
+		_, err = posts.PublishChanges(ctx, post.ID)
+		assert.ErrorIs(t, err, services.ErrNoPendingChanges)
+	})
+
+	t.Run("Descartar volta à versão no ar", func(t *testing.T) {
+		posts.ReplaceTags(ctx, post.ID, []uint{}, nil)
+
+		discarded, err := posts.DiscardChanges(ctx, post.ID)
+		assert.NoError(t, err)
+		assert.False(t, discarded.HasDraft)
+		assert.Len(t, discarded.Tags, 1)
+
+		_, err = posts.DiscardChanges(ctx, post.ID)
+		assert.ErrorIs(t, err, services.ErrNoPendingChanges)
+	})
+
+	t.Run("Conflito de slug só aparece ao publicar", func(t *testing.T) {
+		other, _ := projects.Create(ctx, dtos.ContentInput{Type: "project", Title: "Ocupado", Body: "x"})
+		project, _ := projects.Create(ctx, dtos.ContentInput{Type: "project", Title: "Livre", Body: "x"})
+		approve(ctx, projects.Transition, project.ID)
+		projects.SetPostedAt(ctx, project.ID, &past)
+
+		_, err := projects.Update(ctx, project.ID, dtos.ContentInput{Type: "project", Title: "Livre", Slug: other.Slug + "-novo", Body: "x", DemoURL: "https://demo.dev"})
+		assert.NoError(t, err)
+		db.Model(&models.Project{}).Where("id = ?", other.ID).Update("slug", other.Slug+"-novo")
+
+		_, err = projects.PublishChanges(ctx, project.ID)
+		assert.ErrorIs(t, err, repositories.ErrDuplicateSlug)
+	})
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"cms-headless/internal/repositories"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// IDs de tags/categorias informados que não existem no banco
+type InvalidReferenceError struct {
+	Field string
+	IDs   []uint
+}
+
+func (e *InvalidReferenceError) Error() string {
+	ids := make([]string, 0, len(e.IDs))
+	for _, id := range e.IDs {
+		ids = append(ids, fmt.Sprint(id))
+	}
+	return fmt.Sprintf("%s inexistentes: %s", e.Field, strings.Join(ids, ", "))
+}
+
+// Permite errors.Is(err, repositories.ErrInvalidReference) nas camadas superiores
+func (e *InvalidReferenceError) Unwrap() error {
+	return repositories.ErrInvalidReference
+}
+
+// Valor de campo rejeitado por regra de negócio (ex.: slug reservado)
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// O papel do usuário não permite a operação
+var ErrForbidden = errors.New("operação não permitida para o seu papel")
+
+// Mudança de estado não prevista no fluxo editorial
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("transição inválida: %s → %s", e.From, e.To)
+}
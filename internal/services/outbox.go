@@ -0,0 +1,23 @@
+package services
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Grava o evento no outbox de webhooks dentro da transação corrente
+func enqueueEvent(tx *gorm.DB, e events.Event) error {
+	return repositories.NewWebhookRepository(tx).Enqueue(e)
+}
+
+func postEvent(t events.Type, post *models.Post) events.Event {
+	return events.Event{Type: t, ContentType: dtos.TypePost, ContentID: post.ID, Slug: post.Slug, Title: post.Title}
+}
+
+func projectEvent(t events.Type, project *models.Project) events.Event {
+	return events.Event{Type: t, ContentType: dtos.TypeProject, ContentID: project.ID, Slug: project.Slug, Title: project.Title}
+}
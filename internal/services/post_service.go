@@ -0,0 +1,540 @@
+package services
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/utils"
+	"cms-headless/internal/validators"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Leituras e escritas do admin trabalham sobre a cópia de trabalho (versão no ar + rascunho);
+// a API pública lê direto dos repositórios e só enxerga a versão publicada.
+type PostService interface {
+	List(ctx context.Context, page, pageSize int, status string) ([]models.Post, int64, error)
+	ListPage(ctx context.Context, q utils.CursorQuery, status string) (repositories.CursorPage[models.Post], error)
+	Get(ctx context.Context, id uint) (*models.Post, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Post, error)
+	Create(ctx context.Context, in dtos.ContentInput) (*models.Post, error)
+	Update(ctx context.Context, id uint, in dtos.ContentInput) (*models.Post, error)
+	Delete(ctx context.Context, id uint) error
+	SetPostedAt(ctx context.Context, id uint, t *time.Time) (*models.Post, error)
+	SetUnpublishAt(ctx context.Context, id uint, t *time.Time) (*models.Post, error)
+	ReplaceTags(ctx context.Context, id uint, tagIDs []uint, tagNames []string) (*models.Post, error)
+	ReplaceCategories(ctx context.Context, id uint, categoryIDs []uint) (*models.Post, error)
+	Trash(ctx context.Context, page, pageSize int) ([]models.Post, int64, error)
+	Restore(ctx context.Context, id uint) (*models.Post, error)
+	Purge(ctx context.Context, id uint) error
+	RestoreRevision(ctx context.Context, id uint, number int) (*models.Post, error)
+	PublishChanges(ctx context.Context, id uint) (*models.Post, error)
+	DiscardChanges(ctx context.Context, id uint) (*models.Post, error)
+	Transition(ctx context.Context, id uint, to, note string) (*models.Post, error)
+	StateChanges(ctx context.Context, id uint) ([]models.StateChange, error)
+}
+
+type postService struct {
+	db    *gorm.DB
+	posts repositories.PostRepository
+}
+
+func NewPostService(db *gorm.DB) PostService {
+	return &postService{db: db, posts: repositories.NewPostRepository(db)}
+}
+
+// status vazio lista todos os estados
+func (s *postService) List(ctx context.Context, page, pageSize int, status string) ([]models.Post, int64, error) {
+	tx := s.db.WithContext(ctx)
+	posts, total, err := repositories.NewPostRepository(tx).FindAll(page, pageSize, false, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := overlayPostDrafts(tx, posts); err != nil {
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// Como List, mas por cursor sobre (posted_at, id); rascunhos sem data vêm no fim
+func (s *postService) ListPage(ctx context.Context, q utils.CursorQuery, status string) (repositories.CursorPage[models.Post], error) {
+	tx := s.db.WithContext(ctx)
+	page, err := repositories.NewPostRepository(tx).FindPage(q, repositories.ContentFilter{Status: status})
+	if err != nil {
+		return page, err
+	}
+	return page, overlayPostDrafts(tx, page.Items)
+}
+
+// Aplica sobre cada item da listagem o rascunho pendente, se houver
+func overlayPostDrafts(tx *gorm.DB, posts []models.Post) error {
+	ids := make([]uint, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+	drafts, err := repositories.NewDraftRepository(tx).FindByContentIDs(dtos.TypePost, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.ContentDraft, len(drafts))
+	for i := range drafts {
+		byID[drafts[i].ContentID] = &drafts[i]
+	}
+	for i := range posts {
+		if draft := byID[posts[i].ID]; draft != nil {
+			if err := overlayPostDraft(tx, &posts[i], draft); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *postService) Get(ctx context.Context, id uint) (*models.Post, error) {
+	post, _, err := loadPostWorkingCopy(s.db.WithContext(ctx), id)
+	return post, err
+}
+
+// Cópia de trabalho pelo slug no ar ou pelo do rascunho, inclusive não publicada
+// (usado pelo preview)
+func (s *postService) GetBySlug(ctx context.Context, slug string) (*models.Post, error) {
+	tx := s.db.WithContext(ctx)
+	id := uint(0)
+	live, err := repositories.NewPostRepository(tx).FindBySlug(slug, false)
+	switch {
+	case err == nil:
+		id = live.ID
+	case errors.Is(err, repositories.ErrNotFound):
+		draft, err := repositories.NewDraftRepository(tx).FindBySlug(dtos.TypePost, slug)
+		if err != nil {
+			return nil, err
+		}
+		id = draft.ContentID
+	default:
+		return nil, err
+	}
+	post, _, err := loadPostWorkingCopy(tx, id)
+	return post, err
+}
+
+// Valida as referências e grava o post com suas tags/categorias numa única transação
+func (s *postService) Create(ctx context.Context, in dtos.ContentInput) (*models.Post, error) {
+	var created *models.Post
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewPostRepository(tx)
+
+		var err error
+		if in.TagIDs, err = canonicalTagIDs(tx, in.TagIDs, in.TagNames); err != nil {
+			return err
+		}
+
+		post := &models.Post{}
+		applyPostInput(post, in)
+
+		slug, err := resolveSlug("", 0, true, in.Title, in.Slug, repo.SlugExists)
+		if err != nil {
+			return err
+		}
+		post.Slug = slug
+
+		if err := loadPostAssociations(tx, post, in.TagIDs, in.CategoryIDs); err != nil {
+			return err
+		}
+		if err := repo.Create(post); err != nil {
+			return err
+		}
+
+		created, err = repo.FindByID(post.ID)
+		if err != nil {
+			return err
+		}
+		if err := recordRevision(ctx, tx, postRevision(created)); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, postEvent(events.ContentCreated, created))
+	})
+	return created, err
+}
+
+// Tags/categorias só são substituídas quando informadas (nil mantém as atuais)
+func (s *postService) Update(ctx context.Context, id uint, in dtos.ContentInput) (*models.Post, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, repo repositories.PostRepository, post *models.Post) error {
+		titleChanged := post.Title != in.Title
+		applyPostInput(post, in)
+
+		var err error
+		if in.TagIDs, err = canonicalTagIDs(tx, in.TagIDs, in.TagNames); err != nil {
+			return err
+		}
+		post.Slug, err = resolveSlug(post.Slug, post.ID, titleChanged, in.Title, in.Slug, repo.SlugExists)
+		if err != nil {
+			return err
+		}
+		if in.TagIDs != nil {
+			if post.Tags, err = loadTags(tx, in.TagIDs); err != nil {
+				return err
+			}
+		}
+		if in.CategoryIDs != nil {
+			if post.Categories, err = loadCategories(tx, in.CategoryIDs); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *postService) Delete(ctx context.Context, id uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewPostRepository(tx)
+
+		post, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := repo.Delete(id); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, postEvent(events.ContentDeleted, post))
+	})
+}
+
+// Só conteúdo aprovado pode ser agendado; o estado passa a scheduled/published
+// conforme a data (ou volta a approved quando t é nil)
+func (s *postService) SetPostedAt(ctx context.Context, id uint, t *time.Time) (*models.Post, error) {
+	return s.reschedule(ctx, id, true, func(tx *gorm.DB, repo repositories.PostRepository, post *models.Post) error {
+		next := statusForPostedAt(t, time.Now().UTC())
+		if !slices.Contains(approvedStatuses, post.Status) {
+			return &TransitionError{From: post.Status, To: next}
+		}
+		if err := validateUnpublishAt(t, post.UnpublishAt); err != nil {
+			return err
+		}
+		if next != post.Status {
+			if err := s.setStatus(ctx, tx, repo, post, next, ""); err != nil {
+				return err
+			}
+		}
+		return repo.SetPostedAt(id, t)
+	})
+}
+
+// A retirada precisa ser posterior à publicação (quando esta estiver definida)
+func (s *postService) SetUnpublishAt(ctx context.Context, id uint, t *time.Time) (*models.Post, error) {
+	return s.reschedule(ctx, id, true, func(_ *gorm.DB, repo repositories.PostRepository, post *models.Post) error {
+		if err := validateUnpublishAt(post.PostedAt, t); err != nil {
+			return err
+		}
+		return repo.SetUnpublishAt(id, t)
+	})
+}
+
+// Muda o estado editorial manualmente (scheduled/published só via SetPostedAt).
+// Voltar para antes do agendamento remove o PostedAt na mesma transação; se o item
+// estava no ar, sai e content.unpublished é emitido.
+func (s *postService) Transition(ctx context.Context, id uint, to, note string) (*models.Post, error) {
+	return s.reschedule(ctx, id, false, func(tx *gorm.DB, repo repositories.PostRepository, post *models.Post) error {
+		if to == models.StatusScheduled || to == models.StatusPublished {
+			return &TransitionError{From: post.Status, To: to}
+		}
+		if err := s.setStatus(ctx, tx, repo, post, to, note); err != nil {
+			return err
+		}
+		if post.PostedAt != nil && !slices.Contains(postedStatuses, to) {
+			return repo.SetPostedAt(id, nil)
+		}
+		return nil
+	})
+}
+
+func (s *postService) StateChanges(ctx context.Context, id uint) ([]models.StateChange, error) {
+	if _, err := s.posts.WithContext(ctx).FindByID(id); err != nil {
+		return nil, err
+	}
+	return repositories.NewStateChangeRepository(s.db.WithContext(ctx)).FindByContent(dtos.TypePost, id)
+}
+
+func (s *postService) setStatus(ctx context.Context, tx *gorm.DB, repo repositories.PostRepository, post *models.Post, to, note string) error {
+	if err := checkTransition(ctx, post.Status, to); err != nil {
+		return err
+	}
+	if err := repo.SetStatus(post.ID, to); err != nil {
+		return err
+	}
+	return recordStateChange(ctx, tx, dtos.TypePost, post.ID, post.Status, to, note)
+}
+
+// Aplica a mudança de agendamento/estado e emite published/unpublished se a visibilidade mudou na hora.
+// Sem mudança de visibilidade, content.updated só é emitido quando notifyUpdate é true.
+func (s *postService) reschedule(ctx context.Context, id uint, notifyUpdate bool, apply func(*gorm.DB, repositories.PostRepository, *models.Post) error) (*models.Post, error) {
+	var updated *models.Post
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewPostRepository(tx)
+
+		post, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := apply(tx, repo, post); err != nil {
+			return err
+		}
+
+		live, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		t := scheduleEventType(isVisible(post.Status, post.PostedAt, post.UnpublishAt, now), isVisible(live.Status, live.PostedAt, live.UnpublishAt, now))
+		if t != events.ContentUpdated || notifyUpdate {
+			if err := enqueueEvent(tx, postEvent(t, live)); err != nil {
+				return err
+			}
+		}
+		// Datas já vencidas foram anunciadas aqui (ou o item já estava no ar); o
+		// PublishScheduler só anuncia as que vencerem depois
+		if live.PostedAt != nil && !live.PostedAt.After(now) {
+			if err := repo.MarkPublishNotified(id, now); err != nil {
+				return err
+			}
+		}
+		if live.UnpublishAt != nil && !live.UnpublishAt.After(now) {
+			if err := repo.MarkUnpublishNotified(id, now); err != nil {
+				return err
+			}
+		}
+
+		updated, _, err = loadPostWorkingCopy(tx, id)
+		return err
+	})
+	return updated, err
+}
+
+func (s *postService) Trash(ctx context.Context, page, pageSize int) ([]models.Post, int64, error) {
+	return s.posts.WithContext(ctx).FindTrashed(page, pageSize)
+}
+
+func (s *postService) Restore(ctx context.Context, id uint) (*models.Post, error) {
+	var restored *models.Post
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewPostRepository(tx)
+		if err := repo.Restore(id); err != nil {
+			return err
+		}
+
+		live, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := enqueueEvent(tx, postEvent(events.ContentUpdated, live)); err != nil {
+			return err
+		}
+
+		restored, _, err = loadPostWorkingCopy(tx, id)
+		return err
+	})
+	return restored, err
+}
+
+func (s *postService) Purge(ctx context.Context, id uint) error {
+	return s.posts.WithContext(ctx).Purge(id)
+}
+
+// Nomes em tagNames resolvem para a tag canônica (ver canonicalTagIDs)
+func (s *postService) ReplaceTags(ctx context.Context, id uint, tagIDs []uint, tagNames []string) (*models.Post, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, _ repositories.PostRepository, post *models.Post) error {
+		tagIDs, err := canonicalTagIDs(tx, tagIDs, tagNames)
+		if err != nil {
+			return err
+		}
+		post.Tags, err = loadTags(tx, tagIDs)
+		return err
+	})
+}
+
+func (s *postService) ReplaceCategories(ctx context.Context, id uint, categoryIDs []uint) (*models.Post, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, _ repositories.PostRepository, post *models.Post) error {
+		var err error
+		post.Categories, err = loadCategories(tx, categoryIDs)
+		return err
+	})
+}
+
+// Volta o conteúdo ao estado da revisão gravando uma revisão nova (o histórico não é reescrito).
+// Tags/categorias apagadas desde então são ignoradas; slug/título em uso por outro item geram conflito.
+func (s *postService) RestoreRevision(ctx context.Context, id uint, number int) (*models.Post, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, _ repositories.PostRepository, post *models.Post) error {
+		rev, err := repositories.NewRevisionRepository(tx).FindByNumber(dtos.TypePost, id, number)
+		if err != nil {
+			return err
+		}
+		post.Title = rev.Title
+		post.Slug = rev.Slug
+		post.ShortDescription = rev.ShortDescription
+		post.Body = rev.Body
+		post.Tags, post.Categories, err = loadDraftTaxonomies(tx, rev.TagIDs, rev.CategoryIDs)
+		return err
+	})
+}
+
+// Promove o rascunho a versão no ar. Conflitos de slug/título com outros itens
+// só são detectados aqui, já que o rascunho não ocupa os índices únicos.
+func (s *postService) PublishChanges(ctx context.Context, id uint) (*models.Post, error) {
+	if err := checkDraftReview(ctx); err != nil {
+		return nil, err
+	}
+	var published *models.Post
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewPostRepository(tx)
+
+		post, draft, err := loadPostWorkingCopy(tx, id)
+		if err != nil {
+			return err
+		}
+		if draft == nil {
+			return ErrNoPendingChanges
+		}
+		live, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		if err := writePost(repo, post); err != nil {
+			return err
+		}
+		if err := repositories.NewDraftRepository(tx).Delete(dtos.TypePost, id); err != nil {
+			return err
+		}
+
+		published, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		event := postEvent(events.ContentUpdated, published)
+		if live.Slug != published.Slug {
+			event.PreviousSlug = live.Slug
+		}
+		return enqueueEvent(tx, event)
+	})
+	return published, err
+}
+
+// Joga fora o rascunho; a cópia de trabalho volta a ser a versão no ar
+func (s *postService) DiscardChanges(ctx context.Context, id uint) (*models.Post, error) {
+	if err := checkDraftReview(ctx); err != nil {
+		return nil, err
+	}
+	var discarded *models.Post
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, draft, err := loadPostWorkingCopy(tx, id)
+		if err != nil {
+			return err
+		}
+		if draft == nil {
+			return ErrNoPendingChanges
+		}
+		if err := repositories.NewDraftRepository(tx).Delete(dtos.TypePost, id); err != nil {
+			return err
+		}
+
+		discarded, err = repositories.NewPostRepository(tx).FindByID(id)
+		return err
+	})
+	return discarded, err
+}
+
+// Edita a cópia de trabalho e grava o resultado: no rascunho se o post está no ar
+// (ou já tem rascunho), senão direto na linha. Toda gravação gera uma revisão;
+// webhooks só disparam quando a versão pública muda.
+func (s *postService) edit(ctx context.Context, id uint, change func(*gorm.DB, repositories.PostRepository, *models.Post) error) (*models.Post, error) {
+	var updated *models.Post
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewPostRepository(tx)
+
+		post, draft, err := loadPostWorkingCopy(tx, id)
+		if err != nil {
+			return err
+		}
+		previousSlug := post.Slug
+		if err := change(tx, repo, post); err != nil {
+			return err
+		}
+
+		if editsGoToDraft(post.Status, post.PostedAt, post.UnpublishAt, draft) {
+			if err := saveDraft(ctx, tx, postDraft(post)); err != nil {
+				return err
+			}
+			updated, _, err = loadPostWorkingCopy(tx, id)
+			if err != nil {
+				return err
+			}
+			return recordRevision(ctx, tx, postRevision(updated))
+		}
+
+		if err := writePost(repo, post); err != nil {
+			return err
+		}
+		updated, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := recordRevision(ctx, tx, postRevision(updated)); err != nil {
+			return err
+		}
+		event := postEvent(events.ContentUpdated, updated)
+		if previousSlug != updated.Slug {
+			event.PreviousSlug = previousSlug
+		}
+		return enqueueEvent(tx, event)
+	})
+	return updated, err
+}
+
+// Versão no ar com o rascunho sobreposto (draft é nil quando não há edições pendentes)
+func loadPostWorkingCopy(tx *gorm.DB, id uint) (*models.Post, *models.ContentDraft, error) {
+	post, err := repositories.NewPostRepository(tx).FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	draft, err := findDraft(tx, dtos.TypePost, id)
+	if err != nil || draft == nil {
+		return post, nil, err
+	}
+	return post, draft, overlayPostDraft(tx, post, draft)
+}
+
+// Grava os campos e as associações da cópia de trabalho na versão no ar
+func writePost(repo repositories.PostRepository, post *models.Post) error {
+	tags, categories := post.Tags, post.Categories
+	if err := repo.Update(post); err != nil {
+		return err
+	}
+	if err := repo.ReplaceTags(post, tags); err != nil {
+		return err
+	}
+	return repo.ReplaceCategories(post, categories)
+}
+
+// Body sanitizado contra XSS antes de persistir (o slug é resolvido à parte)
+func applyPostInput(post *models.Post, in dtos.ContentInput) {
+	post.Title = in.Title
+	post.ShortDescription = in.ShortDescription
+	post.Body = validators.SanitizeHTML(in.Body)
+}
+
+func loadPostAssociations(tx *gorm.DB, post *models.Post, tagIDs, categoryIDs []uint) error {
+	tags, err := loadTags(tx, tagIDs)
+	if err != nil {
+		return err
+	}
+	categories, err := loadCategories(tx, categoryIDs)
+	if err != nil {
+		return err
+	}
+	post.Tags, post.Categories = tags, categories
+	return nil
+}
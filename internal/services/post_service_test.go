@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostService(t *testing.T) {
+	db := SetupTestDB()
+	svc := services.NewPostService(db)
+	ctx := context.Background()
+
+	tag := models.Tag{Title: "Go"}
+	cat := models.Category{Title: "Backend"}
+	db.Create(&tag)
+	db.Create(&cat)
+
+	t.Run("Deve criar post com tags e categorias na mesma operação", func(t *testing.T) {
+		post, err := svc.Create(ctx, dtos.ContentInput{
+			Type: "post", Title: "Aprendendo Go", Body: "corpo",
+			TagIDs: []uint{tag.ID}, CategoryIDs: []uint{cat.ID},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "aprendendo-go", post.Slug)
+		assert.Len(t, post.Tags, 1)
+		assert.Len(t, post.Categories, 1)
+	})
+
+	t.Run("Deve recusar IDs de tag inexistentes sem gravar o post", func(t *testing.T) {
+		post, err := svc.Create(ctx, dtos.ContentInput{
+			Type: "post", Title: "Tag Fantasma", Body: "corpo",
+			TagIDs: []uint{tag.ID, 42},
+		})
+
+		var refErr *services.InvalidReferenceError
+		assert.ErrorAs(t, err, &refErr)
+		assert.Equal(t, "tag_ids", refErr.Field)
+		assert.Equal(t, []uint{42}, refErr.IDs)
+		assert.Nil(t, post)
+
+		var count int64
+		db.Model(&models.Post{}).Where("title = ?", "Tag Fantasma").Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("Deve manter associações quando o update não informa IDs", func(t *testing.T) {
+		post, err := svc.Update(ctx, 1, dtos.ContentInput{Type: "post", Title: "Aprendendo Go 2", Body: "novo"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "aprendendo-go-2", post.Slug)
+		assert.Len(t, post.Tags, 1)
+		assert.Len(t, post.Categories, 1)
+	})
+
+	t.Run("Deve limpar tags quando o update informa lista vazia", func(t *testing.T) {
+		post, err := svc.Update(ctx, 1, dtos.ContentInput{Type: "post", Title: "Aprendendo Go 2", Body: "novo", TagIDs: []uint{}})
+
+		assert.NoError(t, err)
+		assert.Empty(t, post.Tags)
+		assert.Len(t, post.Categories, 1)
+	})
+
+	t.Run("Deve desfazer o post quando a gravação das associações falhar", func(t *testing.T) {
+		// Sem a tabela de junção o insert das categorias falha depois do insert do post
+		db.Migrator().DropTable("post_categories")
+		defer db.AutoMigrate(&models.Post{})
+
+		post, err := svc.Create(ctx, dtos.ContentInput{
+			Type: "post", Title: "Rollback", Body: "corpo",
+			CategoryIDs: []uint{cat.ID},
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, post)
+
+		var count int64
+		db.Unscoped().Model(&models.Post{}).Where("title = ?", "Rollback").Count(&count)
+		assert.Zero(t, count, "o insert em posts deveria ter sido revertido")
+	})
+}
+
+func TestPostService_Slugs(t *testing.T) {
+	db := SetupTestDB()
+	svc := services.NewPostService(db)
+	ctx := context.Background()
+
+	t.Run("Deve resolver colisões de slug automaticamente", func(t *testing.T) {
+		first, err := svc.Create(ctx, dtos.ContentInput{Type: "post", Title: "Go: Parte 1", Body: "a"})
+		assert.NoError(t, err)
+		second, err := svc.Create(ctx, dtos.ContentInput{Type: "post", Title: "Go - Parte 1!", Body: "b"})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "go-parte-1", first.Slug)
+		assert.Equal(t, "go-parte-1-2", second.Slug)
+	})
+
+	t.Run("Deve aceitar slug manual normalizado", func(t *testing.T) {
+		post, err := svc.Create(ctx, dtos.ContentInput{Type: "post", Title: "Qualquer", Slug: "Meu Slug Ótimo", Body: "c"})
+		assert.NoError(t, err)
+		assert.Equal(t, "meu-slug-otimo", post.Slug)
+	})
+
+	t.Run("Deve manter slug manual em updates que não mudam o título", func(t *testing.T) {
+		post, err := svc.Update(ctx, 3, dtos.ContentInput{Type: "post", Title: "Qualquer", Body: "novo corpo"})
+		assert.NoError(t, err)
+		assert.Equal(t, "meu-slug-otimo", post.Slug)
+	})
+
+	t.Run("Deve manter o próprio slug ao atualizar sem conflitar consigo mesmo", func(t *testing.T) {
+		post, err := svc.Update(ctx, 1, dtos.ContentInput{Type: "post", Title: "Go: Parte 1", Slug: "go-parte-1", Body: "x"})
+		assert.NoError(t, err)
+		assert.Equal(t, "go-parte-1", post.Slug)
+	})
+
+	t.Run("Deve recusar slug manual ocupado ou reservado", func(t *testing.T) {
+		_, err := svc.Create(ctx, dtos.ContentInput{Type: "post", Title: "Outro", Slug: "go-parte-1", Body: "d"})
+		assert.ErrorIs(t, err, repositories.ErrDuplicateSlug)
+
+		_, err = svc.Create(ctx, dtos.ContentInput{Type: "post", Title: "Outro", Slug: "Admin", Body: "d"})
+		var valErr *services.ValidationError
+		assert.ErrorAs(t, err, &valErr)
+		assert.Equal(t, "slug", valErr.Field)
+	})
+}
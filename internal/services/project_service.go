@@ -0,0 +1,544 @@
+package services
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/utils"
+	"cms-headless/internal/validators"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Leituras e escritas do admin trabalham sobre a cópia de trabalho (versão no ar + rascunho);
+// a API pública lê direto dos repositórios e só enxerga a versão publicada.
+type ProjectService interface {
+	List(ctx context.Context, page, pageSize int, status string) ([]models.Project, int64, error)
+	ListPage(ctx context.Context, q utils.CursorQuery, status string) (repositories.CursorPage[models.Project], error)
+	Get(ctx context.Context, id uint) (*models.Project, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Project, error)
+	Create(ctx context.Context, in dtos.ContentInput) (*models.Project, error)
+	Update(ctx context.Context, id uint, in dtos.ContentInput) (*models.Project, error)
+	Delete(ctx context.Context, id uint) error
+	SetPostedAt(ctx context.Context, id uint, t *time.Time) (*models.Project, error)
+	SetUnpublishAt(ctx context.Context, id uint, t *time.Time) (*models.Project, error)
+	ReplaceTags(ctx context.Context, id uint, tagIDs []uint, tagNames []string) (*models.Project, error)
+	ReplaceCategories(ctx context.Context, id uint, categoryIDs []uint) (*models.Project, error)
+	Trash(ctx context.Context, page, pageSize int) ([]models.Project, int64, error)
+	Restore(ctx context.Context, id uint) (*models.Project, error)
+	Purge(ctx context.Context, id uint) error
+	RestoreRevision(ctx context.Context, id uint, number int) (*models.Project, error)
+	PublishChanges(ctx context.Context, id uint) (*models.Project, error)
+	DiscardChanges(ctx context.Context, id uint) (*models.Project, error)
+	Transition(ctx context.Context, id uint, to, note string) (*models.Project, error)
+	StateChanges(ctx context.Context, id uint) ([]models.StateChange, error)
+}
+
+type projectService struct {
+	db       *gorm.DB
+	projects repositories.ProjectRepository
+}
+
+func NewProjectService(db *gorm.DB) ProjectService {
+	return &projectService{db: db, projects: repositories.NewProjectRepository(db)}
+}
+
+// status vazio lista todos os estados
+func (s *projectService) List(ctx context.Context, page, pageSize int, status string) ([]models.Project, int64, error) {
+	tx := s.db.WithContext(ctx)
+	projects, total, err := repositories.NewProjectRepository(tx).FindAll(page, pageSize, false, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := overlayProjectDrafts(tx, projects); err != nil {
+		return nil, 0, err
+	}
+	return projects, total, nil
+}
+
+// Como List, mas por cursor sobre (posted_at, id); rascunhos sem data vêm no fim
+func (s *projectService) ListPage(ctx context.Context, q utils.CursorQuery, status string) (repositories.CursorPage[models.Project], error) {
+	tx := s.db.WithContext(ctx)
+	page, err := repositories.NewProjectRepository(tx).FindPage(q, repositories.ContentFilter{Status: status})
+	if err != nil {
+		return page, err
+	}
+	return page, overlayProjectDrafts(tx, page.Items)
+}
+
+// Aplica sobre cada item da listagem o rascunho pendente, se houver
+func overlayProjectDrafts(tx *gorm.DB, projects []models.Project) error {
+	ids := make([]uint, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	drafts, err := repositories.NewDraftRepository(tx).FindByContentIDs(dtos.TypeProject, ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.ContentDraft, len(drafts))
+	for i := range drafts {
+		byID[drafts[i].ContentID] = &drafts[i]
+	}
+	for i := range projects {
+		if draft := byID[projects[i].ID]; draft != nil {
+			if err := overlayProjectDraft(tx, &projects[i], draft); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *projectService) Get(ctx context.Context, id uint) (*models.Project, error) {
+	project, _, err := loadProjectWorkingCopy(s.db.WithContext(ctx), id)
+	return project, err
+}
+
+// Cópia de trabalho pelo slug no ar ou pelo do rascunho, inclusive não publicada
+// (usado pelo preview)
+func (s *projectService) GetBySlug(ctx context.Context, slug string) (*models.Project, error) {
+	tx := s.db.WithContext(ctx)
+	id := uint(0)
+	live, err := repositories.NewProjectRepository(tx).FindBySlug(slug, false)
+	switch {
+	case err == nil:
+		id = live.ID
+	case errors.Is(err, repositories.ErrNotFound):
+		draft, err := repositories.NewDraftRepository(tx).FindBySlug(dtos.TypeProject, slug)
+		if err != nil {
+			return nil, err
+		}
+		id = draft.ContentID
+	default:
+		return nil, err
+	}
+	project, _, err := loadProjectWorkingCopy(tx, id)
+	return project, err
+}
+
+// Valida as referências e grava o projeto com suas tags/categorias numa única transação
+func (s *projectService) Create(ctx context.Context, in dtos.ContentInput) (*models.Project, error) {
+	var created *models.Project
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewProjectRepository(tx)
+
+		var err error
+		if in.TagIDs, err = canonicalTagIDs(tx, in.TagIDs, in.TagNames); err != nil {
+			return err
+		}
+
+		project := &models.Project{}
+		applyProjectInput(project, in)
+
+		slug, err := resolveSlug("", 0, true, in.Title, in.Slug, repo.SlugExists)
+		if err != nil {
+			return err
+		}
+		project.Slug = slug
+
+		if err := loadProjectAssociations(tx, project, in.TagIDs, in.CategoryIDs); err != nil {
+			return err
+		}
+		if err := repo.Create(project); err != nil {
+			return err
+		}
+
+		created, err = repo.FindByID(project.ID)
+		if err != nil {
+			return err
+		}
+		if err := recordRevision(ctx, tx, projectRevision(created)); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, projectEvent(events.ContentCreated, created))
+	})
+	return created, err
+}
+
+// Tags/categorias só são substituídas quando informadas (nil mantém as atuais)
+func (s *projectService) Update(ctx context.Context, id uint, in dtos.ContentInput) (*models.Project, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, repo repositories.ProjectRepository, project *models.Project) error {
+		titleChanged := project.Title != in.Title
+		applyProjectInput(project, in)
+
+		var err error
+		if in.TagIDs, err = canonicalTagIDs(tx, in.TagIDs, in.TagNames); err != nil {
+			return err
+		}
+		project.Slug, err = resolveSlug(project.Slug, project.ID, titleChanged, in.Title, in.Slug, repo.SlugExists)
+		if err != nil {
+			return err
+		}
+		if in.TagIDs != nil {
+			if project.Tags, err = loadTags(tx, in.TagIDs); err != nil {
+				return err
+			}
+		}
+		if in.CategoryIDs != nil {
+			if project.Categories, err = loadCategories(tx, in.CategoryIDs); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *projectService) Delete(ctx context.Context, id uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewProjectRepository(tx)
+
+		project, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := repo.Delete(id); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, projectEvent(events.ContentDeleted, project))
+	})
+}
+
+// Só conteúdo aprovado pode ser agendado; o estado passa a scheduled/published
+// conforme a data (ou volta a approved quando t é nil)
+func (s *projectService) SetPostedAt(ctx context.Context, id uint, t *time.Time) (*models.Project, error) {
+	return s.reschedule(ctx, id, true, func(tx *gorm.DB, repo repositories.ProjectRepository, project *models.Project) error {
+		next := statusForPostedAt(t, time.Now().UTC())
+		if !slices.Contains(approvedStatuses, project.Status) {
+			return &TransitionError{From: project.Status, To: next}
+		}
+		if err := validateUnpublishAt(t, project.UnpublishAt); err != nil {
+			return err
+		}
+		if next != project.Status {
+			if err := s.setStatus(ctx, tx, repo, project, next, ""); err != nil {
+				return err
+			}
+		}
+		return repo.SetPostedAt(id, t)
+	})
+}
+
+// A retirada precisa ser posterior à publicação (quando esta estiver definida)
+func (s *projectService) SetUnpublishAt(ctx context.Context, id uint, t *time.Time) (*models.Project, error) {
+	return s.reschedule(ctx, id, true, func(_ *gorm.DB, repo repositories.ProjectRepository, project *models.Project) error {
+		if err := validateUnpublishAt(project.PostedAt, t); err != nil {
+			return err
+		}
+		return repo.SetUnpublishAt(id, t)
+	})
+}
+
+// Muda o estado editorial manualmente (scheduled/published só via SetPostedAt).
+// Voltar para antes do agendamento remove o PostedAt na mesma transação; se o item
+// estava no ar, sai e content.unpublished é emitido.
+func (s *projectService) Transition(ctx context.Context, id uint, to, note string) (*models.Project, error) {
+	return s.reschedule(ctx, id, false, func(tx *gorm.DB, repo repositories.ProjectRepository, project *models.Project) error {
+		if to == models.StatusScheduled || to == models.StatusPublished {
+			return &TransitionError{From: project.Status, To: to}
+		}
+		if err := s.setStatus(ctx, tx, repo, project, to, note); err != nil {
+			return err
+		}
+		if project.PostedAt != nil && !slices.Contains(postedStatuses, to) {
+			return repo.SetPostedAt(id, nil)
+		}
+		return nil
+	})
+}
+
+func (s *projectService) StateChanges(ctx context.Context, id uint) ([]models.StateChange, error) {
+	if _, err := s.projects.WithContext(ctx).FindByID(id); err != nil {
+		return nil, err
+	}
+	return repositories.NewStateChangeRepository(s.db.WithContext(ctx)).FindByContent(dtos.TypeProject, id)
+}
+
+func (s *projectService) setStatus(ctx context.Context, tx *gorm.DB, repo repositories.ProjectRepository, project *models.Project, to, note string) error {
+	if err := checkTransition(ctx, project.Status, to); err != nil {
+		return err
+	}
+	if err := repo.SetStatus(project.ID, to); err != nil {
+		return err
+	}
+	return recordStateChange(ctx, tx, dtos.TypeProject, project.ID, project.Status, to, note)
+}
+
+// Aplica a mudança de agendamento/estado e emite published/unpublished se a visibilidade mudou na hora.
+// Sem mudança de visibilidade, content.updated só é emitido quando notifyUpdate é true.
+func (s *projectService) reschedule(ctx context.Context, id uint, notifyUpdate bool, apply func(*gorm.DB, repositories.ProjectRepository, *models.Project) error) (*models.Project, error) {
+	var updated *models.Project
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewProjectRepository(tx)
+
+		project, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := apply(tx, repo, project); err != nil {
+			return err
+		}
+
+		live, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		t := scheduleEventType(isVisible(project.Status, project.PostedAt, project.UnpublishAt, now), isVisible(live.Status, live.PostedAt, live.UnpublishAt, now))
+		if t != events.ContentUpdated || notifyUpdate {
+			if err := enqueueEvent(tx, projectEvent(t, live)); err != nil {
+				return err
+			}
+		}
+		// Datas já vencidas foram anunciadas aqui (ou o item já estava no ar); o
+		// PublishScheduler só anuncia as que vencerem depois
+		if live.PostedAt != nil && !live.PostedAt.After(now) {
+			if err := repo.MarkPublishNotified(id, now); err != nil {
+				return err
+			}
+		}
+		if live.UnpublishAt != nil && !live.UnpublishAt.After(now) {
+			if err := repo.MarkUnpublishNotified(id, now); err != nil {
+				return err
+			}
+		}
+
+		updated, _, err = loadProjectWorkingCopy(tx, id)
+		return err
+	})
+	return updated, err
+}
+
+func (s *projectService) Trash(ctx context.Context, page, pageSize int) ([]models.Project, int64, error) {
+	return s.projects.WithContext(ctx).FindTrashed(page, pageSize)
+}
+
+func (s *projectService) Restore(ctx context.Context, id uint) (*models.Project, error) {
+	var restored *models.Project
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewProjectRepository(tx)
+		if err := repo.Restore(id); err != nil {
+			return err
+		}
+
+		live, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := enqueueEvent(tx, projectEvent(events.ContentUpdated, live)); err != nil {
+			return err
+		}
+
+		restored, _, err = loadProjectWorkingCopy(tx, id)
+		return err
+	})
+	return restored, err
+}
+
+func (s *projectService) Purge(ctx context.Context, id uint) error {
+	return s.projects.WithContext(ctx).Purge(id)
+}
+
+// Nomes em tagNames resolvem para a tag canônica (ver canonicalTagIDs)
+func (s *projectService) ReplaceTags(ctx context.Context, id uint, tagIDs []uint, tagNames []string) (*models.Project, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, _ repositories.ProjectRepository, project *models.Project) error {
+		tagIDs, err := canonicalTagIDs(tx, tagIDs, tagNames)
+		if err != nil {
+			return err
+		}
+		project.Tags, err = loadTags(tx, tagIDs)
+		return err
+	})
+}
+
+func (s *projectService) ReplaceCategories(ctx context.Context, id uint, categoryIDs []uint) (*models.Project, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, _ repositories.ProjectRepository, project *models.Project) error {
+		var err error
+		project.Categories, err = loadCategories(tx, categoryIDs)
+		return err
+	})
+}
+
+// Volta o conteúdo ao estado da revisão gravando uma revisão nova (o histórico não é reescrito).
+// Tags/categorias apagadas desde então são ignoradas; slug/título em uso por outro item geram conflito.
+func (s *projectService) RestoreRevision(ctx context.Context, id uint, number int) (*models.Project, error) {
+	return s.edit(ctx, id, func(tx *gorm.DB, _ repositories.ProjectRepository, project *models.Project) error {
+		rev, err := repositories.NewRevisionRepository(tx).FindByNumber(dtos.TypeProject, id, number)
+		if err != nil {
+			return err
+		}
+		project.Title = rev.Title
+		project.Slug = rev.Slug
+		project.ShortDescription = rev.ShortDescription
+		project.Body = rev.Body
+		project.DemoURL = rev.DemoURL
+		project.RepoURL = rev.RepoURL
+		project.Tags, project.Categories, err = loadDraftTaxonomies(tx, rev.TagIDs, rev.CategoryIDs)
+		return err
+	})
+}
+
+// Promove o rascunho a versão no ar. Conflitos de slug/título com outros itens
+// só são detectados aqui, já que o rascunho não ocupa os índices únicos.
+func (s *projectService) PublishChanges(ctx context.Context, id uint) (*models.Project, error) {
+	if err := checkDraftReview(ctx); err != nil {
+		return nil, err
+	}
+	var published *models.Project
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewProjectRepository(tx)
+
+		project, draft, err := loadProjectWorkingCopy(tx, id)
+		if err != nil {
+			return err
+		}
+		if draft == nil {
+			return ErrNoPendingChanges
+		}
+		live, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		if err := writeProject(repo, project); err != nil {
+			return err
+		}
+		if err := repositories.NewDraftRepository(tx).Delete(dtos.TypeProject, id); err != nil {
+			return err
+		}
+
+		published, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		event := projectEvent(events.ContentUpdated, published)
+		if live.Slug != published.Slug {
+			event.PreviousSlug = live.Slug
+		}
+		return enqueueEvent(tx, event)
+	})
+	return published, err
+}
+
+// Joga fora o rascunho; a cópia de trabalho volta a ser a versão no ar
+func (s *projectService) DiscardChanges(ctx context.Context, id uint) (*models.Project, error) {
+	if err := checkDraftReview(ctx); err != nil {
+		return nil, err
+	}
+	var discarded *models.Project
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		_, draft, err := loadProjectWorkingCopy(tx, id)
+		if err != nil {
+			return err
+		}
+		if draft == nil {
+			return ErrNoPendingChanges
+		}
+		if err := repositories.NewDraftRepository(tx).Delete(dtos.TypeProject, id); err != nil {
+			return err
+		}
+
+		discarded, err = repositories.NewProjectRepository(tx).FindByID(id)
+		return err
+	})
+	return discarded, err
+}
+
+// Edita a cópia de trabalho e grava o resultado: no rascunho se o projeto está no ar
+// (ou já tem rascunho), senão direto na linha. Toda gravação gera uma revisão;
+// webhooks só disparam quando a versão pública muda.
+func (s *projectService) edit(ctx context.Context, id uint, change func(*gorm.DB, repositories.ProjectRepository, *models.Project) error) (*models.Project, error) {
+	var updated *models.Project
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewProjectRepository(tx)
+
+		project, draft, err := loadProjectWorkingCopy(tx, id)
+		if err != nil {
+			return err
+		}
+		previousSlug := project.Slug
+		if err := change(tx, repo, project); err != nil {
+			return err
+		}
+
+		if editsGoToDraft(project.Status, project.PostedAt, project.UnpublishAt, draft) {
+			if err := saveDraft(ctx, tx, projectDraft(project)); err != nil {
+				return err
+			}
+			updated, _, err = loadProjectWorkingCopy(tx, id)
+			if err != nil {
+				return err
+			}
+			return recordRevision(ctx, tx, projectRevision(updated))
+		}
+
+		if err := writeProject(repo, project); err != nil {
+			return err
+		}
+		updated, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := recordRevision(ctx, tx, projectRevision(updated)); err != nil {
+			return err
+		}
+		event := projectEvent(events.ContentUpdated, updated)
+		if previousSlug != updated.Slug {
+			event.PreviousSlug = previousSlug
+		}
+		return enqueueEvent(tx, event)
+	})
+	return updated, err
+}
+
+// Versão no ar com o rascunho sobreposto (draft é nil quando não há edições pendentes)
+func loadProjectWorkingCopy(tx *gorm.DB, id uint) (*models.Project, *models.ContentDraft, error) {
+	project, err := repositories.NewProjectRepository(tx).FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	draft, err := findDraft(tx, dtos.TypeProject, id)
+	if err != nil || draft == nil {
+		return project, nil, err
+	}
+	return project, draft, overlayProjectDraft(tx, project, draft)
+}
+
+// Grava os campos e as associações da cópia de trabalho na versão no ar
+func writeProject(repo repositories.ProjectRepository, project *models.Project) error {
+	tags, categories := project.Tags, project.Categories
+	if err := repo.Update(project); err != nil {
+		return err
+	}
+	if err := repo.ReplaceTags(project, tags); err != nil {
+		return err
+	}
+	return repo.ReplaceCategories(project, categories)
+}
+
+// Body sanitizado contra XSS antes de persistir (o slug é resolvido à parte)
+func applyProjectInput(project *models.Project, in dtos.ContentInput) {
+	project.Title = in.Title
+	project.ShortDescription = in.ShortDescription
+	project.Body = validators.SanitizeHTML(in.Body)
+	project.DemoURL = in.DemoURL
+	project.RepoURL = in.RepoURL
+}
+
+func loadProjectAssociations(tx *gorm.DB, project *models.Project, tagIDs, categoryIDs []uint) error {
+	tags, err := loadTags(tx, tagIDs)
+	if err != nil {
+		return err
+	}
+	categories, err := loadCategories(tx, categoryIDs)
+	if err != nil {
+		return err
+	}
+	project.Tags, project.Categories = tags, categories
+	return nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Carrega as tags garantindo que todos os IDs existem
+func loadTags(tx *gorm.DB, ids []uint) ([]models.Tag, error) {
+	ids = uniqueIDs(ids)
+	tags, err := repositories.NewTagRepository(tx).FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]uint, 0, len(tags))
+	for _, t := range tags {
+		found = append(found, t.ID)
+	}
+	if missing := missingIDs(ids, found); len(missing) > 0 {
+		return nil, &InvalidReferenceError{Field: "tag_ids", IDs: missing}
+	}
+	return tags, nil
+}
+
+// Junta aos ids as tags canônicas dos nomes (criando as que faltam). Sem nomes,
+// devolve ids como está, preservando o nil que mantém as tags atuais no update.
+func canonicalTagIDs(tx *gorm.DB, ids []uint, names []string) ([]uint, error) {
+	if names == nil {
+		return ids, nil
+	}
+	tags, err := repositories.NewTagRepository(tx).FindOrCreate(names)
+	if err != nil {
+		return nil, err
+	}
+	merged := append([]uint{}, ids...)
+	for _, t := range tags {
+		merged = append(merged, t.ID)
+	}
+	return merged, nil
+}
+
+func loadCategories(tx *gorm.DB, ids []uint) ([]models.Category, error) {
+	ids = uniqueIDs(ids)
+	categories, err := repositories.NewCategoryRepository(tx).FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	found := make([]uint, 0, len(categories))
+	for _, c := range categories {
+		found = append(found, c.ID)
+	}
+	if missing := missingIDs(ids, found); len(missing) > 0 {
+		return nil, &InvalidReferenceError{Field: "category_ids", IDs: missing}
+	}
+	return categories, nil
+}
+
+func uniqueIDs(ids []uint) []uint {
+	out := slices.Clone(ids)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+func missingIDs(wanted, found []uint) []uint {
+	var missing []uint
+	for _, id := range wanted {
+		if !slices.Contains(found, id) {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
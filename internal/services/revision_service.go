@@ -0,0 +1,128 @@
+package services
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/diff"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"context"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Consulta ao histórico de revisões de posts e projetos (a restauração fica em cada serviço)
+type RevisionService interface {
+	List(ctx context.Context, contentType string, contentID uint, page, pageSize int) ([]models.Revision, int64, error)
+	Get(ctx context.Context, contentType string, contentID uint, number int) (*models.Revision, error)
+	Diff(ctx context.Context, contentType string, contentID uint, from, to int) (*dtos.RevisionDiffResponse, error)
+}
+
+type revisionService struct {
+	revisions repositories.RevisionRepository
+}
+
+func NewRevisionService(db *gorm.DB) RevisionService {
+	return &revisionService{revisions: repositories.NewRevisionRepository(db)}
+}
+
+func (s *revisionService) List(ctx context.Context, contentType string, contentID uint, page, pageSize int) ([]models.Revision, int64, error) {
+	return s.revisions.WithContext(ctx).FindByContent(contentType, contentID, page, pageSize)
+}
+
+func (s *revisionService) Get(ctx context.Context, contentType string, contentID uint, number int) (*models.Revision, error) {
+	return s.revisions.WithContext(ctx).FindByNumber(contentType, contentID, number)
+}
+
+func (s *revisionService) Diff(ctx context.Context, contentType string, contentID uint, from, to int) (*dtos.RevisionDiffResponse, error) {
+	repo := s.revisions.WithContext(ctx)
+	a, err := repo.FindByNumber(contentType, contentID, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := repo.FindByNumber(contentType, contentID, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffRevisions(a, b), nil
+}
+
+func diffRevisions(a, b *models.Revision) *dtos.RevisionDiffResponse {
+	res := &dtos.RevisionDiffResponse{From: a.Number, To: b.Number, Changes: []dtos.FieldChange{}}
+	addChange := func(field string, from, to any, changed bool) {
+		if changed {
+			res.Changes = append(res.Changes, dtos.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+
+	addChange("title", a.Title, b.Title, a.Title != b.Title)
+	addChange("slug", a.Slug, b.Slug, a.Slug != b.Slug)
+	addChange("short_description", a.ShortDescription, b.ShortDescription, a.ShortDescription != b.ShortDescription)
+	addChange("demo_url", a.DemoURL, b.DemoURL, a.DemoURL != b.DemoURL)
+	addChange("repo_url", a.RepoURL, b.RepoURL, a.RepoURL != b.RepoURL)
+	addChange("tag_ids", a.TagIDs, b.TagIDs, !sameIDs(a.TagIDs, b.TagIDs))
+	addChange("category_ids", a.CategoryIDs, b.CategoryIDs, !sameIDs(a.CategoryIDs, b.CategoryIDs))
+
+	res.Body = diff.Lines(a.Body, b.Body)
+	return res
+}
+
+func sameIDs(a, b []uint) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// Grava o snapshot do conteúdo na transação da alteração, com o autor vindo do token
+func recordRevision(ctx context.Context, tx *gorm.DB, rev models.Revision) error {
+	if p, ok := auth.FromContext(ctx); ok {
+		rev.Author = p.Name
+	}
+	return repositories.NewRevisionRepository(tx).Create(&rev)
+}
+
+func postRevision(post *models.Post) models.Revision {
+	return models.Revision{
+		ContentType:      dtos.TypePost,
+		ContentID:        post.ID,
+		Title:            post.Title,
+		Slug:             post.Slug,
+		ShortDescription: post.ShortDescription,
+		Body:             post.Body,
+		TagIDs:           tagIDs(post.Tags),
+		CategoryIDs:      categoryIDs(post.Categories),
+	}
+}
+
+func projectRevision(project *models.Project) models.Revision {
+	return models.Revision{
+		ContentType:      dtos.TypeProject,
+		ContentID:        project.ID,
+		Title:            project.Title,
+		Slug:             project.Slug,
+		ShortDescription: project.ShortDescription,
+		Body:             project.Body,
+		DemoURL:          project.DemoURL,
+		RepoURL:          project.RepoURL,
+		TagIDs:           tagIDs(project.Tags),
+		CategoryIDs:      categoryIDs(project.Categories),
+	}
+}
+
+func tagIDs(tags []models.Tag) []uint {
+	ids := make([]uint, 0, len(tags))
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func categoryIDs(categories []models.Category) []uint {
+	ids := make([]uint, 0, len(categories))
+	for _, c := range categories {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
@@ -0,0 +1,82 @@
+package services_test
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisions(t *testing.T) {
+	db := SetupTestDB()
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Name: "editora"})
+	posts := services.NewPostService(db)
+	projects := services.NewProjectService(db)
+	revisions := services.NewRevisionService(db)
+
+	tag := models.Tag{Title: "Go"}
+	db.Create(&tag)
+
+	post, _ := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "Primeira Versão", Body: "linha 1\nlinha 2", TagIDs: []uint{tag.ID}})
+	posts.Update(ctx, post.ID, dtos.ContentInput{Type: "post", Title: "Segunda Versão", Body: "linha 1\nlinha dois", TagIDs: []uint{}})
+
+	t.Run("Deve gravar uma revisão por alteração com autor", func(t *testing.T) {
+		list, total, err := revisions.List(ctx, dtos.TypePost, post.ID, 1, 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, 2, list[0].Number, "mais recente primeiro")
+			assert.Equal(t, "editora", list[0].Author)
+		}
+
+		first, err := revisions.Get(ctx, dtos.TypePost, post.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "primeira-versao", first.Slug)
+		assert.Equal(t, []uint{tag.ID}, first.TagIDs)
+	})
+
+	t.Run("Deve comparar duas revisões", func(t *testing.T) {
+		res, err := revisions.Diff(ctx, dtos.TypePost, post.ID, 1, 2)
+
+		assert.NoError(t, err)
+		fields := []string{}
+		for _, c := range res.Changes {
+			fields = append(fields, c.Field)
+		}
+		assert.ElementsMatch(t, []string{"title", "slug", "tag_ids"}, fields)
+		assert.Len(t, res.Body, 3) // "linha 1" igual, "linha 2" removida, "linha dois" inserida
+	})
+
+	t.Run("Deve restaurar revisão como nova revisão", func(t *testing.T) {
+		restored, err := posts.RestoreRevision(ctx, post.ID, 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Primeira Versão", restored.Title)
+		assert.Equal(t, "primeira-versao", restored.Slug)
+		assert.Equal(t, "linha 1\nlinha 2", restored.Body)
+		assert.Len(t, restored.Tags, 1)
+
+		_, total, _ := revisions.List(ctx, dtos.TypePost, post.ID, 1, 10)
+		assert.Equal(t, int64(3), total, "histórico é imutável: restaurar gera a revisão 3")
+	})
+
+	t.Run("Deve restaurar URLs de projetos", func(t *testing.T) {
+		project, _ := projects.Create(ctx, dtos.ContentInput{Type: "project", Title: "Portfolio", Body: "corpo", DemoURL: "https://v1.dev"})
+		projects.Update(ctx, project.ID, dtos.ContentInput{Type: "project", Title: "Portfolio", Body: "corpo", DemoURL: "https://v2.dev"})
+
+		restored, err := projects.RestoreRevision(ctx, project.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://v1.dev", restored.DemoURL)
+	})
+
+	t.Run("Deve retornar ErrNotFound para revisão inexistente", func(t *testing.T) {
+		_, err := posts.RestoreRevision(ctx, post.ID, 99)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"time"
+)
+
+func validateUnpublishAt(postedAt, unpublishAt *time.Time) error {
+	if unpublishAt != nil && postedAt != nil && !unpublishAt.After(*postedAt) {
+		return &ValidationError{Field: "unpublish_at", Message: "deve ser posterior a posted_at"}
+	}
+	return nil
+}
+
+// Mesma regra do scope visible dos repositórios
+func isVisible(status string, postedAt, unpublishAt *time.Time, now time.Time) bool {
+	return status != models.StatusArchived && postedAt != nil && !postedAt.After(now) && (unpublishAt == nil || unpublishAt.After(now))
+}
+
+// Evento de uma mudança de agendamento: publicação/retirada imediata ou simples atualização
+// (agendamentos futuros são anunciados pelo PublishScheduler quando vencem)
+func scheduleEventType(wasVisible, visible bool) events.Type {
+	switch {
+	case !wasVisible && visible:
+		return events.ContentPublished
+	case wasVisible && !visible:
+		return events.ContentUnpublished
+	default:
+		return events.ContentUpdated
+	}
+}
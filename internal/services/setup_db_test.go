@@ -0,0 +1,23 @@
+package services_test
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/search"
+	"context"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func SetupTestDB() *gorm.DB {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	models.Migrate(db)
+	search.Setup(db)
+	return db
+}
+
+// Leva o conteúdo de draft a approved para que possa ser agendado
+func approve[T any](ctx context.Context, transition func(context.Context, uint, string, string) (T, error), id uint) {
+	transition(ctx, id, models.StatusInReview, "")
+	transition(ctx, id, models.StatusApproved, "")
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/validators"
+)
+
+type slugExistsFunc func(slug string, excludeID uint) (bool, error)
+
+// Define o slug do conteúdo:
+//   - slug manual é normalizado e precisa estar livre, sem sufixo automático;
+//   - sem slug manual, o atual é mantido enquanto o título não mudar;
+//   - caso contrário é gerado do título, com "-2", "-3"... em caso de colisão.
+func resolveSlug(current string, id uint, titleChanged bool, title, manual string, exists slugExistsFunc) (string, error) {
+	if manual != "" {
+		slug := validators.GenerateSlug(manual)
+		if validators.IsReservedSlug(slug) {
+			return "", &ValidationError{Field: "slug", Message: "slug reservado: " + slug}
+		}
+		taken, err := exists(slug, id)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", repositories.ErrDuplicateSlug
+		}
+		return slug, nil
+	}
+
+	if current != "" && !titleChanged {
+		return current, nil
+	}
+
+	return validators.UniqueSlug(validators.GenerateSlug(title), func(slug string) (bool, error) {
+		return exists(slug, id)
+	})
+}
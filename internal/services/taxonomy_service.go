@@ -0,0 +1,445 @@
+package services
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/utils"
+	"cms-headless/internal/validators"
+	"context"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// Operações administrativas sobre tags e categorias
+// As leituras devolvem também quantos posts e projetos publicados usam cada item.
+type TaxonomyService interface {
+	ListTags(ctx context.Context, q utils.CursorQuery) (repositories.CursorPage[models.Tag], map[uint]repositories.TaxonomyUsage, error)
+	GetTag(ctx context.Context, id uint) (*models.Tag, repositories.TaxonomyUsage, error)
+	FindTagByTitle(ctx context.Context, title string) (*models.Tag, repositories.TaxonomyUsage, error)
+	RenameTag(ctx context.Context, id uint, title string) (*models.Tag, error)
+	UpdateTag(ctx context.Context, id uint, in dtos.TaxonomyInput) (*models.Tag, error)
+	DeleteTag(ctx context.Context, id, reassignTo uint) error
+	MergeTags(ctx context.Context, sourceIDs []uint, targetID uint) (*models.Tag, repositories.TaxonomyUsage, error)
+	ListTagAliases(ctx context.Context, tagID uint) ([]models.TagAlias, error)
+	AddTagAlias(ctx context.Context, tagID uint, alias string) (*models.TagAlias, error)
+	DeleteTagAlias(ctx context.Context, tagID, aliasID uint) error
+	AutocompleteTags(ctx context.Context, q string, limit int) ([]repositories.TagMatch, error)
+	SuggestPostTags(ctx context.Context, id uint, limit int) (repositories.TagSuggestions, error)
+	SuggestProjectTags(ctx context.Context, id uint, limit int) (repositories.TagSuggestions, error)
+
+	ListCategories(ctx context.Context, q utils.CursorQuery) (repositories.CursorPage[models.Category], map[uint]repositories.TaxonomyUsage, error)
+	GetCategory(ctx context.Context, id uint) (*models.Category, repositories.TaxonomyUsage, error)
+	FindCategoryByTitle(ctx context.Context, title string) (*models.Category, repositories.TaxonomyUsage, error)
+	RenameCategory(ctx context.Context, id uint, title string) (*models.Category, error)
+	UpdateCategory(ctx context.Context, id uint, in dtos.TaxonomyInput) (*models.Category, error)
+	DeleteCategory(ctx context.Context, id, reassignTo uint) error
+	MergeCategories(ctx context.Context, sourceIDs []uint, targetID uint) (*models.Category, repositories.TaxonomyUsage, error)
+	MoveCategory(ctx context.Context, id uint, in dtos.CategoryMoveInput) (*models.Category, error)
+}
+
+// Padrão e teto de itens no autocomplete e nas sugestões de tags
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+)
+
+type taxonomyService struct {
+	db *gorm.DB
+}
+
+func NewTaxonomyService(db *gorm.DB) TaxonomyService {
+	return &taxonomyService{db: db}
+}
+
+func (s *taxonomyService) ListTags(ctx context.Context, q utils.CursorQuery) (repositories.CursorPage[models.Tag], map[uint]repositories.TaxonomyUsage, error) {
+	repo := repositories.NewTagRepository(s.db).WithContext(ctx)
+	page, err := repo.FindPage(q)
+	if err != nil {
+		return page, nil, err
+	}
+	ids := make([]uint, 0, len(page.Items))
+	for _, t := range page.Items {
+		ids = append(ids, t.ID)
+	}
+	usage, err := repo.UsageCounts(ids)
+	return page, usage, err
+}
+
+func (s *taxonomyService) GetTag(ctx context.Context, id uint) (*models.Tag, repositories.TaxonomyUsage, error) {
+	repo := repositories.NewTagRepository(s.db).WithContext(ctx)
+	tag, err := repo.FindByID(id)
+	return tagWithUsage(repo, tag, err)
+}
+
+func (s *taxonomyService) FindTagByTitle(ctx context.Context, title string) (*models.Tag, repositories.TaxonomyUsage, error) {
+	repo := repositories.NewTagRepository(s.db).WithContext(ctx)
+	tag, err := repo.Resolve(title)
+	return tagWithUsage(repo, tag, err)
+}
+
+func (s *taxonomyService) ListTagAliases(ctx context.Context, tagID uint) ([]models.TagAlias, error) {
+	return repositories.NewTagRepository(s.db).WithContext(ctx).Aliases(tagID)
+}
+
+// O alias passa a resolver para a tag em tag_names e no lookup por título
+func (s *taxonomyService) AddTagAlias(ctx context.Context, tagID uint, alias string) (*models.TagAlias, error) {
+	if validators.NormalizeTitle(alias) == "" {
+		return nil, &ValidationError{Field: "alias", Message: "campo obrigatório"}
+	}
+	return repositories.NewTagRepository(s.db).WithContext(ctx).AddAlias(tagID, alias)
+}
+
+func (s *taxonomyService) AutocompleteTags(ctx context.Context, q string, limit int) ([]repositories.TagMatch, error) {
+	return repositories.NewTagRepository(s.db).WithContext(ctx).Autocomplete(q, suggestionLimit(limit))
+}
+
+// Sugestões a partir da cópia de trabalho (com o rascunho pendente, se houver),
+// para o editor escolher antes do ReplaceTags
+func (s *taxonomyService) SuggestPostTags(ctx context.Context, id uint, limit int) (repositories.TagSuggestions, error) {
+	tx := s.db.WithContext(ctx)
+	post, _, err := loadPostWorkingCopy(tx, id)
+	if err != nil {
+		return repositories.TagSuggestions{}, err
+	}
+	text := suggestionText(post.Title, post.ShortDescription, post.Body)
+	return repositories.NewTagRepository(tx).Suggest(text, tagIDs(post.Tags), suggestionLimit(limit))
+}
+
+func (s *taxonomyService) SuggestProjectTags(ctx context.Context, id uint, limit int) (repositories.TagSuggestions, error) {
+	tx := s.db.WithContext(ctx)
+	project, _, err := loadProjectWorkingCopy(tx, id)
+	if err != nil {
+		return repositories.TagSuggestions{}, err
+	}
+	text := suggestionText(project.Title, project.ShortDescription, project.Body)
+	return repositories.NewTagRepository(tx).Suggest(text, tagIDs(project.Tags), suggestionLimit(limit))
+}
+
+func (s *taxonomyService) DeleteTagAlias(ctx context.Context, tagID, aliasID uint) error {
+	return repositories.NewTagRepository(s.db).WithContext(ctx).DeleteAlias(tagID, aliasID)
+}
+
+// Sem reassignTo os itens apenas perdem a tag. Webhooks recebem tag.deleted.
+func (s *taxonomyService) DeleteTag(ctx context.Context, id, reassignTo uint) error {
+	if reassignTo == id {
+		return &ValidationError{Field: "reassign_to", Message: "deve ser outra tag"}
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if reassignTo > 0 {
+			if _, err := loadTags(tx, []uint{reassignTo}); err != nil {
+				return referenceField(err, "reassign_to")
+			}
+		}
+		if err := repositories.NewTagRepository(tx).Delete(id, reassignTo); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, events.Event{Type: events.TagDeleted, ContentType: "tag", ContentID: id})
+	})
+}
+
+// Funde as origens no destino; cada origem removida gera um tag.deleted
+func (s *taxonomyService) MergeTags(ctx context.Context, sourceIDs []uint, targetID uint) (*models.Tag, repositories.TaxonomyUsage, error) {
+	sourceIDs = uniqueIDs(sourceIDs)
+	if err := validateMerge(sourceIDs, targetID); err != nil {
+		return nil, repositories.TaxonomyUsage{}, err
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := loadTags(tx, sourceIDs); err != nil {
+			return referenceField(err, "source_ids")
+		}
+		if _, err := loadTags(tx, []uint{targetID}); err != nil {
+			return referenceField(err, "target_id")
+		}
+		if err := repositories.NewTagRepository(tx).Merge(sourceIDs, targetID); err != nil {
+			return err
+		}
+		for _, id := range sourceIDs {
+			if err := enqueueEvent(tx, events.Event{Type: events.TagDeleted, ContentType: "tag", ContentID: id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, repositories.TaxonomyUsage{}, err
+	}
+	return s.GetTag(ctx, targetID)
+}
+
+func (s *taxonomyService) ListCategories(ctx context.Context, q utils.CursorQuery) (repositories.CursorPage[models.Category], map[uint]repositories.TaxonomyUsage, error) {
+	repo := repositories.NewCategoryRepository(s.db).WithContext(ctx)
+	page, err := repo.FindPage(q)
+	if err != nil {
+		return page, nil, err
+	}
+	ids := make([]uint, 0, len(page.Items))
+	for _, c := range page.Items {
+		ids = append(ids, c.ID)
+	}
+	usage, err := repo.UsageCounts(ids)
+	return page, usage, err
+}
+
+func (s *taxonomyService) GetCategory(ctx context.Context, id uint) (*models.Category, repositories.TaxonomyUsage, error) {
+	repo := repositories.NewCategoryRepository(s.db).WithContext(ctx)
+	category, err := repo.FindByID(id)
+	return categoryWithUsage(repo, category, err)
+}
+
+func (s *taxonomyService) FindCategoryByTitle(ctx context.Context, title string) (*models.Category, repositories.TaxonomyUsage, error) {
+	repo := repositories.NewCategoryRepository(s.db).WithContext(ctx)
+	category, err := repo.FindByTitle(title)
+	return categoryWithUsage(repo, category, err)
+}
+
+func (s *taxonomyService) DeleteCategory(ctx context.Context, id, reassignTo uint) error {
+	if reassignTo == id {
+		return &ValidationError{Field: "reassign_to", Message: "deve ser outra categoria"}
+	}
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if reassignTo > 0 {
+			if _, err := loadCategories(tx, []uint{reassignTo}); err != nil {
+				return referenceField(err, "reassign_to")
+			}
+		}
+		if err := repositories.NewCategoryRepository(tx).Delete(id, reassignTo); err != nil {
+			return err
+		}
+		return enqueueEvent(tx, events.Event{Type: events.CategoryDeleted, ContentType: "category", ContentID: id})
+	})
+}
+
+func (s *taxonomyService) MergeCategories(ctx context.Context, sourceIDs []uint, targetID uint) (*models.Category, repositories.TaxonomyUsage, error) {
+	sourceIDs = uniqueIDs(sourceIDs)
+	if err := validateMerge(sourceIDs, targetID); err != nil {
+		return nil, repositories.TaxonomyUsage{}, err
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := loadCategories(tx, sourceIDs); err != nil {
+			return referenceField(err, "source_ids")
+		}
+		if _, err := loadCategories(tx, []uint{targetID}); err != nil {
+			return referenceField(err, "target_id")
+		}
+		if err := repositories.NewCategoryRepository(tx).Merge(sourceIDs, targetID); err != nil {
+			return err
+		}
+		for _, id := range sourceIDs {
+			if err := enqueueEvent(tx, events.Event{Type: events.CategoryDeleted, ContentType: "category", ContentID: id}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, repositories.TaxonomyUsage{}, err
+	}
+	return s.GetCategory(ctx, targetID)
+}
+
+// Muda o pai e/ou a posição entre as irmãs. Webhooks recebem category.moved,
+// já que os breadcrumbs da subárvore mudam.
+func (s *taxonomyService) MoveCategory(ctx context.Context, id uint, in dtos.CategoryMoveInput) (*models.Category, error) {
+	position := -1 // Fim da lista
+	if in.Position != nil {
+		if *in.Position < 0 {
+			return nil, &ValidationError{Field: "position", Message: "não pode ser negativa"}
+		}
+		position = *in.Position
+	}
+	if in.ParentID != nil && *in.ParentID == id {
+		return nil, &ValidationError{Field: "parent_id", Message: "não pode ser a própria categoria"}
+	}
+
+	var moved *models.Category
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewCategoryRepository(tx)
+		if _, err := repo.FindByID(id); err != nil {
+			return err
+		}
+		if in.ParentID != nil {
+			if _, err := loadCategories(tx, []uint{*in.ParentID}); err != nil {
+				return referenceField(err, "parent_id")
+			}
+		}
+		if err := repo.Move(id, in.ParentID, position); err != nil {
+			if errors.Is(err, repositories.ErrCategoryCycle) {
+				return &ValidationError{Field: "parent_id", Message: "não pode ser uma subcategoria dela"}
+			}
+			return err
+		}
+
+		category, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		moved = category
+		return enqueueEvent(tx, events.Event{Type: events.CategoryMoved, ContentType: "category", ContentID: id, Title: category.Title, Slug: category.Slug})
+	})
+	return moved, err
+}
+
+// Renomeia e avisa os webhooks: o título aparece em todos os conteúdos associados
+func (s *taxonomyService) RenameTag(ctx context.Context, id uint, title string) (*models.Tag, error) {
+	var renamed *models.Tag
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewTagRepository(tx)
+		if err := repo.UpdateName(id, title); err != nil {
+			return err
+		}
+
+		tags, err := repo.FindByIDs([]uint{id})
+		if err != nil {
+			return err
+		}
+		renamed = &tags[0]
+		return enqueueEvent(tx, events.Event{Type: events.TagRenamed, ContentType: "tag", ContentID: id, Title: title, Slug: renamed.Slug})
+	})
+	return renamed, err
+}
+
+// Título, slug, descrição e SEO. Mudança de título ou slug avisa os webhooks com
+// o slug anterior, para revalidar a página antiga.
+func (s *taxonomyService) UpdateTag(ctx context.Context, id uint, in dtos.TaxonomyInput) (*models.Tag, error) {
+	var updated *models.Tag
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewTagRepository(tx)
+		tag, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		previous := *tag
+		titleChanged := tag.Title != in.Title
+		if tag.Slug, err = resolveSlug(tag.Slug, tag.ID, titleChanged, in.Title, in.Slug, repo.SlugExists); err != nil {
+			return err
+		}
+		tag.Title = in.Title
+		tag.Description = in.Description
+		tag.MetaTitle = in.MetaTitle
+		tag.MetaDescription = in.MetaDescription
+		if err := repo.Update(tag); err != nil {
+			return err
+		}
+		updated = tag
+
+		if !titleChanged && previous.Slug == tag.Slug {
+			return nil
+		}
+		event := events.Event{Type: events.TagRenamed, ContentType: "tag", ContentID: id, Title: tag.Title, Slug: tag.Slug}
+		if previous.Slug != tag.Slug {
+			event.PreviousSlug = previous.Slug
+		}
+		return enqueueEvent(tx, event)
+	})
+	return updated, err
+}
+
+func (s *taxonomyService) RenameCategory(ctx context.Context, id uint, title string) (*models.Category, error) {
+	var renamed *models.Category
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewCategoryRepository(tx)
+		if err := repo.UpdateName(id, title); err != nil {
+			return err
+		}
+
+		categories, err := repo.FindByIDs([]uint{id})
+		if err != nil {
+			return err
+		}
+		renamed = &categories[0]
+		return enqueueEvent(tx, events.Event{Type: events.CategoryRenamed, ContentType: "category", ContentID: id, Title: title, Slug: renamed.Slug})
+	})
+	return renamed, err
+}
+
+// Título, slug, descrição e SEO. Mudança de título ou slug avisa os webhooks com
+// o slug anterior, para revalidar a página antiga.
+func (s *taxonomyService) UpdateCategory(ctx context.Context, id uint, in dtos.TaxonomyInput) (*models.Category, error) {
+	var updated *models.Category
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo := repositories.NewCategoryRepository(tx)
+		category, err := repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		previous := *category
+		titleChanged := category.Title != in.Title
+		if category.Slug, err = resolveSlug(category.Slug, category.ID, titleChanged, in.Title, in.Slug, repo.SlugExists); err != nil {
+			return err
+		}
+		category.Title = in.Title
+		category.Description = in.Description
+		category.MetaTitle = in.MetaTitle
+		category.MetaDescription = in.MetaDescription
+		if err := repo.Update(category); err != nil {
+			return err
+		}
+		updated = category
+
+		if !titleChanged && previous.Slug == category.Slug {
+			return nil
+		}
+		event := events.Event{Type: events.CategoryRenamed, ContentType: "category", ContentID: id, Title: category.Title, Slug: category.Slug}
+		if previous.Slug != category.Slug {
+			event.PreviousSlug = previous.Slug
+		}
+		return enqueueEvent(tx, event)
+	})
+	return updated, err
+}
+
+// Sem limite (ou com um inválido) usa o padrão; acima do teto, o teto
+func suggestionLimit(limit int) int {
+	if limit <= 0 {
+		return defaultSuggestionLimit
+	}
+	return min(limit, maxSuggestionLimit)
+}
+
+// O mesmo texto que vai para o índice de busca
+func suggestionText(title, shortDescription, body string) string {
+	return title + "\n" + shortDescription + "\n" + validators.StripHTML(body)
+}
+
+// Completa a busca de uma tag com a contagem de uso dela
+func tagWithUsage(repo repositories.TagRepository, tag *models.Tag, err error) (*models.Tag, repositories.TaxonomyUsage, error) {
+	if err != nil {
+		return nil, repositories.TaxonomyUsage{}, err
+	}
+	usage, err := repo.UsageCounts([]uint{tag.ID})
+	return tag, usage[tag.ID], err
+}
+
+func categoryWithUsage(repo repositories.CategoryRepository, category *models.Category, err error) (*models.Category, repositories.TaxonomyUsage, error) {
+	if err != nil {
+		return nil, repositories.TaxonomyUsage{}, err
+	}
+	usage, err := repo.UsageCounts([]uint{category.ID})
+	return category, usage[category.ID], err
+}
+
+func validateMerge(sourceIDs []uint, targetID uint) error {
+	if len(sourceIDs) == 0 {
+		return &ValidationError{Field: "source_ids", Message: "campo obrigatório"}
+	}
+	if slices.Contains(sourceIDs, targetID) {
+		return &ValidationError{Field: "target_id", Message: "não pode estar entre as origens"}
+	}
+	return nil
+}
+
+// Ids inexistentes entre os informados viram erro de validação do campo (422),
+// deixando o 404 para o item da rota
+func referenceField(err error, field string) error {
+	var refErr *InvalidReferenceError
+	if errors.As(err, &refErr) {
+		refErr.Field = field
+	}
+	return err
+}
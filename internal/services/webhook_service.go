@@ -0,0 +1,65 @@
+package services
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type WebhookService interface {
+	List(ctx context.Context) ([]models.WebhookSubscription, error)
+	Create(ctx context.Context, in dtos.WebhookInput) (*models.WebhookSubscription, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+type webhookService struct {
+	webhooks repositories.WebhookRepository
+}
+
+func NewWebhookService(db *gorm.DB) WebhookService {
+	return &webhookService{webhooks: repositories.NewWebhookRepository(db)}
+}
+
+func (s *webhookService) List(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.webhooks.WithContext(ctx).FindSubscriptions()
+}
+
+// Sem secret informado, geramos um aleatório (devolvido apenas nesta resposta)
+func (s *webhookService) Create(ctx context.Context, in dtos.WebhookInput) (*models.WebhookSubscription, error) {
+	for _, t := range in.Events {
+		if !events.IsValidType(t) {
+			return nil, &ValidationError{Field: "events", Message: "evento desconhecido: " + t}
+		}
+	}
+
+	secret := in.Secret
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	sub := &models.WebhookSubscription{
+		URL:    in.URL,
+		Secret: secret,
+		Events: strings.Join(in.Events, ","),
+		Active: true,
+	}
+	if err := s.webhooks.WithContext(ctx).CreateSubscription(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, id uint) error {
+	return s.webhooks.WithContext(ctx).DeleteSubscription(id)
+}
@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func outboxEvents(db *gorm.DB) []events.Event {
+	var deliveries []models.WebhookDelivery
+	db.Order("id asc").Find(&deliveries)
+
+	res := make([]events.Event, 0, len(deliveries))
+	for _, d := range deliveries {
+		var e events.Event
+		json.Unmarshal([]byte(d.Payload), &e)
+		res = append(res, e)
+	}
+	return res
+}
+
+func TestWebhookOutbox(t *testing.T) {
+	db := SetupTestDB()
+	ctx := context.Background()
+	webhooks := services.NewWebhookService(db)
+	posts := services.NewPostService(db)
+	taxonomies := services.NewTaxonomyService(db)
+
+	t.Run("Deve gerar secret e recusar eventos desconhecidos", func(t *testing.T) {
+		sub, err := webhooks.Create(ctx, dtos.WebhookInput{URL: "https://site.dev/api/revalidate"})
+		assert.NoError(t, err)
+		assert.Len(t, sub.Secret, 64)
+
+		_, err = webhooks.Create(ctx, dtos.WebhookInput{URL: "https://site.dev/x", Events: []string{"content.exploded"}})
+		var valErr *services.ValidationError
+		assert.ErrorAs(t, err, &valErr)
+	})
+
+	t.Run("Deve gravar eventos do ciclo de vida no outbox", func(t *testing.T) {
+		post, err := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "Olá Mundo", Body: "corpo"})
+		assert.NoError(t, err)
+
+		post, err = posts.Update(ctx, post.ID, dtos.ContentInput{Type: "post", Title: "Olá Pessoal", Body: "corpo"})
+		assert.NoError(t, err)
+
+		approve(ctx, posts.Transition, post.ID)
+		past := time.Now().UTC().Add(-time.Minute)
+		_, err = posts.SetPostedAt(ctx, post.ID, &past)
+		assert.NoError(t, err)
+
+		assert.NoError(t, posts.Delete(ctx, post.ID))
+
+		got := outboxEvents(db)
+		if assert.Len(t, got, 4) {
+			assert.Equal(t, events.ContentCreated, got[0].Type)
+			assert.Equal(t, events.ContentUpdated, got[1].Type)
+			assert.Equal(t, "ola-mundo", got[1].PreviousSlug)
+			assert.Equal(t, "ola-pessoal", got[1].Slug)
+			assert.Equal(t, events.ContentPublished, got[2].Type)
+			assert.Equal(t, events.ContentDeleted, got[3].Type)
+		}
+	})
+
+	t.Run("Não deve gravar evento quando a transação falha", func(t *testing.T) {
+		db.Where("1 = 1").Delete(&models.WebhookDelivery{})
+
+		_, err := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "Tag Fantasma", Body: "corpo", TagIDs: []uint{42}})
+		assert.Error(t, err)
+		assert.Empty(t, outboxEvents(db))
+	})
+
+	t.Run("Deve emitir evento ao renomear tag", func(t *testing.T) {
+		db.Where("1 = 1").Delete(&models.WebhookDelivery{})
+		tag := models.Tag{Title: "Golang"}
+		db.Create(&tag)
+
+		renamed, err := taxonomies.RenameTag(ctx, tag.ID, "Go")
+		assert.NoError(t, err)
+		assert.Equal(t, "Go", renamed.Title)
+
+		got := outboxEvents(db)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, events.TagRenamed, got[0].Type)
+			assert.Equal(t, "Go", got[0].Title)
+		}
+	})
+
+	t.Run("Deve informar o slug anterior ao editar categoria", func(t *testing.T) {
+		db.Where("1 = 1").Delete(&models.WebhookDelivery{})
+		category := models.Category{Title: "Back-end"}
+		db.Create(&category)
+
+		updated, err := taxonomies.UpdateCategory(ctx, category.ID, dtos.TaxonomyInput{Title: "Back-end", Slug: "Servidor", Description: "APIs"})
+		assert.NoError(t, err)
+		assert.Equal(t, "servidor", updated.Slug)
+		assert.Equal(t, "APIs", updated.Description)
+
+		_, err = taxonomies.UpdateCategory(ctx, category.ID, dtos.TaxonomyInput{Title: "Back-end", Description: "Só APIs"})
+		assert.NoError(t, err, "sem slug e sem troca de título mantém o atual")
+
+		got := outboxEvents(db)
+		if assert.Len(t, got, 1, "edição só de descrição não avisa os webhooks") {
+			assert.Equal(t, events.CategoryRenamed, got[0].Type)
+			assert.Equal(t, "servidor", got[0].Slug)
+			assert.Equal(t, "back-end", got[0].PreviousSlug)
+		}
+
+		other := models.Category{Title: "Front-end"}
+		db.Create(&other)
+		_, err = taxonomies.UpdateCategory(ctx, other.ID, dtos.TaxonomyInput{Title: "Front-end", Slug: "servidor"})
+		assert.ErrorIs(t, err, repositories.ErrDuplicateSlug)
+	})
+
+	t.Run("Assinatura filtrada só recebe os eventos escolhidos", func(t *testing.T) {
+		db.Where("1 = 1").Delete(&models.WebhookSubscription{})
+		db.Where("1 = 1").Delete(&models.WebhookDelivery{})
+		_, err := webhooks.Create(ctx, dtos.WebhookInput{URL: "https://site.dev/feed", Events: []string{"content.deleted"}})
+		assert.NoError(t, err)
+
+		post, _ := posts.Create(ctx, dtos.ContentInput{Type: "post", Title: "Filtrado", Body: "corpo"})
+		assert.NoError(t, posts.Delete(ctx, post.ID))
+
+		got := outboxEvents(db)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, events.ContentDeleted, got[0].Type)
+		}
+	})
+
+	t.Run("Deve retornar ErrNotFound ao renomear categoria inexistente", func(t *testing.T) {
+		_, err := taxonomies.RenameCategory(ctx, 999, "Nada")
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestWebhookService_Delete(t *testing.T) {
+	// Arquivo em vez de memória para que a pragma valha em todas as conexões
+	db, _ := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "cms.db")+"?_foreign_keys=on"), &gorm.Config{})
+	models.Migrate(db)
+	ctx := context.Background()
+	webhooks := services.NewWebhookService(db)
+	repo := repositories.NewWebhookRepository(db)
+
+	t.Run("Deve remover assinatura com entregas já feitas", func(t *testing.T) {
+		sub, err := webhooks.Create(ctx, dtos.WebhookInput{URL: "https://site.dev/api/revalidate"})
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Enqueue(events.Event{Type: events.ContentCreated}))
+		assert.NoError(t, repo.Enqueue(events.Event{Type: events.ContentDeleted}))
+
+		due, _ := repo.FindDue(time.Now().UTC().Add(time.Minute), 10)
+		if assert.Len(t, due, 2) {
+			assert.NoError(t, repo.MarkDelivered(due[0].ID, time.Now().UTC()))
+		}
+
+		assert.NoError(t, webhooks.Delete(ctx, sub.ID))
+
+		var remaining int64
+		db.Model(&models.WebhookDelivery{}).Count(&remaining)
+		assert.Zero(t, remaining)
+		assert.ErrorIs(t, webhooks.Delete(ctx, sub.ID), repositories.ErrNotFound)
+	})
+}
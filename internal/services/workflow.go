@@ -0,0 +1,95 @@
+package services
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"context"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type transition struct {
+	from, to string
+}
+
+var (
+	anyRole       = []string{auth.RoleEditor, auth.RoleReviewer, auth.RoleAdmin}
+	reviewerRoles = []string{auth.RoleReviewer, auth.RoleAdmin}
+	adminRoles    = []string{auth.RoleAdmin}
+)
+
+// Transições permitidas e os papéis que podem executá-las. As que envolvem
+// scheduled/published acontecem apenas via SetPostedAt.
+var transitions = map[transition][]string{
+	{models.StatusDraft, models.StatusInReview}:    anyRole,
+	{models.StatusInReview, models.StatusDraft}:    reviewerRoles, // Devolvido para ajustes
+	{models.StatusInReview, models.StatusApproved}: reviewerRoles,
+	{models.StatusApproved, models.StatusDraft}:    anyRole,
+
+	{models.StatusApproved, models.StatusScheduled}:  reviewerRoles,
+	{models.StatusApproved, models.StatusPublished}:  reviewerRoles,
+	{models.StatusScheduled, models.StatusPublished}: reviewerRoles,
+	{models.StatusPublished, models.StatusScheduled}: reviewerRoles,
+	{models.StatusScheduled, models.StatusApproved}:  reviewerRoles, // PostedAt removido
+	{models.StatusPublished, models.StatusApproved}:  reviewerRoles, // PostedAt removido
+
+	{models.StatusApproved, models.StatusArchived}:  adminRoles,
+	{models.StatusScheduled, models.StatusArchived}: adminRoles,
+	{models.StatusPublished, models.StatusArchived}: adminRoles,
+	{models.StatusArchived, models.StatusDraft}:     adminRoles,
+}
+
+// Estados que passaram pela aprovação e por isso aceitam SetPostedAt
+var approvedStatuses = []string{models.StatusApproved, models.StatusScheduled, models.StatusPublished}
+
+// Estados que guardam o PostedAt. Ao voltar deles para os demais (ex.: published →
+// approved, archived → draft) ele é removido, para o item não seguir no ar.
+var postedStatuses = []string{models.StatusScheduled, models.StatusPublished, models.StatusArchived}
+
+// Sem Principal no contexto (jobs e chamadas internas) a transição é liberada
+func checkTransition(ctx context.Context, from, to string) error {
+	roles, ok := transitions[transition{from, to}]
+	if !ok {
+		return &TransitionError{From: from, To: to}
+	}
+	return checkRole(ctx, roles)
+}
+
+// Publicar ou descartar o rascunho de um item no ar muda (ou mantém) a versão
+// pública sem passar pela máquina de estados, então exige os mesmos papéis de
+// quem aprova e agenda
+func checkDraftReview(ctx context.Context) error {
+	return checkRole(ctx, reviewerRoles)
+}
+
+func checkRole(ctx context.Context, roles []string) error {
+	if p, ok := auth.FromContext(ctx); ok && !slices.Contains(roles, p.Role) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// Estado resultante de um agendamento em conteúdo aprovado
+func statusForPostedAt(postedAt *time.Time, now time.Time) string {
+	switch {
+	case postedAt == nil:
+		return models.StatusApproved
+	case postedAt.After(now):
+		return models.StatusScheduled
+	default:
+		return models.StatusPublished
+	}
+}
+
+func recordStateChange(ctx context.Context, tx *gorm.DB, contentType string, id uint, from, to, note string) error {
+	actor := models.SystemActor
+	if p, ok := auth.FromContext(ctx); ok {
+		actor = p.Name
+	}
+	return repositories.NewStateChangeRepository(tx).Create(&models.StateChange{
+		ContentType: contentType, ContentID: id, From: from, To: to, Actor: actor, Note: note,
+	})
+}
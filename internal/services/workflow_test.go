@@ -0,0 +1,186 @@
+package services_test
+
+import (
+	"cms-headless/internal/auth"
+	"cms-headless/internal/dtos"
+	"cms-headless/internal/events"
+	"cms-headless/internal/models"
+	"cms-headless/internal/repositories"
+	"cms-headless/internal/services"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflow(t *testing.T) {
+	db := SetupTestDB()
+	posts := services.NewPostService(db)
+	projects := services.NewProjectService(db)
+	editor := auth.WithPrincipal(context.Background(), auth.Principal{Name: "ana", Role: auth.RoleEditor})
+	reviewer := auth.WithPrincipal(context.Background(), auth.Principal{Name: "bia", Role: auth.RoleReviewer})
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Name: "caio", Role: auth.RoleAdmin})
+	past := time.Now().UTC().Add(-time.Hour)
+
+	post, _ := posts.Create(editor, dtos.ContentInput{Type: "post", Title: "Fluxo", Body: "corpo"})
+
+	t.Run("Conteúdo novo nasce como draft e não pode ser agendado", func(t *testing.T) {
+		assert.Equal(t, models.StatusDraft, post.Status)
+
+		_, err := posts.SetPostedAt(admin, post.ID, &past)
+		var transErr *services.TransitionError
+		assert.ErrorAs(t, err, &transErr)
+	})
+
+	t.Run("Editor envia para revisão mas não aprova", func(t *testing.T) {
+		updated, err := posts.Transition(editor, post.ID, models.StatusInReview, "pronto para revisão")
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusInReview, updated.Status)
+
+		_, err = posts.Transition(editor, post.ID, models.StatusApproved, "")
+		assert.ErrorIs(t, err, services.ErrForbidden)
+	})
+
+	t.Run("Revisor aprova e publica", func(t *testing.T) {
+		_, err := posts.Transition(reviewer, post.ID, models.StatusApproved, "")
+		assert.NoError(t, err)
+
+		published, err := posts.SetPostedAt(reviewer, post.ID, &past)
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusPublished, published.Status)
+	})
+
+	t.Run("Deve recusar transições fora do fluxo", func(t *testing.T) {
+		_, err := posts.Transition(admin, post.ID, models.StatusInReview, "")
+		var transErr *services.TransitionError
+		assert.ErrorAs(t, err, &transErr)
+
+		_, err = posts.Transition(admin, post.ID, models.StatusPublished, "")
+		assert.ErrorAs(t, err, &transErr, "publicação só via posted_at")
+	})
+
+	t.Run("Arquivar exige admin e tira o conteúdo do ar", func(t *testing.T) {
+		_, err := posts.Transition(reviewer, post.ID, models.StatusArchived, "")
+		assert.ErrorIs(t, err, services.ErrForbidden)
+
+		db.Create(&models.WebhookSubscription{URL: "https://site.dev/revalidate", Secret: "s", Active: true})
+		archived, err := posts.Transition(admin, post.ID, models.StatusArchived, "fora de linha")
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusArchived, archived.Status)
+
+		got := outboxEvents(db)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, events.ContentUnpublished, got[0].Type)
+		}
+	})
+
+	t.Run("Deve registrar o histórico com autor e nota", func(t *testing.T) {
+		changes, err := posts.StateChanges(admin, post.ID)
+
+		assert.NoError(t, err)
+		if assert.Len(t, changes, 4) {
+			assert.Equal(t, models.StatusDraft, changes[0].From)
+			assert.Equal(t, models.StatusInReview, changes[0].To)
+			assert.Equal(t, "ana", changes[0].Actor)
+			assert.Equal(t, "pronto para revisão", changes[0].Note)
+			assert.Equal(t, models.StatusPublished, changes[2].To)
+			assert.Equal(t, "caio", changes[3].Actor)
+		}
+	})
+
+	t.Run("Deve filtrar a listagem por estado", func(t *testing.T) {
+		project, _ := projects.Create(editor, dtos.ContentInput{Type: "project", Title: "Projeto", Body: "x"})
+		approve(admin, projects.Transition, project.ID)
+
+		list, total, err := projects.List(admin, 1, 10, models.StatusApproved)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, project.ID, list[0].ID)
+
+		_, total, _ = projects.List(admin, 1, 10, models.StatusDraft)
+		assert.Equal(t, int64(0), total)
+	})
+}
+
+func TestWorkflow_BackToApproved(t *testing.T) {
+	db := SetupTestDB()
+	posts := services.NewPostService(db)
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Name: "caio", Role: auth.RoleAdmin})
+	past := time.Now().UTC().Add(-time.Hour)
+
+	post, _ := posts.Create(admin, dtos.ContentInput{Type: "post", Title: "No Ar", Body: "corpo"})
+	approve(admin, posts.Transition, post.ID)
+	posts.SetPostedAt(admin, post.ID, &past)
+	db.Create(&models.WebhookSubscription{URL: "https://site.dev/revalidate", Secret: "s", Active: true})
+
+	t.Run("Deve tirar do ar ao voltar de published para approved", func(t *testing.T) {
+		approved, err := posts.Transition(admin, post.ID, models.StatusApproved, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusApproved, approved.Status)
+		assert.Nil(t, approved.PostedAt)
+
+		_, err = repositories.NewPostRepository(db).FindBySlug("no-ar", true)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+
+		got := outboxEvents(db)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, events.ContentUnpublished, got[0].Type)
+		}
+	})
+
+	t.Run("Deve remover o PostedAt ao reabrir um arquivado como draft", func(t *testing.T) {
+		posts.SetPostedAt(admin, post.ID, &past)
+		posts.Transition(admin, post.ID, models.StatusArchived, "")
+
+		draft, err := posts.Transition(admin, post.ID, models.StatusDraft, "")
+
+		assert.NoError(t, err)
+		assert.Nil(t, draft.PostedAt)
+		_, err = repositories.NewPostRepository(db).FindBySlug("no-ar", true)
+		assert.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestWorkflow_DraftChanges(t *testing.T) {
+	db := SetupTestDB()
+	posts := services.NewPostService(db)
+	projects := services.NewProjectService(db)
+	editor := auth.WithPrincipal(context.Background(), auth.Principal{Name: "ana", Role: auth.RoleEditor})
+	reviewer := auth.WithPrincipal(context.Background(), auth.Principal{Name: "bia", Role: auth.RoleReviewer})
+	past := time.Now().UTC().Add(-time.Hour)
+
+	post, _ := posts.Create(reviewer, dtos.ContentInput{Type: "post", Title: "No Ar", Body: "publicado"})
+	approve(reviewer, posts.Transition, post.ID)
+	posts.SetPostedAt(reviewer, post.ID, &past)
+	posts.Update(editor, post.ID, dtos.ContentInput{Type: "post", Title: "No Ar", Body: "editado"})
+
+	project, _ := projects.Create(reviewer, dtos.ContentInput{Type: "project", Title: "Projeto No Ar", Body: "publicado"})
+	approve(reviewer, projects.Transition, project.ID)
+	projects.SetPostedAt(reviewer, project.ID, &past)
+	projects.Update(editor, project.ID, dtos.ContentInput{Type: "project", Title: "Projeto No Ar", Body: "editado"})
+
+	t.Run("Editor não publica nem descarta o rascunho de um item no ar", func(t *testing.T) {
+		_, err := posts.PublishChanges(editor, post.ID)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		_, err = posts.DiscardChanges(editor, post.ID)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		_, err = projects.PublishChanges(editor, project.ID)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+		_, err = projects.DiscardChanges(editor, project.ID)
+		assert.ErrorIs(t, err, services.ErrForbidden)
+
+		live, _ := repositories.NewPostRepository(db).FindByID(post.ID)
+		assert.Equal(t, "publicado", live.Body)
+	})
+
+	t.Run("Revisor publica o rascunho", func(t *testing.T) {
+		published, err := posts.PublishChanges(reviewer, post.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "editado", published.Body)
+
+		_, err = projects.DiscardChanges(reviewer, project.ID)
+		assert.NoError(t, err)
+	})
+}
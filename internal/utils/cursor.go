@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// Posição do último item entregue numa listagem ordenada por (tempo DESC, id DESC).
+// Time nil representa itens sem data (ex.: rascunhos sem PostedAt), que vêm por último.
+type Cursor struct {
+	Time *time.Time `json:"t,omitempty"`
+	ID   uint       `json:"id"`
+}
+
+// Parâmetros de uma página por cursor; After nil é a primeira página
+type CursorQuery struct {
+	After     *Cursor
+	Limit     int
+	WithTotal bool // COUNT(*) é opcional: rolagem infinita não precisa do total
+}
+
+// Cursor opaco para o cliente: JSON em base64 URL-safe
+func EncodeCursor(c Cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Vazio retorna nil (primeira página)
+func DecodeCursor(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Ordena por (timeColumn DESC, idColumn DESC), com nulos no fim, e continua depois
+// de after. Diferente do OFFSET, o custo não cresce com a profundidade e itens
+// publicados no meio da rolagem não deslocam as páginas seguintes.
+func Keyset(timeColumn, idColumn string, after *Cursor) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		// "IS NULL" ordena false antes de true tanto no SQLite quanto no Postgres
+		db = db.Order(timeColumn + " IS NULL").Order(timeColumn + " DESC").Order(idColumn + " DESC")
+		if after == nil {
+			return db
+		}
+		if after.Time == nil {
+			return db.Where(timeColumn+" IS NULL AND "+idColumn+" < ?", after.ID)
+		}
+		return db.Where(clause.Or(
+			clause.Expr{
+				SQL:  timeColumn + " < ? OR (" + timeColumn + " = ? AND " + idColumn + " < ?)",
+				Vars: []any{*after.Time, *after.Time, after.ID},
+			},
+			clause.Expr{SQL: timeColumn + " IS NULL"},
+		))
+	}
+}
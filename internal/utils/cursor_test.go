@@ -0,0 +1,56 @@
+package utils_test
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/utils"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("Deve ida e volta preservando tempo e id", func(t *testing.T) {
+		at := time.Date(2024, 5, 1, 10, 30, 0, 123456789, time.UTC)
+		token := utils.EncodeCursor(utils.Cursor{Time: &at, ID: 42})
+
+		c, err := utils.DecodeCursor(token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint(42), c.ID)
+		assert.True(t, at.Equal(*c.Time))
+	})
+
+	t.Run("Deve tratar vazio como primeira página e rejeitar lixo", func(t *testing.T) {
+		c, err := utils.DecodeCursor("")
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+
+		for _, token := range []string{"%%%", "bm9wZQ", utils.EncodeCursor(utils.Cursor{})} {
+			_, err := utils.DecodeCursor(token)
+			assert.ErrorIs(t, err, utils.ErrInvalidCursor, token)
+		}
+	})
+}
+
+func TestKeyset(t *testing.T) {
+	db := setupMinimalDB()
+
+	t.Run("Primeira página só ordena, sem OFFSET", func(t *testing.T) {
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			return tx.Model(&models.Post{}).Scopes(utils.Keyset("posted_at", "id", nil)).Limit(10).Find(&[]models.Post{})
+		})
+
+		assert.Contains(t, sql, "ORDER BY posted_at IS NULL,posted_at DESC,id DESC")
+		assert.NotContains(t, sql, "OFFSET")
+	})
+
+	t.Run("Cursor sem data continua só entre os itens sem data", func(t *testing.T) {
+		sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			return tx.Model(&models.Post{}).Scopes(utils.Keyset("posted_at", "id", &utils.Cursor{ID: 7})).Find(&[]models.Post{})
+		})
+
+		assert.Contains(t, sql, "posted_at IS NULL AND id < 7")
+	})
+}
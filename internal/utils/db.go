@@ -3,6 +3,7 @@ package utils
 import (
 	"log"
 	"os"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
@@ -32,5 +33,11 @@ func InitDB() *gorm.DB {
 		log.Fatalf("Falha ao conectar no banco (%s): %v", env, err)
 	}
 
+	// Prazo máximo por statement; o contexto da requisição pode encurtá-lo
+	timeout := QueryTimeoutFromEnv(os.Getenv("DB_QUERY_TIMEOUT"), 5*time.Second)
+	if err := db.Use(QueryTimeout{Timeout: timeout}); err != nil {
+		log.Fatalf("Falha ao registrar timeout de consultas: %v", err)
+	}
+
 	return db
 }
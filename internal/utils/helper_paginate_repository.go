@@ -2,18 +2,24 @@ package utils
 
 import "gorm.io/gorm"
 
+// Normaliza page/pageSize com os mesmos limites usados na paginação do repositório
+func NormalizePagination(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	switch {
+	case pageSize > 500:
+		pageSize = 500
+	case pageSize <= 0:
+		pageSize = 50
+	}
+	return page, pageSize
+}
+
 // Helper para paginação segura
 func PaginateRepository(page, pageSize int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
-		if page <= 0 {
-			page = 1
-		}
-		switch {
-		case pageSize > 500:
-			pageSize = 500
-		case pageSize <= 0:
-			pageSize = 50
-		}
+		page, pageSize := NormalizePagination(page, pageSize)
 		offset := (page - 1) * pageSize
 		return db.Offset(offset).Limit(pageSize)
 	}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	queryTimeoutCancelKey = "utils:query_timeout_cancel"
+	queryTimeoutParentKey = "utils:query_timeout_parent"
+)
+
+// Plugin do GORM que aplica um prazo máximo a cada statement executado.
+// O prazo é derivado do contexto da chamada (db.WithContext), então um cancelamento
+// do cliente ou um deadline menor definido pelo chamador continuam valendo.
+type QueryTimeout struct {
+	Timeout time.Duration
+}
+
+func (QueryTimeout) Name() string {
+	return "utils:query_timeout"
+}
+
+func (p QueryTimeout) Initialize(db *gorm.DB) error {
+	if p.Timeout <= 0 {
+		return nil
+	}
+
+	// O Statement é compartilhado pela cadeia (ex.: query.Count(&total) seguido de
+	// query.Find), então o contexto original volta ao lugar ao fim de cada
+	// statement; senão o próximo herdaria o prazo já cancelado.
+	start := func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		ctx := parent
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryTimeoutCancelKey, cancel)
+		tx.InstanceSet(queryTimeoutParentKey, parent)
+	}
+	finish := func(tx *gorm.DB) {
+		if cancel, ok := tx.InstanceGet(queryTimeoutCancelKey); ok {
+			cancel.(context.CancelFunc)()
+		}
+		if parent, ok := tx.InstanceGet(queryTimeoutParentKey); ok {
+			ctx, _ := parent.(context.Context)
+			tx.Statement.Context = ctx
+		}
+	}
+
+	// Row() fica de fora: o *sql.Rows devolvido ainda é lido depois dos callbacks
+	cb := db.Callback()
+	for _, register := range []func(string, func(*gorm.DB)) error{
+		cb.Create().Before("*").Register,
+		cb.Query().Before("*").Register,
+		cb.Update().Before("*").Register,
+		cb.Delete().Before("*").Register,
+		cb.Raw().Before("*").Register,
+	} {
+		if err := register("utils:query_timeout_start", start); err != nil {
+			return err
+		}
+	}
+	for _, register := range []func(string, func(*gorm.DB)) error{
+		cb.Create().After("*").Register,
+		cb.Query().After("*").Register,
+		cb.Update().After("*").Register,
+		cb.Delete().After("*").Register,
+		cb.Raw().After("*").Register,
+	} {
+		if err := register("utils:query_timeout_finish", finish); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lê DB_QUERY_TIMEOUT (ex.: "3s"); valores inválidos caem no padrão
+func QueryTimeoutFromEnv(raw string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return d
+	}
+	return def
+}
@@ -0,0 +1,63 @@
+package utils_test
+
+import (
+	"cms-headless/internal/models"
+	"cms-headless/internal/utils"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	db, _ := gorm.Open(sqlite.Open(""), &gorm.Config{})
+	db.AutoMigrate(&models.Tag{})
+	assert.NoError(t, db.Use(utils.QueryTimeout{Timeout: 2 * time.Second}))
+
+	// Captura o contexto efetivamente usado pelo statement
+	var captured context.Context
+	db.Callback().Query().Before("gorm:query").Register("test:capture", func(tx *gorm.DB) {
+		captured = tx.Statement.Context
+	})
+
+	t.Run("Deve aplicar prazo a cada consulta e liberá-lo ao final", func(t *testing.T) {
+		var tags []models.Tag
+		err := db.Find(&tags).Error
+		assert.NoError(t, err)
+
+		deadline, ok := captured.Deadline()
+		assert.True(t, ok, "a consulta deveria ter deadline")
+		assert.WithinDuration(t, time.Now().Add(2*time.Second), deadline, time.Second)
+		assert.ErrorIs(t, captured.Err(), context.Canceled, "o cancel deveria ser chamado após a consulta")
+	})
+
+	t.Run("Deve preservar um deadline menor vindo do chamador", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		var tags []models.Tag
+		db.WithContext(ctx).Find(&tags)
+
+		deadline, _ := captured.Deadline()
+		parent, _ := ctx.Deadline()
+		assert.Equal(t, parent, deadline)
+	})
+
+	t.Run("Deve interromper consultas com contexto cancelado", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var tags []models.Tag
+		err := db.WithContext(ctx).Find(&tags).Error
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestQueryTimeoutFromEnv(t *testing.T) {
+	assert.Equal(t, 3*time.Second, utils.QueryTimeoutFromEnv("3s", time.Second))
+	assert.Equal(t, time.Second, utils.QueryTimeoutFromEnv("", time.Second))
+	assert.Equal(t, time.Second, utils.QueryTimeoutFromEnv("abc", time.Second))
+}
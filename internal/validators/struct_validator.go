@@ -0,0 +1,109 @@
+package validators
+
+import (
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Erro de validação associado a um campo do JSON de entrada
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Valida uma struct a partir das tags `binding` e `validate` já declaradas nos DTOs.
+// Regras suportadas: required, min=N, max=N, oneof=a b c e url.
+// Campos vazios só falham se forem required: `validate:"url"` vale para URLs informadas.
+func ValidateStruct(v any) []FieldError {
+	val := reflect.Indirect(reflect.ValueOf(v))
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	typ := val.Type()
+
+	var errs []FieldError
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		rules := joinRules(field.Tag.Get("binding"), field.Tag.Get("validate"))
+		if len(rules) == 0 {
+			continue
+		}
+
+		name := jsonName(field)
+		if msg := checkRules(val.Field(i), rules); msg != "" {
+			errs = append(errs, FieldError{Field: name, Message: msg})
+		}
+	}
+	return errs
+}
+
+func joinRules(tags ...string) []string {
+	var rules []string
+	for _, tag := range tags {
+		for _, rule := range strings.Split(tag, ",") {
+			if rule = strings.TrimSpace(rule); rule != "" {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+// Retorna a primeira regra violada (mensagem vazia se válido)
+func checkRules(fv reflect.Value, rules []string) string {
+	s, isString := "", fv.Kind() == reflect.String
+	if isString {
+		s = strings.TrimSpace(fv.String())
+	}
+
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if fv.IsZero() || (isString && s == "") {
+				return "campo obrigatório"
+			}
+		case "min", "max":
+			n, err := strconv.Atoi(param)
+			if err != nil || !isString || s == "" {
+				continue
+			}
+			length := utf8.RuneCountInString(s)
+			if name == "min" && length < n {
+				return "deve ter no mínimo " + param + " caracteres"
+			}
+			if name == "max" && length > n {
+				return "deve ter no máximo " + param + " caracteres"
+			}
+		case "oneof":
+			if !isString || s == "" {
+				continue
+			}
+			if !slices.Contains(strings.Fields(param), s) {
+				return "deve ser um dos valores: " + strings.Join(strings.Fields(param), ", ")
+			}
+		case "url":
+			if isString && s != "" && !isURL(s) {
+				return "deve ser uma URL válida"
+			}
+		}
+	}
+	return ""
+}
+
+func isURL(s string) bool {
+	u, err := url.ParseRequestURI(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -1,16 +1,126 @@
 package validators
 
 import (
+	"fmt"
+	"html"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/text/unicode/norm"
 )
 
+// Tamanho máximo do slug (sufixos de desambiguação "-N" incluídos)
+const MaxSlugLength = 80
+
+// Slugs que colidiriam com rotas do Next.js/da API
+var ReservedSlugs = []string{
+	"admin", "api", "new", "edit", "draft", "drafts", "preview",
+	"search", "tags", "categories", "feed", "rss", "sitemap", "page",
+}
+
+// Letras latinas que não se decompõem em base + acento no NFD
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d",
+	'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i",
+}
+
+// Usado quando o título não tem nenhum caractere aproveitável (ex.: "!!!")
+const fallbackSlug = "conteudo"
+
 func SanitizeHTML(input string) string {
 	p := bluemonday.UGCPolicy() // Protege contra XSS
 	return p.Sanitize(input)
 }
 
+// Texto puro do HTML, com os espaços normalizados (usado pelo índice de busca)
+func StripHTML(input string) string {
+	p := bluemonday.StrictPolicy()
+	p.AddSpaceWhenStrippingTag(true) // "<p>a</p><p>b</p>" vira "a b", não "ab"
+	return strings.Join(strings.Fields(html.UnescapeString(p.Sanitize(input))), " ")
+}
+
+// "Programação em Go: Parte 1!" -> "programacao-em-go-parte-1"
 func GenerateSlug(title string) string {
-	return strings.ToLower(strings.ReplaceAll(title, " ", "-"))
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(title)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Acento separado pelo NFD: descartamos
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case transliterations[r] != "":
+			b.WriteString(transliterations[r])
+			dash = false
+		default:
+			// Espaços, pontuação e qualquer caractere não seguro para URL viram um único "-"
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := truncateSlug(b.String(), MaxSlugLength)
+	if slug == "" {
+		return fallbackSlug
+	}
+	return slug
+}
+
+// "  Programação  EM Go " -> "programacao em go". Chave de unicidade de títulos:
+// ignora caixa, acentos e espaços extras, mas mantém a pontuação (C++ e C# são
+// tags diferentes, embora o slug das duas seja "c")
+func NormalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(title) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func IsReservedSlug(slug string) bool {
+	return slices.Contains(ReservedSlugs, slug)
+}
+
+// Resolve colisões acrescentando "-2", "-3"... até exists devolver false.
+// Slugs reservados são tratados como ocupados.
+func UniqueSlug(base string, exists func(slug string) (bool, error)) (string, error) {
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			suffix := fmt.Sprintf("-%d", n)
+			candidate = truncateSlug(base, MaxSlugLength-len(suffix)) + suffix
+		}
+		if IsReservedSlug(candidate) {
+			continue
+		}
+
+		taken, err := exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+}
+
+// Corta no limite preferindo a fronteira de uma palavra e sem deixar "-" nas pontas
+func truncateSlug(slug string, max int) string {
+	if len(slug) > max {
+		cut := slug[:max]
+		if i := strings.LastIndexByte(cut, '-'); i > max/2 {
+			cut = cut[:i]
+		}
+		slug = cut
+	}
+	return strings.Trim(slug, "-")
 }
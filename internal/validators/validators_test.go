@@ -0,0 +1,83 @@
+package validators_test
+
+import (
+	"cms-headless/internal/validators"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSlug(t *testing.T) {
+	cases := map[string]string{
+		"Programação em Go: Parte 1!": "programacao-em-go-parte-1",
+		"Olá,   Mundo --- Novo":       "ola-mundo-novo",
+		"  Çà et là  ":                "ca-et-la",
+		"Straße & Ærø":                "strasse-aero",
+		"Go 1.22 / Generics?":         "go-1-22-generics",
+		"!!!":                         "conteudo",
+	}
+	for title, want := range cases {
+		assert.Equal(t, want, validators.GenerateSlug(title), title)
+	}
+
+	t.Run("Títulos que diferem só na pontuação geram o mesmo slug", func(t *testing.T) {
+		assert.Equal(t, validators.GenerateSlug("Go, rápido!"), validators.GenerateSlug("Go rápido"))
+	})
+
+	t.Run("Deve respeitar o tamanho máximo cortando na fronteira de palavra", func(t *testing.T) {
+		slug := validators.GenerateSlug(strings.Repeat("palavra ", 30))
+		assert.LessOrEqual(t, len(slug), validators.MaxSlugLength)
+		assert.False(t, strings.HasSuffix(slug, "-"))
+		assert.True(t, strings.HasSuffix(slug, "palavra"))
+	})
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	cases := map[string]string{
+		"Go":                  "go",
+		"  GO  ":              "go",
+		"Programação   em Go": "programacao em go",
+		"Ação\tRápida":        "acao rapida",
+		"C++":                 "c++",
+		"C#":                  "c#",
+	}
+	for title, want := range cases {
+		assert.Equal(t, want, validators.NormalizeTitle(title), title)
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	taken := map[string]bool{"meu-post": true, "meu-post-2": true}
+	exists := func(slug string) (bool, error) { return taken[slug], nil }
+
+	t.Run("Deve acrescentar o próximo sufixo livre", func(t *testing.T) {
+		slug, err := validators.UniqueSlug("meu-post", exists)
+		assert.NoError(t, err)
+		assert.Equal(t, "meu-post-3", slug)
+	})
+
+	t.Run("Deve manter o slug base quando livre", func(t *testing.T) {
+		slug, _ := validators.UniqueSlug("outro", exists)
+		assert.Equal(t, "outro", slug)
+	})
+
+	t.Run("Deve tratar palavras reservadas como ocupadas", func(t *testing.T) {
+		slug, _ := validators.UniqueSlug("admin", exists)
+		assert.Equal(t, "admin-2", slug)
+	})
+
+	t.Run("Sufixo não deve estourar o tamanho máximo", func(t *testing.T) {
+		base := strings.Repeat("a", validators.MaxSlugLength)
+		taken[base] = true
+		slug, _ := validators.UniqueSlug(base, exists)
+		assert.Len(t, slug, validators.MaxSlugLength)
+		assert.True(t, strings.HasSuffix(slug, "-2"))
+	})
+
+	t.Run("Deve propagar erro da verificação", func(t *testing.T) {
+		_, err := validators.UniqueSlug("x", func(string) (bool, error) { return false, errors.New("db fora") })
+		assert.Error(t, err)
+	})
+}